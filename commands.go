@@ -0,0 +1,451 @@
+// MakeaTUI command-line subcommands
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/app"
+	"github.com/makeatui/makeatui/internal/ui/preview"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/contrast"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/palette"
+	"github.com/makeatui/makeatui/pkg/render"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/muesli/termenv"
+)
+
+// loadCanvas reads a design saved as JSON
+func loadCanvas(path string) (*schema.Canvas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var canvas schema.Canvas
+	if err := json.Unmarshal(data, &canvas); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &canvas, nil
+}
+
+// runLint implements `makeatui lint [--config file] [--json] design.json`
+func runLint(args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	configPath := fs.String("config", "", "lint config file (JSON)")
+	asJSON := fs.Bool("json", false, "print diagnostics as JSON")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: makeatui lint [--config file] [--json] design.json")
+		return 2
+	}
+
+	cfg := lint.DefaultConfig()
+	if *configPath != "" {
+		var err error
+		if cfg, err = lint.LoadConfig(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	canvas, err := loadCanvas(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	diags := lint.New(cfg).Run(canvas)
+	if *asJSON {
+		data, _ := json.MarshalIndent(diags, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, d := range diags {
+			fmt.Println(d.String())
+		}
+		fmt.Printf("%d error(s), %d warning(s)\n",
+			lint.Count(diags, lint.SeverityError), lint.Count(diags, lint.SeverityWarning))
+	}
+
+	if lint.Count(diags, lint.SeverityError) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runPreview implements `makeatui preview [--sizes 80x24,...] [--theme name] [--color] [--out dir] design.json`
+func runPreview(args []string) int {
+	fs := flag.NewFlagSet("preview", flag.ExitOnError)
+	sizeList := fs.String("sizes", "80x24,120x40,60x20", "terminal sizes, comma-separated")
+	themeName := fs.String("theme", "", "theme to render with (defaults to the design's theme)")
+	color := fs.Bool("color", false, "keep colors in the snapshots")
+	outDir := fs.String("out", "", "write a snapshot file for each size into this directory")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: makeatui preview [--sizes 80x24,...] [--theme name] [--color] [--out dir] design.json")
+		return 2
+	}
+	sizes, err := preview.ParseSizes(*sizeList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	canvas, err := loadCanvas(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *themeName == "" {
+		*themeName = canvas.Theme
+	}
+
+	// Plain text unless asked, so that snapshots compare the same everywhere
+	if !*color {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	errs := 0
+	base := strings.TrimSuffix(filepath.Base(fs.Arg(0)), filepath.Ext(fs.Arg(0)))
+	for _, snap := range preview.Snapshots(canvas, sizes, styles.Lookup(*themeName)) {
+		errs += lint.Count(snap.Issues, lint.SeverityError)
+		text := snap.View + "\n"
+		for _, d := range snap.Issues {
+			text += d.String() + "\n"
+		}
+
+		if *outDir == "" {
+			fmt.Println(text)
+			continue
+		}
+		path := filepath.Join(*outDir, fmt.Sprintf("%s-%dx%d.txt", base, snap.Size.Width, snap.Size.Height))
+		if err := os.MkdirAll(*outDir, 0o755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		fmt.Printf("%s → %s\n", snap.Title(), path)
+	}
+
+	if errs > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runRender implements `makeatui render [--format ansi|plain|html] [--size 80x24] [--theme name] [--out file] design.json`
+func runRender(args []string) int {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	formatName := fs.String("format", "ansi", "output format: ansi, plain or html")
+	sizeName := fs.String("size", "", "terminal size, such as 80x24 (defaults to the design's size)")
+	themeName := fs.String("theme", "", "theme to render with (defaults to the design's theme)")
+	out := fs.String("out", "", "write to this file instead of the standard output")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: makeatui render [--format ansi|plain|html] [--size 80x24] [--theme name] [--out file] design.json")
+		return 2
+	}
+	format, err := render.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	canvas, err := loadCanvas(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	size := render.Size(canvas)
+	if *sizeName != "" {
+		sizes, err := preview.ParseSizes(*sizeName)
+		if err != nil || len(sizes) != 1 {
+			fmt.Fprintf(os.Stderr, "Error: invalid size %q, want WIDTHxHEIGHT\n", *sizeName)
+			return 2
+		}
+		size = sizes[0]
+	}
+	if *themeName == "" {
+		*themeName = canvas.Theme
+	}
+
+	// The same colors whatever the terminal, or none for plain text
+	if format == render.FormatPlain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	} else {
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	}
+
+	text, err := render.Render(canvas, format, size, styles.Lookup(*themeName))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if *out == "" {
+		fmt.Print(text)
+		return 0
+	}
+	if err := os.WriteFile(*out, []byte(text), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	return 0
+}
+
+// runContrast implements `makeatui contrast [--level AA|AAA] [--theme name] [--json] [design.json]`
+func runContrast(args []string) int {
+	fs := flag.NewFlagSet("contrast", flag.ExitOnError)
+	level := fs.String("level", "AA", "WCAG level: AA or AAA")
+	themeName := fs.String("theme", "", "theme to check (defaults to the design's theme)")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	_ = fs.Parse(args)
+
+	var canvas *schema.Canvas
+	if fs.NArg() > 0 {
+		var err error
+		if canvas, err = loadCanvas(fs.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		if *themeName == "" {
+			*themeName = canvas.Theme
+		}
+	}
+
+	wcag, err := contrast.ParseLevel(*level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	report := contrast.Check(canvas, styles.Lookup(*themeName), contrast.Options{
+		Level:        wcag,
+		IncludeTheme: true,
+	})
+
+	if *asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printContrastReport(report)
+	}
+
+	if len(report.Failures()) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printContrastReport(report contrast.Report) {
+	fmt.Printf("Theme %s, WCAG %s\n\n", report.Theme, report.Level)
+
+	for _, p := range report.Pairs {
+		status := "pass"
+		if !p.Pass {
+			status = "FAIL"
+		}
+		owner := "theme"
+		if p.ComponentID != "" {
+			owner = p.Component
+		}
+		line := fmt.Sprintf("%-4s %5.2f:1 (needs %.1f)  %s: %s %s on %s",
+			status, p.Ratio, p.Required, owner, p.Role, p.Foreground, p.Background)
+		if p.Suggestion != "" {
+			line += "  → try " + p.Suggestion
+		}
+		fmt.Println(line)
+	}
+
+	for _, profile := range []contrast.Profile{contrast.Profile256, contrast.Profile16} {
+		fails := report.DownsampleFailures(profile)
+		if len(fails) == 0 {
+			continue
+		}
+		fmt.Printf("\nPassing pairs that fail on %s-color terminals:\n", profile)
+		for _, p := range fails {
+			ratio := p.Ratio256
+			if profile == contrast.Profile16 {
+				ratio = p.Ratio16
+			}
+			fmt.Printf("  %s %s on %s: %.2f:1\n", p.Role, p.Foreground, p.Background, ratio)
+		}
+	}
+
+	if len(report.Collapses) > 0 {
+		fmt.Println("\nDistinct colors that collapse into one:")
+		for _, c := range report.Collapses {
+			fmt.Println("  " + c.String())
+		}
+	}
+
+	fmt.Printf("\n%d of %d pairs fail\n", len(report.Failures()), len(report.Pairs))
+}
+
+// runTheme implements `makeatui theme list|import|export`
+func runTheme(args []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, `usage:
+  makeatui theme list
+  makeatui theme import [--name name] file
+  makeatui theme export name file.json|file.toml
+  makeatui theme generate [--mode dark|light] [--level AA|AAA] [--name name] [--out file] seed`)
+		return 2
+	}
+	if len(args) == 0 {
+		return usage()
+	}
+
+	switch args[0] {
+	case "list":
+		for _, name := range styles.Names() {
+			fmt.Println(name)
+		}
+		fmt.Printf("\nUser themes: %s\n", styles.ThemeDir())
+		return 0
+
+	case "import":
+		fs := flag.NewFlagSet("theme import", flag.ExitOnError)
+		name := fs.String("name", "", "name for the imported theme (single-scheme files only)")
+		_ = fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			return usage()
+		}
+		themes, err := styles.LoadFile(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if *name != "" && len(themes) == 1 {
+			themes[0].Name = *name
+		}
+		for _, t := range themes {
+			path, err := styles.SaveUserTheme(t)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			fmt.Printf("Imported %s → %s\n", t.Name, path)
+		}
+		return 0
+
+	case "export":
+		if len(args) != 3 {
+			return usage()
+		}
+		t, ok := styles.Get(args[1])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown theme %q\n", args[1])
+			return 1
+		}
+		if err := styles.SaveFile(t, args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+
+	case "generate":
+		return runThemeGenerate(args[1:], usage)
+	}
+	return usage()
+}
+
+// runThemeGenerate writes a theme derived from a seed color, into the
+// user theme directory unless --out names a file
+func runThemeGenerate(args []string, usage func() int) int {
+	fs := flag.NewFlagSet("theme generate", flag.ExitOnError)
+	mode := fs.String("mode", "dark", "dark or light")
+	level := fs.String("level", "AA", "WCAG level every pair must meet: AA or AAA")
+	name := fs.String("name", "", "theme name (derived from the seed by default)")
+	out := fs.String("out", "", "write to this .json or .toml file instead of the theme directory")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return usage()
+	}
+
+	wcag, err := contrast.ParseLevel(*level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	t, err := palette.Generate(fs.Arg(0), palette.Options{
+		Name:  *name,
+		Mode:  palette.Mode(strings.ToLower(*mode)),
+		Level: wcag,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	path := *out
+	if path == "" {
+		path, err = styles.SaveUserTheme(t)
+	} else {
+		err = styles.SaveFile(t, path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Generated %s → %s\n", t.Name, path)
+	return 0
+}
+
+// runKeys implements `makeatui keys [--preset name]`: it lists the key
+// bindings, those of the keys file unless a preset is named, and fails
+// when the keys file has mistakes
+func runKeys(args []string) int {
+	fs := flag.NewFlagSet("keys", flag.ExitOnError)
+	preset := fs.String("preset", "", "show a preset instead: "+strings.Join(app.KeyPresets, ", "))
+	_ = fs.Parse(args)
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "usage: makeatui keys [--preset name]")
+		return 2
+	}
+
+	var k app.KeyMap
+	var err error
+	if *preset != "" {
+		k, err = app.PresetKeyMap(*preset)
+	} else {
+		k, err = app.ReadKeyMap(app.KeysPath())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	width := 0
+	for _, b := range k.Bindings() {
+		width = max(width, len(b.Action))
+	}
+	for _, b := range k.Bindings() {
+		var names []string
+		for _, key := range b.Keys {
+			if key == " " {
+				key = "space"
+			}
+			names = append(names, key)
+		}
+		keys := strings.Join(names, ", ")
+		if keys == "" {
+			keys = "-"
+		}
+		fmt.Printf("%-*s  %-16s %s\n", width, b.Action, keys, b.Desc)
+	}
+	if *preset == "" {
+		fmt.Printf("\nKeys file: %s\n", app.KeysPath())
+	}
+	return 0
+}
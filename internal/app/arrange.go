@@ -0,0 +1,186 @@
+// Package app - Align, distribute and group
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// arrangeEntries are the actions of the arrange menu
+var arrangeEntries = []menuEntry{
+	{id: "align-left", label: "Align left"},
+	{id: "align-center", label: "Align center"},
+	{id: "align-right", label: "Align right"},
+	{id: "align-top", label: "Align top"},
+	{id: "align-middle", label: "Align middle"},
+	{id: "align-bottom", label: "Align bottom"},
+	{id: "distribute-h", label: "Distribute across"},
+	{id: "distribute-v", label: "Distribute down"},
+}
+
+// openArrangeMenu offers the arrange actions next to the cursor, which
+// need at least two components selected
+func (m *Model) openArrangeMenu(x, y int) {
+	n := len(m.canvas.SelectedIndices())
+	if n < 2 {
+		m.message = "Select two or more components to arrange"
+		return
+	}
+	entries := make([]menuEntry, len(arrangeEntries))
+	for i, e := range arrangeEntries {
+		e.disabled = strings.HasPrefix(e.id, "distribute") && n < 3
+		entries[i] = e
+	}
+	m.openMenu(entries, x, y)
+}
+
+// align lines the selected components up along one side or the middle
+// of the rectangle around them
+func (m *Model) align(how string) {
+	pos, size := m.selectionBounds()
+	var edits []edit
+	for _, c := range m.selection() {
+		x, y := c.Position.X, c.Position.Y
+		switch how {
+		case "left":
+			x = pos.X
+		case "center":
+			x = pos.X + (size.Width-c.Size.Width)/2
+		case "right":
+			x = pos.X + size.Width - c.Size.Width
+		case "top":
+			y = pos.Y
+		case "middle":
+			y = pos.Y + (size.Height-c.Size.Height)/2
+		case "bottom":
+			y = pos.Y + size.Height - c.Size.Height
+		}
+		if x != c.Position.X || y != c.Position.Y {
+			edits = append(edits, edit{agent.CmdMoveComponent, agent.MoveComponentParams{ID: c.ID, X: x, Y: y}})
+		}
+	}
+	if len(edits) > 0 {
+		_ = m.execEdits(false, edits...)
+	}
+}
+
+// distribute spaces the selected components evenly between the first
+// and the last of them, across or down
+func (m *Model) distribute(across bool) {
+	comps := m.selection()
+	if len(comps) < 3 {
+		return
+	}
+	start := func(c schema.Component) int {
+		if across {
+			return c.Position.X
+		}
+		return c.Position.Y
+	}
+	length := func(c schema.Component) int {
+		if across {
+			return c.Size.Width
+		}
+		return c.Size.Height
+	}
+	sort.SliceStable(comps, func(i, j int) bool { return start(comps[i]) < start(comps[j]) })
+
+	// Share the free space between the gaps, the remainder going to
+	// the first ones
+	first, last := comps[0], comps[len(comps)-1]
+	free := start(last) + length(last) - start(first)
+	for _, c := range comps {
+		free -= length(c)
+	}
+	gaps := len(comps) - 1
+
+	var edits []edit
+	at := start(first)
+	for i, c := range comps {
+		if i > 0 {
+			gap := free / gaps
+			if i <= free%gaps {
+				gap++
+			}
+			at += gap
+		}
+		x, y := c.Position.X, c.Position.Y
+		if across {
+			x = at
+		} else {
+			y = at
+		}
+		if x != c.Position.X || y != c.Position.Y {
+			edits = append(edits, edit{agent.CmdMoveComponent, agent.MoveComponentParams{ID: c.ID, X: x, Y: y}})
+		}
+		at += length(c)
+	}
+	if len(edits) > 0 {
+		_ = m.execEdits(false, edits...)
+	}
+}
+
+// groupSelected moves the selected components into a new container
+// and selects it
+func (m *Model) groupSelected() {
+	comps := m.selection()
+	if len(comps) == 0 {
+		return
+	}
+	var ids []string
+	for _, c := range comps {
+		ids = append(ids, c.ID)
+	}
+	if m.exec(agent.CmdGroupComponents, agent.GroupComponentsParams{IDs: ids}) != nil {
+		return
+	}
+	for i, c := range m.canvas.Components {
+		if len(c.Children) > 0 && c.Children[0].ID == ids[0] {
+			m.canvas.Select(i)
+		}
+	}
+	m.message = "Grouped " + describe(comps)
+}
+
+// ungroupSelected replaces the selected containers with their children
+// and selects those
+func (m *Model) ungroupSelected() {
+	var edits []edit
+	children := map[string]bool{}
+	for _, c := range m.selection() {
+		if len(c.Children) == 0 {
+			continue
+		}
+		edits = append(edits, edit{agent.CmdUngroup, agent.UngroupParams{ID: c.ID}})
+		for _, child := range c.Children {
+			children[child.ID] = true
+		}
+	}
+	if len(edits) == 0 {
+		m.message = "Nothing to ungroup"
+		return
+	}
+	if m.execEdits(false, edits...) != nil {
+		return
+	}
+	m.selectWhere(func(_ int, c schema.Component) bool { return children[c.ID] })
+}
+
+// runArrangeAction carries out an arrange menu choice, reporting
+// whether id was one
+func (m *Model) runArrangeAction(id string) bool {
+	switch id {
+	case "align-left", "align-center", "align-right", "align-top", "align-middle", "align-bottom":
+		m.align(id[len("align-"):])
+	case "distribute-h":
+		m.distribute(true)
+	case "distribute-v":
+		m.distribute(false)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestArrangeNeedsTwo(t *testing.T) {
+	m := press(newTestModel(t), "enter", "esc") // a box, not selected
+	for _, keys := range [][]string{{"a"}, {"enter", "a"}} {
+		m = press(m, keys...)
+		if m.contextMenu != nil {
+			t.Fatalf("%d selected: opened the arrange menu", len(m.canvas.SelectedIndices()))
+		}
+		if m.message == "" {
+			t.Errorf("%d selected: no message in place of the menu", len(m.canvas.SelectedIndices()))
+		}
+		m = press(m, "up", "k") // used to panic with nothing selectable
+	}
+}
+
+func TestAlignLeft(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "a", X: 4, Y: 2, Width: 10, Height: 3})
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "b", X: 20, Y: 8, Width: 10, Height: 3})
+	m = press(m, "tab", "ctrl+a", "a")
+	if m.contextMenu == nil {
+		t.Fatal("no arrange menu with two selected")
+	}
+	steps := len(m.session.UndoStack)
+
+	// Align left comes first; choosing it sends back its action
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter chose nothing")
+	}
+	m = send(next.(Model), cmd())
+	for _, c := range m.canvas.Components {
+		if c.Position.X != 4 {
+			t.Errorf("%s at x %d after aligning left, want 4", c.Name, c.Position.X)
+		}
+	}
+	if got := len(m.session.UndoStack) - steps; got != 1 {
+		t.Errorf("aligning took %d undo steps, want 1", got)
+	}
+}
@@ -0,0 +1,294 @@
+// Package app - Command palette
+package app
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/templates"
+	"github.com/makeatui/makeatui/pkg/widgets/navigation"
+)
+
+// paletteActionMsg is sent with a command's ID when it is chosen in the
+// command palette
+type paletteActionMsg string
+
+// designerCommand is an action of the designer offered in the command
+// palette
+type designerCommand struct {
+	id, label, category string
+	description         string
+	keys                key.Binding // shown beside the label when bound
+	run                 func(m *Model)
+}
+
+// PluginCommand is a command a plugin adds to the designer's command
+// palette. Run gets the design and the IDs of the selected components
+// and returns the session commands that carry it out, which the
+// designer runs as a single undo step.
+type PluginCommand struct {
+	ID          string
+	Label       string
+	Category    string // "Plugin" when empty
+	Description string
+	Run         func(design schema.Canvas, selected []string) ([]agent.Command, error)
+}
+
+var (
+	pluginCommands   []PluginCommand
+	pluginCommandsMu sync.RWMutex
+)
+
+// RegisterCommand adds a plugin command to the command palette,
+// replacing any command with the same ID
+func RegisterCommand(cmd PluginCommand) {
+	pluginCommandsMu.Lock()
+	defer pluginCommandsMu.Unlock()
+	for i, c := range pluginCommands {
+		if c.ID == cmd.ID {
+			pluginCommands[i] = cmd
+			return
+		}
+	}
+	pluginCommands = append(pluginCommands, cmd)
+}
+
+// plugins returns the registered plugin commands
+func plugins() []PluginCommand {
+	pluginCommandsMu.RLock()
+	defer pluginCommandsMu.RUnlock()
+	return append([]PluginCommand(nil), pluginCommands...)
+}
+
+// designerCommands lists every action of the designer, with the plugin
+// commands last
+func (m Model) designerCommands() []designerCommand {
+	cmds := []designerCommand{}
+	add := func(category, id, label string, keys key.Binding, run func(m *Model)) {
+		cmds = append(cmds, designerCommand{id: id, label: label, category: category, keys: keys, run: run})
+	}
+	none := key.Binding{}
+
+	for _, item := range m.components {
+		add("Add", "add-"+string(item.Type), item.Name, none, func(m *Model) {
+			m.addComponent(item.Type, item.Name)
+			m.focus = FocusCanvas
+		})
+	}
+	for _, item := range m.components {
+		add("Draw", "draw-"+string(item.Type), item.Name, none, func(m *Model) {
+			for i, c := range m.components {
+				if c.Type == item.Type {
+					m.selected = i
+				}
+			}
+			m.focus = FocusCanvas
+			m.startDrawing()
+		})
+	}
+
+	add("Edit", "undo", "Undo", keys.Undo, (*Model).undo)
+	add("Edit", "redo", "Redo", keys.Redo, (*Model).redo)
+	add("Edit", "copy", "Copy", keys.Copy, func(m *Model) { m.copySelected() })
+	add("Edit", "cut", "Cut", keys.Cut, (*Model).cutSelected)
+	add("Edit", "paste", "Paste", keys.Paste, (*Model).paste)
+	add("Edit", "duplicate", "Duplicate", keys.Duplicate, (*Model).duplicateSelected)
+	add("Edit", "edit-text", "Edit text", keys.EditText, func(m *Model) {
+		m.focus = FocusCanvas
+		m.startTextEdit()
+	})
+	add("Edit", "delete", "Delete", keys.Delete, (*Model).removeSelected)
+	add("Edit", "move-mode", "Move mode", keys.MoveMod, func(m *Model) { m.toggleMode(canvas.ModeMove) })
+	add("Edit", "resize-mode", "Resize mode", keys.Resize, func(m *Model) { m.toggleMode(canvas.ModeResize) })
+	add("Edit", "design-size", "Design size…", keys.Size, (*Model).openSizePrompt)
+
+	add("Select", "select-all", "All", keys.SelectAll, (*Model).selectAll)
+	add("Select", "select-same", "Same type", keys.SameType, (*Model).selectSameType)
+	add("Select", "select-invert", "Invert", keys.Invert, (*Model).invertSelection)
+	add("Select", "box-select", "Box select", keys.BoxSelect, func(m *Model) {
+		m.focus = FocusCanvas
+		m.startBoxSelect()
+	})
+
+	for _, e := range arrangeEntries {
+		add("Arrange", e.id, e.label, none, func(m *Model) { m.runArrangeAction(e.id) })
+	}
+	add("Arrange", "group", "Group", keys.Group, (*Model).groupSelected)
+	add("Arrange", "ungroup", "Ungroup", keys.Ungroup, (*Model).ungroupSelected)
+	add("Arrange", "forward", "Bring forward", keys.Forward, func(m *Model) { m.restackSelected(1) })
+	add("Arrange", "backward", "Send backward", keys.Backward, func(m *Model) { m.restackSelected(-1) })
+	add("Arrange", "to-front", "Bring to front", keys.ToFront, func(m *Model) { m.restackSelected(1 << 16) })
+	add("Arrange", "to-back", "Send to back", keys.ToBack, func(m *Model) { m.restackSelected(-(1 << 16)) })
+
+	add("View", "snap", "Toggle snap", keys.Snap, (*Model).toggleSnap)
+	add("View", "grid", "Next grid size", keys.Grid, (*Model).cycleGrid)
+	add("View", "layers", "Layers panel", keys.Layers, (*Model).toggleLayers)
+	add("View", "fit", "Fit design to view", keys.Fit, (*Model).toggleFit)
+	add("View", "minimap", "Toggle minimap", keys.Minimap, (*Model).toggleMinimap)
+	add("View", "preview", "Preview", keys.Preview, (*Model).startPreview)
+	add("View", "gallery", "Preview at several sizes", keys.Gallery, (*Model).startGallery)
+	add("View", "help", "Keyboard shortcuts", keys.Help, func(m *Model) { m.showHelp = true })
+
+	add("Lint", "lint", "Run lint", none, (*Model).runLint)
+	add("Lint", "lint-panel", "Toggle lint panel", keys.Lint, func(m *Model) { m.showLint = !m.showLint })
+
+	add("File", "save", "Save", keys.Save, (*Model).saveProject)
+	add("File", "save-as", "Save as…", keys.SaveAs, func(m *Model) { m.openFileDialog(dialogSaveAs) })
+	add("File", "open", "Open…", keys.Open, func(m *Model) {
+		if m.confirmDiscard(shortKey(keys.Open)) {
+			m.openFileDialog(dialogOpen)
+		}
+	})
+	add("File", "quit", "Quit", keys.Quit, func(m *Model) {
+		next, _ := m.quit(false)
+		*m = next.(Model)
+	})
+
+	add("Export", "export-go", "Go code…", keys.Export, func(m *Model) { m.openFileDialog(dialogExport) })
+	add("Export", "snapshot-text", "Plain text snapshot…", none, func(m *Model) { m.openFileDialog(dialogSnapshotText) })
+	add("Export", "snapshot-html", "HTML snapshot…", none, func(m *Model) { m.openFileDialog(dialogSnapshotHTML) })
+
+	add("Theme", "theme-next", "Next theme", keys.Theme, (*Model).cycleTheme)
+	add("Theme", "theme-editor", "Generate from a seed color", keys.ThemeEditor, func(m *Model) {
+		m.themeEditor = newThemeEditor(m.theme)
+		m.applyTheme(m.themeEditor.theme)
+	})
+	for _, t := range styles.All() {
+		add("Theme", "theme:"+t.Name, t.Name, none, func(m *Model) { m.applyTheme(t) })
+	}
+
+	add("Template", "templates", "Browse templates…", keys.Templates, func(m *Model) { m.openTemplates("") })
+	tpls := templates.NewTemplateEngine().List()
+	sort.Slice(tpls, func(i, j int) bool {
+		if tpls[i].Category != tpls[j].Category {
+			return tpls[i].Category < tpls[j].Category
+		}
+		return tpls[i].Name < tpls[j].Name
+	})
+	for _, t := range tpls {
+		add("Template", "template:"+t.Name, t.Name, none, func(m *Model) { m.openTemplates(t.Name) })
+		cmds[len(cmds)-1].description = t.Description
+	}
+
+	for _, p := range plugins() {
+		category := p.Category
+		if category == "" {
+			category = "Plugin"
+		}
+		add(category, "plugin:"+p.ID, p.Label, none, func(m *Model) { m.runPlugin(p) })
+		cmds[len(cmds)-1].description = p.Description
+	}
+	return cmds
+}
+
+// openCommandPalette lists the designer's commands, recently used first
+func (m *Model) openCommandPalette() {
+	m.canvas.StopDrawing()
+	m.drag = nil
+	cp := m.commands
+	cp.SetStyle(m.commandPaletteStyle())
+	cp.Commands = nil
+	for _, c := range m.designerCommands() {
+		shortcut := ""
+		if c.keys.Enabled() {
+			shortcut = c.keys.Help().Key
+		}
+		id := c.id
+		cp.AddCommand(id, c.label, c.description, c.category, shortcut, "", func() tea.Cmd {
+			return func() tea.Msg { return paletteActionMsg(id) }
+		})
+	}
+	cp.Show()
+}
+
+// updateCommandPalette passes keys to the open palette
+func (m Model) updateCommandPalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	_, cmd := m.commands.Update(msg)
+	return m, cmd
+}
+
+// runDesignerCommand carries out the command chosen in the palette
+func (m *Model) runDesignerCommand(id paletteActionMsg) tea.Cmd {
+	for _, c := range m.designerCommands() {
+		if c.id == string(id) {
+			c.run(m)
+			break
+		}
+	}
+	if m.quitting {
+		return tea.Quit
+	}
+	return nil
+}
+
+// runLint opens the lint panel and sums up what it found
+func (m *Model) runLint() {
+	diags := m.diagnostics
+	m.showLint = true
+	m.message = fmt.Sprintf("Lint: %d errors, %d warnings",
+		lint.Count(diags, lint.SeverityError), lint.Count(diags, lint.SeverityWarning))
+}
+
+// runPlugin runs the commands a plugin returns as one undo step
+func (m *Model) runPlugin(p PluginCommand) {
+	cmds, err := p.Run(m.GetCanvasSchema(), m.selectedIDs())
+	if err != nil {
+		m.message = p.Label + ": " + err.Error()
+		return
+	}
+	for i, cmd := range cmds {
+		if i == 0 {
+			err = m.session.Execute(cmd)
+			m.nudging = ""
+		} else {
+			err = m.session.Amend(cmd)
+		}
+		if err != nil {
+			m.message = p.Label + ": " + err.Error()
+			break
+		}
+	}
+	m.syncCanvas()
+}
+
+// selectedIDs returns the IDs of the selected components
+func (m *Model) selectedIDs() []string {
+	var ids []string
+	for _, comp := range m.selection() {
+		ids = append(ids, comp.ID)
+	}
+	return ids
+}
+
+// commandPaletteStyle styles the palette with the theme
+func (m Model) commandPaletteStyle() navigation.CommandPaletteStyle {
+	style := navigation.DefaultCommandPaletteStyle()
+	style.Container = style.Container.Background(m.theme.Surface).BorderForeground(m.theme.Primary)
+	style.Input = style.Input.Background(m.theme.SurfaceLight).Foreground(m.theme.TextPrimary)
+	style.Item = style.Item.Foreground(m.theme.TextPrimary)
+	style.ItemSel = style.ItemSel.Background(m.theme.Primary).Foreground(m.theme.TextPrimary)
+	style.Label = lipgloss.NewStyle().Foreground(m.theme.TextPrimary)
+	style.Description = style.Description.Foreground(m.theme.TextMuted)
+	style.Category = lipgloss.NewStyle().Foreground(m.theme.Secondary)
+	style.Shortcut = lipgloss.NewStyle().Foreground(m.theme.Accent)
+	style.Recent = lipgloss.NewStyle().Foreground(m.theme.TextMuted)
+	style.Empty = style.Empty.Foreground(m.theme.TextMuted)
+	return style
+}
+
+// renderCommandPalette draws the open palette over view, near the top
+func (m Model) renderCommandPalette(view string) string {
+	box := m.commands.View()
+	w, _ := lipgloss.Size(box)
+	return compositor.Overlay(view, box, max((m.width-w)/2, 0), 2)
+}
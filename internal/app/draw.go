@@ -0,0 +1,97 @@
+// Package app - Draw mode
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/pkg/agent"
+)
+
+// startDrawing enters draw mode for the component type picked in the
+// sidebar
+func (m *Model) startDrawing() {
+	m.focus = FocusCanvas
+	m.canvas.Mode = canvas.ModeDraw
+	m.canvas.StopDrawing()
+	m.nudging = ""
+	m.placing = nil
+	m.message = "Drawing a " + m.components[m.selected].Name
+}
+
+// stopDrawing leaves draw mode
+func (m *Model) stopDrawing() {
+	m.canvas.StopDrawing()
+	m.canvas.Mode = canvas.ModeSelect
+	m.placing = nil
+}
+
+// updateDraw handles the keys that draw: space or enter anchors the
+// rectangle at the cursor and then creates the component, and esc
+// drops the anchor or leaves draw mode. It reports whether it took
+// the key; the arrows still move the cursor.
+func (m *Model) updateDraw(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "enter", " ":
+		if m.canvas.Anchor == nil {
+			m.canvas.StartDrawing()
+		} else {
+			m.finishDrawing()
+		}
+	case "esc":
+		if m.canvas.Anchor != nil {
+			m.canvas.StopDrawing()
+		} else {
+			m.stopDrawing()
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// finishDrawing creates the sidebar's component type, or the template
+// being placed, in the rectangle drawn and selects it
+func (m *Model) finishDrawing() {
+	pos, size, ok := m.canvas.DrawRect()
+	placing := m.placing
+	m.stopDrawing()
+	if !ok {
+		return
+	}
+	if placing != nil {
+		m.insertTemplate(placing, pos, size)
+		return
+	}
+	item := m.components[m.selected]
+	err := m.exec(agent.CmdAddComponent, agent.AddComponentParams{
+		Type:   item.Type,
+		Name:   item.Name,
+		X:      pos.X,
+		Y:      pos.Y,
+		Width:  size.Width,
+		Height: size.Height,
+		Text:   item.Name,
+	})
+	if err == nil {
+		m.canvas.Select(len(m.canvas.Components) - 1)
+	}
+}
+
+// drawMouse draws with the mouse: pressing anchors the rectangle,
+// dragging stretches it and releasing creates the component. Corners
+// snap to the grid.
+func (m *Model) drawMouse(msg tea.MouseMsg, x, y int) {
+	x, y = m.snapPoint(x, y)
+	m.canvas.CursorX = min(max(x, 0), m.canvas.Width-1)
+	m.canvas.CursorY = min(max(y, 0), m.canvas.Height-1)
+	switch msg.Action {
+	case tea.MouseActionPress:
+		if msg.Button == tea.MouseButtonLeft {
+			m.canvas.StartDrawing()
+		}
+	case tea.MouseActionRelease:
+		if m.canvas.Anchor != nil {
+			m.finishDrawing()
+		}
+	}
+}
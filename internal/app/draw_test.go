@@ -0,0 +1,67 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestDrawWithKeys(t *testing.T) {
+	m := press(newTestModel(t), "n")
+	if m.canvas.Mode != canvas.ModeDraw || m.focus != FocusCanvas {
+		t.Fatalf("mode %v, focus %v after n, want drawing on the canvas", m.canvas.Mode, m.focus)
+	}
+	kind := m.components[m.selected]
+	x, y := m.canvas.CursorX, m.canvas.CursorY
+
+	m = press(m, "enter", "right", "right", "right", "down", "down", "enter")
+	if len(m.canvas.Components) != 1 {
+		t.Fatalf("%d components after drawing, want 1", len(m.canvas.Components))
+	}
+	c := m.canvas.GetSelected()
+	if c == nil || c.Type != kind.Type {
+		t.Fatalf("drew %+v, want a selected %s", c, kind.Type)
+	}
+	want := schema.Position{X: x, Y: y}
+	size := schema.Size{Width: m.canvas.CursorX - x + 1, Height: m.canvas.CursorY - y + 1}
+	if c.Position != want || c.Size != size {
+		t.Errorf("drew %v %v, want %v %v", c.Position, c.Size, want, size)
+	}
+	if m.canvas.Mode != canvas.ModeSelect {
+		t.Errorf("mode %v after drawing, want select", m.canvas.Mode)
+	}
+	if m = press(m, "u"); len(m.canvas.Components) != 0 {
+		t.Error("undo kept the drawn component")
+	}
+}
+
+func TestDrawCancel(t *testing.T) {
+	m := press(newTestModel(t), "n", "enter", "right", "down", "esc")
+	if m.canvas.Anchor != nil || m.canvas.Mode != canvas.ModeDraw {
+		t.Fatal("esc should drop the anchor and keep drawing")
+	}
+	m = press(m, "esc")
+	if m.canvas.Mode != canvas.ModeSelect || len(m.canvas.Components) != 0 {
+		t.Errorf("mode %v with %d components, want select with none", m.canvas.Mode, len(m.canvas.Components))
+	}
+}
+
+func TestDrawWithMouse(t *testing.T) {
+	m := press(newTestModel(t), "n")
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionPress, 9, 4)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionMotion, 14, 6)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionRelease, 18, 6)
+	if len(m.canvas.Components) != 1 {
+		t.Fatalf("%d components after drawing, want 1", len(m.canvas.Components))
+	}
+	// The corners snap outwards to the 4×2 grid
+	c := m.canvas.Components[0]
+	if want := (schema.Position{X: 8, Y: 4}); c.Position != want {
+		t.Errorf("drawn at %v, want %v", c.Position, want)
+	}
+	if want := (schema.Size{Width: 12, Height: 4}); c.Size != want {
+		t.Errorf("drawn %v, want %v", c.Size, want)
+	}
+}
@@ -3,49 +3,45 @@ package app
 
 import (
 	"os"
+	"strings"
 
 	"github.com/makeatui/makeatui/internal/codegen"
+	"github.com/makeatui/makeatui/pkg/render"
 	"github.com/makeatui/makeatui/pkg/schema"
 )
 
-// Export exports the current canvas to Go code
-func (m *Model) Export(filename string) error {
-	canvas := schema.Canvas{
-		Name:       m.projectName,
-		Width:      m.canvas.Width,
-		Height:     m.canvas.Height,
-		Components: m.canvas.Components,
-		Theme:      m.theme.Name,
-	}
-
-	gen := codegen.NewGenerator(canvas)
+// Export exports the current canvas to Go code, returning what the
+// generator had to work around, such as unknown theme tokens
+func (m *Model) Export(filename string) ([]string, error) {
+	gen := codegen.NewGenerator(m.GetCanvasSchema())
 	code := gen.Generate()
+	return gen.Warnings, os.WriteFile(filename, []byte(code), 0644)
+}
 
-	return os.WriteFile(filename, []byte(code), 0644)
+// Snapshot writes the design, as it looks when its app starts, to a
+// file in format
+func (m *Model) Snapshot(filename string, format render.Format) error {
+	design := m.GetCanvasSchema()
+	text, err := render.Render(&design, format, render.Size(&design), m.theme)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return os.WriteFile(filename, []byte(text), 0644)
 }
 
 // ExportString returns the generated code as a string
 func (m *Model) ExportString() string {
-	canvas := schema.Canvas{
-		Name:       m.projectName,
-		Width:      m.canvas.Width,
-		Height:     m.canvas.Height,
-		Components: m.canvas.Components,
-		Theme:      m.theme.Name,
-	}
-
-	gen := codegen.NewGenerator(canvas)
+	gen := codegen.NewGenerator(m.GetCanvasSchema())
 	return gen.Generate()
 }
 
 // GetCanvasSchema returns the canvas schema for JSON export
 func (m *Model) GetCanvasSchema() schema.Canvas {
-	return schema.Canvas{
-		Name:       m.projectName,
-		Width:      m.canvas.Width,
-		Height:     m.canvas.Height,
-		Components: m.canvas.Components,
-		Theme:      m.theme.Name,
-	}
+	canvas := m.session.Canvas
+	canvas.Name = m.projectName
+	canvas.Theme = m.theme.Name
+	return canvas
 }
-
@@ -0,0 +1,298 @@
+// Package app - Open, save-as and export dialogs
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/render"
+	"github.com/makeatui/makeatui/pkg/widgets/advanced"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+)
+
+const (
+	fileDialogWidth  = 64
+	fileDialogHeight = 18
+)
+
+// dialogMode selects what a file dialog does with the chosen file
+type dialogMode int
+
+const (
+	dialogOpen dialogMode = iota
+	dialogSaveAs
+	dialogExport
+	dialogSnapshotText
+	dialogSnapshotHTML
+)
+
+// browserKeys are the file manager keys a dialog passes through; the
+// rest (delete, paste, ...) have no place in a file dialog
+var browserKeys = map[string]bool{
+	"up": true, "k": true, "down": true, "j": true,
+	"home": true, "end": true, "pgup": true, "pgdown": true,
+	"left": true, "h": true, "backspace": true, ".": true, "~": true,
+}
+
+// fileDialog picks a file with advanced.FileManager. Save-as, export and
+// snapshots also take a file name, which tab switches to.
+type fileDialog struct {
+	mode    dialogMode
+	browser *advanced.FileManager
+	name    *input.TextInput
+	naming  bool   // typing the file name rather than browsing
+	confirm string // path to overwrite on the next enter
+	err     string
+}
+
+// newFileDialog opens a dialog in dir. For save-as and export, file is
+// the suggested file name.
+func newFileDialog(mode dialogMode, dir, file string) *fileDialog {
+	d := &fileDialog{
+		mode:    mode,
+		browser: advanced.NewFileManager("file-dialog", fileDialogWidth-4, fileDialogHeight-6),
+	}
+	d.browser.Filter = "*" + d.ext()
+	d.browser.ShowDetails = false
+	if dir == "" || d.browser.SetDirectory(dir) != nil {
+		_ = d.browser.Refresh()
+	}
+	d.browser.Focus()
+
+	if mode != dialogOpen {
+		d.name = input.NewTextInput("file-name").
+			SetLabel("File name").
+			SetWidth(fileDialogWidth - 10)
+		d.name.SetValue(file)
+		d.name.CursorEnd()
+		d.setNaming(true)
+	}
+	return d
+}
+
+// ext is the extension of the files the dialog works with
+func (d *fileDialog) ext() string {
+	switch d.mode {
+	case dialogExport:
+		return ".go"
+	case dialogSnapshotText:
+		return render.FormatPlain.Ext()
+	case dialogSnapshotHTML:
+		return render.FormatHTML.Ext()
+	}
+	return ".json"
+}
+
+func (d *fileDialog) title() string {
+	switch d.mode {
+	case dialogSaveAs:
+		return "💾 Save design as"
+	case dialogExport:
+		return "📤 Export Go code"
+	case dialogSnapshotText:
+		return "📸 Snapshot as plain text"
+	case dialogSnapshotHTML:
+		return "📸 Snapshot as HTML"
+	}
+	return "📂 Open design"
+}
+
+func (d *fileDialog) setNaming(naming bool) {
+	d.naming = naming
+	if naming {
+		d.browser.Blur()
+		d.name.Focus()
+	} else {
+		d.name.Blur()
+		d.browser.Focus()
+	}
+}
+
+// target returns the path the typed file name refers to
+func (d *fileDialog) target() string {
+	name := strings.TrimSpace(d.name.Value())
+	if name == "" {
+		return ""
+	}
+	if filepath.Ext(name) == "" {
+		name += d.ext()
+	}
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(d.browser.CurrentDir, name)
+}
+
+// updateFileDialog handles keys while a file dialog is open
+func (m Model) updateFileDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.fileDialog
+	key := msg.String()
+	if key != "enter" {
+		d.confirm = ""
+	}
+
+	switch {
+	case key == "esc":
+		m.fileDialog = nil
+		return m, nil
+	case key == "tab" && d.name != nil:
+		d.setNaming(!d.naming)
+		return m, nil
+	case key == "enter" && d.naming:
+		m.finishFileDialog(d.target())
+		return m, nil
+	case key == "enter":
+		entry := d.browser.GetSelected()
+		switch {
+		case entry == nil:
+		case entry.IsDir:
+			_ = d.browser.SetDirectory(entry.Path)
+		case d.mode == dialogOpen:
+			m.finishFileDialog(entry.Path)
+		default:
+			d.name.SetValue(entry.Name)
+			d.name.CursorEnd()
+			d.setNaming(true)
+		}
+		return m, nil
+	}
+
+	if d.naming {
+		d.name.Update(msg)
+	} else if browserKeys[key] {
+		d.browser.Update(msg)
+	}
+	return m, nil
+}
+
+// finishFileDialog opens, saves or exports path and closes the dialog,
+// or keeps it open with an error
+func (m *Model) finishFileDialog(path string) {
+	d := m.fileDialog
+	if path == "" {
+		d.err = "Enter a file name"
+		return
+	}
+
+	if d.mode != dialogOpen && path != m.project.path && d.confirm != path {
+		if _, err := os.Stat(path); err == nil {
+			d.confirm = path
+			d.err = filepath.Base(path) + " exists: press enter again to replace it"
+			return
+		}
+	}
+
+	var err error
+	var warnings []string
+	switch d.mode {
+	case dialogOpen:
+		err = m.load(path)
+	case dialogSaveAs:
+		err = m.save(path)
+	case dialogExport:
+		warnings, err = m.Export(path)
+	case dialogSnapshotText:
+		err = m.Snapshot(path, render.FormatPlain)
+	case dialogSnapshotHTML:
+		err = m.Snapshot(path, render.FormatHTML)
+	}
+	if err != nil {
+		d.err = err.Error()
+		return
+	}
+
+	m.fileDialog = nil
+	switch d.mode {
+	case dialogOpen:
+		m.message = "Opened " + filepath.Base(path)
+	case dialogSaveAs:
+		m.message = "Saved " + filepath.Base(path)
+	case dialogExport:
+		m.message = "Exported " + filepath.Base(path)
+		if len(warnings) > 0 {
+			m.message += " with warnings: " + strings.Join(warnings, "; ")
+		}
+	case dialogSnapshotText, dialogSnapshotHTML:
+		m.message = "Saved a snapshot to " + filepath.Base(path)
+	}
+}
+
+// openFileDialog starts a dialog next to the project file
+func (m *Model) openFileDialog(mode dialogMode) {
+	dir, file := "", ""
+	if m.project.path != "" {
+		dir = filepath.Dir(m.project.path)
+		file = strings.TrimSuffix(filepath.Base(m.project.path), ".json")
+	}
+	if file == "" {
+		file = projectFileName(m.projectName)
+	}
+	if mode == dialogExport {
+		file = "main.go"
+	}
+	m.fileDialog = newFileDialog(mode, dir, file)
+}
+
+// projectFileName suggests a file name for a project
+func projectFileName(project string) string {
+	name := strings.ToLower(strings.Join(strings.Fields(project), "-"))
+	if name == "" {
+		return "design"
+	}
+	return name
+}
+
+// renderFileDialog draws the open dialog over view
+func (m Model) renderFileDialog(view string) string {
+	d := m.fileDialog
+
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Primary).
+		Bold(true).
+		Render(d.title())
+
+	parts := []string{title, "", d.browser.View()}
+	if d.name != nil {
+		parts = append(parts, d.name.View())
+	}
+	if d.err != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.theme.Error).Render(d.err))
+	}
+
+	hint := "↑↓ browse · enter open · esc cancel"
+	switch d.mode {
+	case dialogSaveAs:
+		hint = "tab browse/name · enter save · esc cancel"
+	case dialogExport, dialogSnapshotText, dialogSnapshotHTML:
+		hint = "tab browse/name · enter export · esc cancel"
+	}
+	parts = append(parts, lipgloss.NewStyle().Foreground(m.theme.TextMuted).Render(hint))
+
+	box := lipgloss.NewStyle().
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(0, 1).
+		Width(fileDialogWidth).
+		Render(strings.Join(parts, "\n"))
+
+	w, h := lipgloss.Size(box)
+	return compositor.Overlay(view, box, max((m.width-w)/2, 0), max((m.height-h)/2, 0))
+}
+
+// fileStatus names the project file, marking unsaved changes
+func (m Model) fileStatus() string {
+	name := "unsaved"
+	if m.project.path != "" {
+		name = filepath.Base(m.project.path)
+	}
+	if m.dirty() {
+		name = fmt.Sprintf("● %s", name)
+	}
+	return name
+}
@@ -0,0 +1,148 @@
+// Package app - Responsive preview gallery
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/internal/ui/preview"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/lint"
+)
+
+// galleryGap is the space between two snapshots
+const galleryGap = 3
+
+// gallery shows the design at several terminal sizes side by side
+type gallery struct {
+	snaps   []preview.Snapshot
+	columns []int              // left edge of each snapshot
+	page    *compositor.Buffer // every snapshot with its issues below
+	x, y    int                // scroll offset into page
+	current int                // snapshot tab last jumped to
+}
+
+// startGallery renders the design at the default sizes
+func (m *Model) startGallery() {
+	m.canvas.StopDrawing()
+	m.drag = nil
+	design := m.GetCanvasSchema()
+	g := &gallery{snaps: preview.Snapshots(&design, preview.DefaultSizes, m.theme)}
+
+	muted := lipgloss.NewStyle().Foreground(m.theme.TextMuted)
+	var blocks []string
+	width, height := 0, 0
+	for _, snap := range g.snaps {
+		lines := []string{snap.View}
+		if len(snap.Issues) == 0 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(m.theme.Success).Render("✓ fits"))
+		}
+		for _, d := range snap.Issues {
+			icon, color := "⚠", m.theme.Warning
+			if d.Severity == lint.SeverityError {
+				icon, color = "✗", m.theme.Error
+			}
+			lines = append(lines, lipgloss.NewStyle().Foreground(color).Render(icon+" "+d.Component)+
+				muted.Render(": "+d.Message))
+		}
+		block := lipgloss.NewStyle().MaxWidth(snap.Size.Width + 2).Render(strings.Join(lines, "\n"))
+		g.columns = append(g.columns, width)
+		blocks = append(blocks, block)
+		width += snap.Size.Width + 2 + galleryGap
+		height = max(height, lipgloss.Height(block))
+	}
+
+	g.page = compositor.NewBuffer(width, height)
+	for i, block := range blocks {
+		g.page.Draw(g.columns[i], 0, block)
+	}
+	m.gallery = g
+}
+
+// gallerySize is the area the gallery scrolls in, the canvas with its
+// rulers
+func (m Model) gallerySize() (int, int) {
+	return m.canvas.ViewWidth + canvas.RulerWidth, m.canvas.ViewHeight + 1
+}
+
+// scrollGallery scrolls by dx, dy, within the page
+func (m *Model) scrollGallery(dx, dy int) {
+	g := m.gallery
+	width, height := m.gallerySize()
+	g.x = max(min(g.x+dx, g.page.Width()-width), 0)
+	g.y = max(min(g.y+dy, g.page.Height()-height), 0)
+}
+
+// updateGallery scrolls the gallery; tab jumps to the next size and
+// esc goes back to editing
+func (m Model) updateGallery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	g := m.gallery
+	_, height := m.gallerySize()
+	switch msg.String() {
+	case "esc", "P":
+		m.gallery = nil
+		return m, nil
+	case "left", "h":
+		m.scrollGallery(-4, 0)
+	case "right", "l":
+		m.scrollGallery(4, 0)
+	case "up", "k":
+		m.scrollGallery(0, -1)
+	case "down", "j":
+		m.scrollGallery(0, 1)
+	case "pgup":
+		m.scrollGallery(0, -height)
+	case "pgdown", " ":
+		m.scrollGallery(0, height)
+	case "home", "g":
+		g.x, g.y = 0, 0
+	case "end", "G":
+		m.scrollGallery(g.page.Width(), g.page.Height())
+	case "tab", "shift+tab":
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(g.columns) - 1
+		}
+		g.current = (g.current + step) % len(g.columns)
+		g.x = 0
+		m.scrollGallery(g.columns[g.current], 0)
+	}
+	return m, nil
+}
+
+// galleryMouse scrolls the gallery with the wheel, sideways with shift
+func (m Model) galleryMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp && msg.Shift, msg.Button == tea.MouseButtonWheelLeft:
+		m.scrollGallery(-4, 0)
+	case msg.Button == tea.MouseButtonWheelDown && msg.Shift, msg.Button == tea.MouseButtonWheelRight:
+		m.scrollGallery(4, 0)
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.scrollGallery(0, -3)
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.scrollGallery(0, 3)
+	}
+	return m, nil
+}
+
+// renderGallery renders the part of the gallery scrolled to, with a
+// line of key hints
+func (m Model) renderGallery() string {
+	g := m.gallery
+	width, height := m.gallerySize()
+	view := compositor.NewBuffer(width, height)
+	view.DrawBuffer(-g.x, -g.y, g.page)
+
+	var titles []string
+	for _, snap := range g.snaps {
+		titles = append(titles, snap.Title())
+	}
+	hint := " SIZES " + strings.Join(titles, " · ") + " | ←↑↓→ scroll · tab next size · esc edit "
+	return view.String() + "\n" + lipgloss.NewStyle().
+		Foreground(m.theme.TextMuted).
+		Italic(true).
+		MaxWidth(width).
+		Render(hint)
+}
@@ -0,0 +1,311 @@
+// Package app - Edit history and clipboard
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// pasteOffset shifts pasted and duplicated components so that they do
+// not cover the original
+const pasteOffset = 2
+
+// edit is a session command with its parameters
+type edit struct {
+	ct     agent.CommandType
+	params any
+}
+
+// exec runs an edit through the session as a new undo step
+func (m *Model) exec(ct agent.CommandType, params any) error {
+	return m.run(ct, params, false)
+}
+
+// execEdits runs edits as a single undo step. With amend set they join
+// the last undo step instead of starting a new one.
+func (m *Model) execEdits(amend bool, edits ...edit) error {
+	for i, e := range edits {
+		if err := m.run(e.ct, e.params, amend || i > 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Model) run(ct agent.CommandType, params any, amend bool) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	cmd := agent.Command{Type: string(ct), Params: data}
+	if amend {
+		err = m.session.Amend(cmd)
+	} else {
+		err = m.session.Execute(cmd)
+		m.nudging = ""
+	}
+	m.syncCanvas()
+	if err != nil {
+		m.message = err.Error()
+	}
+	return err
+}
+
+// syncCanvas points the canvas at the session's components, keeping the
+// selection in range, and lints the changed design
+func (m *Model) syncCanvas() {
+	m.canvas.Components = m.session.Canvas.Components
+	if m.canvas.Selected >= len(m.canvas.Components) {
+		m.canvas.Selected = len(m.canvas.Components) - 1
+	}
+	m.relint()
+}
+
+// selection returns the selected components
+func (m *Model) selection() []schema.Component {
+	var comps []schema.Component
+	for _, i := range m.canvas.SelectedIndices() {
+		comps = append(comps, m.canvas.Components[i])
+	}
+	return comps
+}
+
+// movable returns the selected components that are not locked
+func (m *Model) movable() []schema.Component {
+	var comps []schema.Component
+	for _, comp := range m.selection() {
+		if !comp.Locked {
+			comps = append(comps, comp)
+		}
+	}
+	return comps
+}
+
+// selectionKey identifies the current selection, so that repeated
+// nudges of the same components can be merged into one undo step
+func (m *Model) selectionKey(prefix string) string {
+	var ids []string
+	for _, comp := range m.selection() {
+		ids = append(ids, comp.ID)
+	}
+	return prefix + strings.Join(ids, ",")
+}
+
+// selectLast selects the last n components, such as those just pasted
+func (m *Model) selectLast(n int) {
+	total := len(m.canvas.Components)
+	m.canvas.Select(total - 1)
+	for i := max(total-n, 0); i < total-1; i++ {
+		m.canvas.ToggleSelect(i)
+	}
+}
+
+// addComponent places a new component at the cursor and selects it
+func (m *Model) addComponent(ctype schema.ComponentType, name string) {
+	err := m.exec(agent.CmdAddComponent, agent.AddComponentParams{
+		Type: ctype,
+		Name: name,
+		X:    m.canvas.CursorX,
+		Y:    m.canvas.CursorY,
+		Text: name,
+	})
+	if err == nil {
+		m.canvas.Select(len(m.canvas.Components) - 1)
+	}
+}
+
+// removeSelected deletes the selected components
+func (m *Model) removeSelected() {
+	indices := m.canvas.SelectedIndices()
+	if len(indices) == 0 {
+		return
+	}
+	var edits []edit
+	for _, comp := range m.selection() {
+		edits = append(edits, edit{agent.CmdRemoveComponent, struct {
+			ID string `json:"id"`
+		}{ID: comp.ID}})
+	}
+	_ = m.execEdits(false, edits...)
+	m.canvas.Select(min(indices[0], len(m.canvas.Components)-1))
+}
+
+// nudgeSelected moves the selected components by dx, dy, or with snap
+// on to the next grid line. Consecutive nudges of the same components
+// are undone together.
+func (m *Model) nudgeSelected(dx, dy int) {
+	if pos, _, ok := m.canvas.SelectionBounds(); ok && m.canvas.Snap {
+		dx = gridStep(pos.X, dx, m.canvas.Grid.Width)
+		dy = gridStep(pos.Y, dy, m.canvas.Grid.Height)
+	}
+	m.repeatable("move:", func(comp schema.Component) edit {
+		return edit{agent.CmdMoveComponent, agent.MoveComponentParams{
+			ID: comp.ID,
+			X:  comp.Position.X + dx,
+			Y:  comp.Position.Y + dy,
+		}}
+	})
+	m.showGuides()
+}
+
+// resizeSelected grows or shrinks the selected components by dw, dh,
+// or with snap on until their right or bottom edge meets the next grid
+// line. Like nudges, consecutive resizes are undone together.
+func (m *Model) resizeSelected(dw, dh int) {
+	if pos, size, ok := m.canvas.SelectionBounds(); ok && m.canvas.Snap {
+		dw = gridStep(pos.X+size.Width, dw, m.canvas.Grid.Width)
+		dh = gridStep(pos.Y+size.Height, dh, m.canvas.Grid.Height)
+	}
+	m.repeatable("resize:", func(comp schema.Component) edit {
+		return edit{agent.CmdResizeComponent, agent.ResizeComponentParams{
+			ID:     comp.ID,
+			Width:  max(comp.Size.Width+dw, 1),
+			Height: max(comp.Size.Height+dh, 1),
+		}}
+	})
+	m.showGuides()
+}
+
+// repeatable applies an edit to every selected component, merging it
+// into the last undo step when it repeats the previous edit
+func (m *Model) repeatable(prefix string, fn func(schema.Component) edit) {
+	comps := m.movable()
+	if len(comps) == 0 {
+		if len(m.selection()) > 0 {
+			m.message = "Locked"
+		}
+		return
+	}
+	var edits []edit
+	for _, comp := range comps {
+		edits = append(edits, fn(comp))
+	}
+	key := m.selectionKey(prefix)
+	if m.execEdits(m.nudging == key, edits...) == nil {
+		m.nudging = key
+	}
+}
+
+// commitComponent records an edit that was already applied to the
+// selected component in place, such as one made in the properties
+// panel, as a single undo step. before is the component as it was.
+// It reports whether there was a change to record.
+func (m *Model) commitComponent(before schema.Component) bool {
+	comp := m.canvas.GetSelected()
+	if comp == nil || reflect.DeepEqual(*comp, before) {
+		return false
+	}
+	after := comp.Clone()
+	*comp = before
+	return m.exec(agent.CmdUpdateComponent, agent.UpdateComponentParams{Component: after}) == nil
+}
+
+// undo reverts the last edit
+func (m *Model) undo() {
+	if !m.restore(m.session.Undo) {
+		m.message = "Nothing to undo"
+	}
+}
+
+// redo reapplies the last undone edit
+func (m *Model) redo() {
+	if !m.restore(m.session.Redo) {
+		m.message = "Nothing to redo"
+	}
+}
+
+// restore steps through the history with undo or redo. A design without
+// a size of its own follows the window, see fitCanvas, so it keeps the
+// size it has now rather than the one recorded with the edit.
+func (m *Model) restore(step func() bool) bool {
+	width, height := m.session.Canvas.Width, m.session.Canvas.Height
+	if !step() {
+		return false
+	}
+	if !m.sized {
+		m.session.Resize(width, height)
+	}
+	m.nudging = ""
+	m.syncCanvas()
+	return true
+}
+
+// describe names a set of components for status messages
+func describe(comps []schema.Component) string {
+	if len(comps) == 1 {
+		return comps[0].Name
+	}
+	return fmt.Sprintf("%d components", len(comps))
+}
+
+// copySelected puts a copy of the selected components on the clipboard
+func (m *Model) copySelected() bool {
+	comps := m.selection()
+	if len(comps) == 0 {
+		return false
+	}
+	m.clipboard = nil
+	for _, comp := range comps {
+		m.clipboard = append(m.clipboard, comp.Clone())
+	}
+	m.message = "Copied " + describe(comps)
+	return true
+}
+
+// cutSelected copies the selected components and deletes them. The
+// first paste puts them back where they were.
+func (m *Model) cutSelected() {
+	if !m.copySelected() {
+		return
+	}
+	m.removeSelected()
+	for i := range m.clipboard {
+		m.clipboard[i].Position.X -= pasteOffset
+		m.clipboard[i].Position.Y -= pasteOffset / 2
+	}
+	m.message = "Cut " + describe(m.clipboard)
+}
+
+// paste adds a copy of the clipboard, offset from where it was copied.
+// Pasting again cascades further, so copies never stack exactly.
+func (m *Model) paste() {
+	if len(m.clipboard) == 0 {
+		m.message = "Clipboard is empty"
+		return
+	}
+	err := m.exec(agent.CmdPasteComponents, agent.PasteComponentsParams{
+		Components: m.clipboard,
+		DX:         pasteOffset,
+		DY:         pasteOffset / 2,
+	})
+	if err != nil {
+		return
+	}
+	for i := range m.clipboard {
+		m.clipboard[i].Position.X += pasteOffset
+		m.clipboard[i].Position.Y += pasteOffset / 2
+	}
+	m.selectLast(len(m.clipboard))
+}
+
+// duplicateSelected adds offset copies of the selected components
+// without touching the clipboard
+func (m *Model) duplicateSelected() {
+	comps := m.selection()
+	if len(comps) == 0 {
+		return
+	}
+	err := m.exec(agent.CmdPasteComponents, agent.PasteComponentsParams{
+		Components: comps,
+		DX:         pasteOffset,
+		DY:         pasteOffset / 2,
+	})
+	if err == nil {
+		m.selectLast(len(comps))
+	}
+}
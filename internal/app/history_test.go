@@ -0,0 +1,27 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoKeepsWindowSize(t *testing.T) {
+	m := press(newTestModel(t), "enter") // add a box
+	m = send(m, tea.WindowSizeMsg{Width: 150, Height: 50})
+	width, height := m.session.Canvas.Width, m.session.Canvas.Height
+
+	// Called directly, as Update would put the window's size back
+	m.undo()
+	if len(m.canvas.Components) != 0 {
+		t.Fatalf("%d components after undoing the box, want 0", len(m.canvas.Components))
+	}
+	if got := m.session.Canvas; got.Width != width || got.Height != height {
+		t.Errorf("undo sized the design %d×%d, want the window's %d×%d", got.Width, got.Height, width, height)
+	}
+
+	m.redo()
+	if got := m.session.Canvas; len(got.Components) != 1 || got.Width != width || got.Height != height {
+		t.Errorf("redo gave %d components at %d×%d, want 1 at %d×%d", len(got.Components), got.Width, got.Height, width, height)
+	}
+}
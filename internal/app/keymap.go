@@ -0,0 +1,374 @@
+// Package app - Key bindings, presets and the keys file
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/makeatui/makeatui/internal/config"
+)
+
+// keys are the designer's bindings, see LoadKeyMap
+var keys, _ = PresetKeyMap("default")
+
+// KeyPresets are the names of the key presets, the default first
+var KeyPresets = []string{"default", "vim", "emacs"}
+
+// keyPresets are the bindings each preset changes from the default ones
+var keyPresets = map[string]map[string][]string{
+	"default": nil,
+	"vim": {
+		"copy":       {"y"},
+		"paste":      {"p"},
+		"box-select": {"v"},
+		"preview":    {"!"},
+		"commands":   {":", "ctrl+p"},
+	},
+	"emacs": {
+		"up":       {"up", "ctrl+p"},
+		"down":     {"down", "ctrl+n"},
+		"left":     {"left", "ctrl+b"},
+		"right":    {"right", "ctrl+f"},
+		"commands": {"alt+x"},
+		"undo":     {"ctrl+_", "u"},
+		"redo":     {"alt+_"},
+		"copy":     {"alt+w"},
+		"cut":      {"ctrl+w"},
+		"paste":    {"ctrl+y"},
+		"delete":   {"ctrl+d", "d", "delete"},
+	},
+}
+
+// reservedKeys are keys the designer uses for something that cannot be
+// rebound, and what
+var reservedKeys = map[string]string{
+	"ctrl+left":  "pan",
+	"ctrl+right": "pan",
+	"ctrl+up":    "pan",
+	"ctrl+down":  "pan",
+	"pgup":       "pan",
+	"pgdown":     "pan",
+	"esc":        "cancel",
+}
+
+// keyNames are how labels show keys that have a symbol or a clearer
+// name
+var keyNames = map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "space"}
+
+// keyAction is a binding of a KeyMap with the name the keys file gives
+// it
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions lists the bindings of k by name. Names match those of the
+// command palette entries that do the same.
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Up}, {"down", &k.Down}, {"left", &k.Left}, {"right", &k.Right},
+		{"add", &k.Enter}, {"focus", &k.Tab}, {"delete", &k.Delete},
+		{"move-mode", &k.MoveMod}, {"resize-mode", &k.Resize}, {"draw", &k.Draw},
+		{"box-select", &k.BoxSelect}, {"select-all", &k.SelectAll},
+		{"select-same", &k.SameType}, {"select-invert", &k.Invert},
+		{"arrange", &k.Arrange}, {"group", &k.Group}, {"ungroup", &k.Ungroup},
+		{"forward", &k.Forward}, {"backward", &k.Backward},
+		{"to-front", &k.ToFront}, {"to-back", &k.ToBack}, {"edit-text", &k.EditText},
+		{"templates", &k.Templates},
+		{"snap", &k.Snap}, {"grid", &k.Grid}, {"layers", &k.Layers},
+		{"preview", &k.Preview}, {"gallery", &k.Gallery},
+		{"fit", &k.Fit}, {"minimap", &k.Minimap}, {"design-size", &k.Size},
+		{"undo", &k.Undo}, {"redo", &k.Redo},
+		{"copy", &k.Copy}, {"cut", &k.Cut}, {"paste", &k.Paste}, {"duplicate", &k.Duplicate},
+		{"lint-panel", &k.Lint}, {"theme-next", &k.Theme}, {"theme-editor", &k.ThemeEditor},
+		{"save", &k.Save}, {"save-as", &k.SaveAs}, {"open", &k.Open},
+		{"export-go", &k.Export}, {"commands", &k.Commands},
+		{"help", &k.Help}, {"quit", &k.Quit},
+	}
+}
+
+// KeyBinding is an action of a keymap, as the keys file names it, with
+// its keys and what it does
+type KeyBinding struct {
+	Action string
+	Keys   []string
+	Desc   string
+}
+
+// Bindings lists the actions of k
+func (k KeyMap) Bindings() []KeyBinding {
+	var bindings []KeyBinding
+	for _, a := range k.actions() {
+		bindings = append(bindings, KeyBinding{a.name, a.binding.Keys(), a.binding.Help().Desc})
+	}
+	return bindings
+}
+
+// PresetKeyMap returns the bindings of the named preset
+func PresetKeyMap(name string) (KeyMap, error) {
+	changes, ok := keyPresets[name]
+	if !ok {
+		return KeyMap{}, fmt.Errorf("unknown key preset %q, want %s", name, strings.Join(KeyPresets, ", "))
+	}
+	return defaultKeys.with(changes)
+}
+
+// with returns k with the named actions bound to other keys, and the
+// help of every binding labelled from its keys. An empty list of keys
+// unbinds the action.
+func (k KeyMap) with(bindings map[string][]string) (KeyMap, error) {
+	var unknown []string
+	names := map[string]bool{}
+	for _, a := range k.actions() {
+		names[a.name] = true
+	}
+	for name := range bindings {
+		if !names[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return k, fmt.Errorf("unknown key actions %s", strings.Join(unknown, ", "))
+	}
+
+	for _, a := range k.actions() {
+		ks, ok := bindings[a.name]
+		if !ok {
+			ks = a.binding.Keys()
+		}
+		ks = normalizeKeys(ks)
+		opts := []key.BindingOpt{key.WithKeys(ks...), key.WithHelp(keyLabel(ks), a.binding.Help().Desc)}
+		if len(ks) == 0 {
+			opts = append(opts, key.WithDisabled())
+		}
+		*a.binding = key.NewBinding(opts...)
+	}
+	return k, nil
+}
+
+// normalizeKeys returns ks as bubbletea names them, so "space" may be
+// written for " "
+func normalizeKeys(ks []string) []string {
+	out := make([]string, 0, len(ks))
+	for _, s := range ks {
+		if s == "space" {
+			s = " "
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// keyLabel shows keys in help, such as "↑/k"
+func keyLabel(ks []string) string {
+	labels := make([]string, len(ks))
+	for i, s := range ks {
+		if name, ok := keyNames[s]; ok {
+			s = name
+		}
+		labels[i] = s
+	}
+	return strings.Join(labels, "/")
+}
+
+// shortKey is the first key of b for hints, or "" when b is unbound
+func shortKey(b key.Binding) string {
+	if len(b.Keys()) == 0 {
+		return ""
+	}
+	return keyLabel(b.Keys()[:1])
+}
+
+// Conflicts describes each key bound to more than one action, or to an
+// action and something that cannot be rebound
+func (k KeyMap) Conflicts() []string {
+	owners := map[string][]string{}
+	var order []string
+	for _, a := range k.actions() {
+		for _, s := range a.binding.Keys() {
+			names := owners[s]
+			if len(names) > 0 && names[len(names)-1] == a.name {
+				continue
+			}
+			if names == nil {
+				order = append(order, s)
+			}
+			owners[s] = append(names, a.name)
+		}
+	}
+
+	var conflicts []string
+	for _, s := range order {
+		names := owners[s]
+		if what, ok := reservedKeys[s]; ok {
+			names = append(names, what)
+		}
+		switch {
+		case len(names) == 2:
+			conflicts = append(conflicts, fmt.Sprintf("%s is bound to both %s and %s", keyLabel([]string{s}), names[0], names[1]))
+		case len(names) > 2:
+			conflicts = append(conflicts, fmt.Sprintf("%s is bound to %s and %s", keyLabel([]string{s}),
+				strings.Join(names[:len(names)-1], ", "), names[len(names)-1]))
+		}
+	}
+	return conflicts
+}
+
+// keysFile is the keys file: a preset, and the bindings that replace
+// its ones, each a list of keys
+type keysFile struct {
+	Preset   string              `json:"preset,omitempty"`
+	Bindings map[string][]string `json:"bindings,omitempty"`
+}
+
+// KeysPath is where the designer reads its key bindings from
+func KeysPath() string {
+	return config.Path("keys.json")
+}
+
+// ReadKeyMap reads a keys file. Without one the bindings are the default
+// ones; with mistakes, an unknown action or a key bound twice, the
+// default ones are returned with an error.
+func ReadKeyMap(path string) (KeyMap, error) {
+	fallback, _ := PresetKeyMap("default")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fallback, nil
+	}
+	if err != nil {
+		return fallback, err
+	}
+
+	var f keysFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fallback, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Preset == "" {
+		f.Preset = "default"
+	}
+	k, err := PresetKeyMap(f.Preset)
+	if err == nil {
+		k, err = k.with(f.Bindings)
+	}
+	if err == nil {
+		if conflicts := k.Conflicts(); len(conflicts) > 0 {
+			err = errors.New(strings.Join(conflicts, "; "))
+		}
+	}
+	if err != nil {
+		return fallback, fmt.Errorf("%s: %w", path, err)
+	}
+	return k, nil
+}
+
+// LoadKeyMap makes the bindings of the keys file the designer's
+func LoadKeyMap() error {
+	k, err := ReadKeyMap(KeysPath())
+	keys = k
+	return err
+}
+
+// helpRow is a line of the keyboard help: the keys of the actions named,
+// or fixed keys that cannot be rebound, and what they do. long says more
+// for the command line help.
+type helpRow struct {
+	actions     []string
+	fixed       string
+	short, long string
+}
+
+var helpRows = []helpRow{
+	{actions: []string{"commands"}, short: "Command palette", long: "Command palette: every action, searchable, recent first"},
+	{actions: []string{"focus"}, short: "Switch focus area", long: "Switch focus (sidebar → canvas → properties)"},
+	{actions: []string{"up", "down"}, short: "Navigate up/down"},
+	{actions: []string{"left", "right"}, short: "Navigate left/right"},
+	{actions: []string{"add"}, short: "Add selected component", long: "Add selected component to canvas"},
+	{actions: []string{"delete"}, short: "Delete selected", long: "Delete selected component"},
+	{actions: []string{"move-mode"}, short: "Toggle move mode"},
+	{actions: []string{"resize-mode"}, short: "Toggle resize mode"},
+	{actions: []string{"draw"}, short: "Draw the sidebar's component type", long: "Draw a component: space to anchor, move, space to create"},
+	{actions: []string{"box-select"}, short: "Box select (space selects, esc cancels)", long: "Box select: move the cursor, space selects what it encloses"},
+	{actions: []string{"select-all", "select-same", "select-invert"}, short: "Select all, same type, invert", long: "Select all, all of the same type, invert the selection"},
+	{actions: []string{"arrange"}, short: "Align or distribute the selection"},
+	{actions: []string{"group", "ungroup"}, short: "Group, ungroup", long: "Group the selection into a container, ungroup"},
+	{actions: []string{"snap", "grid"}, short: "Toggle snap, change grid size", long: "Toggle snapping, cycle the grid (1×1, 2×1, 4×2, 8×4)"},
+	{actions: []string{"layers"}, short: "Layers panel (H hide, ^L lock, r rename)", long: "Layers panel: shift+↑↓ reorder, H hide, Ctrl+L lock, r rename"},
+	{actions: []string{"forward", "backward"}, short: "Bring forward, send backward", long: "Bring the selection forward, send it backward"},
+	{actions: []string{"to-front", "to-back"}, short: "Bring to front, send to back", long: "Bring the selection to the front, send it to the back"},
+	{actions: []string{"preview"}, short: "Preview the design (esc returns)", long: "Preview: run the design, tab between components, esc to edit"},
+	{actions: []string{"gallery"}, short: "Preview at 80×24, 120×40 and 60×20", long: "Preview at 80×24, 120×40 and 60×20 side by side"},
+	{fixed: "ctrl+arrows", short: "Pan a large design (also pgup/pgdown, wheel)", long: "Pan a design larger than the window (also pgup/pgdown and the wheel)"},
+	{actions: []string{"fit", "minimap"}, short: "Fit the design to the view, toggle minimap", long: "Fit the whole design in the window, toggle the minimap"},
+	{actions: []string{"design-size"}, short: "Set the design size", long: "Set the design size, or empty to follow the window"},
+	{actions: []string{"undo", "redo"}, short: "Undo, redo"},
+	{actions: []string{"copy", "cut", "paste"}, short: "Copy, cut, paste"},
+	{actions: []string{"duplicate"}, short: "Duplicate selected", long: "Duplicate selected component"},
+	{actions: []string{"edit-text"}, short: "Edit text in place (or enter on it)", long: "Edit the selected component's text in place (or enter on it)"},
+	{actions: []string{"templates"}, short: "Browse templates (enter new, i insert)", long: "Browse templates: enter starts a new design, i inserts into the selection"},
+	{fixed: "enter", short: "Edit property (properties panel)"},
+	{fixed: "esc", short: "Revert the property being edited"},
+	{actions: []string{"lint-panel"}, short: "Toggle lint panel"},
+	{actions: []string{"theme-next"}, short: "Switch theme"},
+	{actions: []string{"theme-editor"}, short: "Generate a theme from a seed color"},
+	{actions: []string{"save", "save-as"}, short: "Save, save as"},
+	{actions: []string{"open"}, short: "Open a design"},
+	{actions: []string{"export-go"}, short: "Export to Go code", long: "Export design to Go code"},
+	{actions: []string{"help"}, short: "Toggle this help", long: "Toggle help overlay"},
+	{actions: []string{"quit"}, short: "Quit"},
+}
+
+// helpLines returns the keyboard help for k, a shortcut a line with the
+// keys in a column. An unbound action shows as "-"; rows with nothing
+// bound are left out.
+func (k KeyMap) helpLines(long bool) []string {
+	labels := map[string]string{}
+	for _, a := range k.actions() {
+		labels[a.name] = a.binding.Help().Key
+	}
+
+	type line struct{ keys, desc string }
+	var lines []line
+	width := 0
+	for _, r := range helpRows {
+		keys, bound := r.fixed, r.fixed != ""
+		if !bound {
+			var ks []string
+			for _, name := range r.actions {
+				label := labels[name]
+				bound = bound || label != ""
+				if label == "" {
+					label = "-"
+				}
+				ks = append(ks, label)
+			}
+			keys = strings.Join(ks, ", ")
+		}
+		if !bound {
+			continue
+		}
+		desc := r.short
+		if long && r.long != "" {
+			desc = r.long
+		}
+		lines = append(lines, line{keys, desc})
+		width = max(width, len([]rune(keys)))
+	}
+
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.keys + strings.Repeat(" ", width-len([]rune(l.keys))+2) + l.desc
+	}
+	return out
+}
+
+// KeyHelp is the help for the designer's keys, as the command line
+// shows it, each line indented
+func KeyHelp(indent string) string {
+	return indent + strings.Join(keys.helpLines(true), "\n"+indent)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyPresetsHaveNoConflicts(t *testing.T) {
+	for _, name := range KeyPresets {
+		k, err := PresetKeyMap(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if conflicts := k.Conflicts(); len(conflicts) > 0 {
+			t.Errorf("%s: %s", name, strings.Join(conflicts, "; "))
+		}
+	}
+}
+
+func TestReadKeyMap(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "keys.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	k, err := ReadKeyMap(write(`{"preset": "vim", "bindings": {"redo": ["ctrl+y"], "export-go": []}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlY}, k.Redo) || key.Matches(tea.KeyMsg{Type: tea.KeyCtrlR}, k.Redo) {
+		t.Errorf("redo is bound to %v, want ctrl+y", k.Redo.Keys())
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}, k.Copy) {
+		t.Errorf("copy is bound to %v, want the vim preset's y", k.Copy.Keys())
+	}
+	if k.Export.Enabled() {
+		t.Errorf("export is bound to %v, want nothing", k.Export.Keys())
+	}
+	for _, line := range k.helpLines(false) {
+		if strings.Contains(line, "Export") {
+			t.Errorf("help lists the unbound export: %q", line)
+		}
+	}
+
+	for content, want := range map[string]string{
+		`{"bindings": {"fit": ["u"]}}`:    "u is bound to both fit and undo",
+		`{"bindings": {"fit": ["pgup"]}}`: "pgup is bound to both fit and pan",
+		`{"bindings": {"fits": ["z"]}}`:   "unknown key actions fits",
+		`{"preset": "nano"}`:              `unknown key preset "nano"`,
+	} {
+		k, err := ReadKeyMap(write(content))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got error %v, want %q", content, err, want)
+		}
+		if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlR}, k.Redo) {
+			t.Errorf("%s: the default keys are not kept", content)
+		}
+	}
+}
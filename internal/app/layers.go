@@ -0,0 +1,396 @@
+// Package app - Layers panel
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/widgets/display"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+)
+
+const layersPanelWidth = 34
+
+// layersTop is the row of the first layer below the top of the panel:
+// past the border, the title and a blank line
+const layersTop = 3
+
+// layersPanel lists the components as a tree, frontmost first
+type layersPanel struct {
+	tree   *display.Tree
+	offset int              // first visible row when the tree is scrolled
+	rename *input.TextInput // open while renaming the layer at the cursor
+}
+
+func newLayersPanel() *layersPanel {
+	return &layersPanel{tree: display.NewTree("layers").SetShowRoot(false)}
+}
+
+// refreshLayers rebuilds the tree from the canvas, keeping the cursor
+// and the collapsed containers. The cursor follows the selection unless
+// it is already on the selected component or inside it.
+func (m *Model) refreshLayers() {
+	lp := m.layers
+	collapsed := map[string]bool{}
+	var cursor string
+	if lp.tree.Root != nil {
+		var walk func(n *display.TreeNode)
+		walk = func(n *display.TreeNode) {
+			if !n.IsLeaf() && !n.Expanded {
+				collapsed[n.ID] = true
+			}
+			for _, child := range n.Children {
+				walk(child)
+			}
+		}
+		walk(lp.tree.Root)
+		if node := lp.tree.GetSelectedNode(); node != nil {
+			cursor = node.ID
+		}
+	}
+
+	root := display.NewTreeNode("", "").SetExpanded(true)
+	m.addLayerNodes(root, m.canvas.Components, collapsed, true)
+	lp.tree.SetStyle(m.layerStyle()).SetRoot(root)
+	lp.tree.Focused = m.focus == FocusLayers
+
+	if sel := m.canvas.GetSelected(); sel != nil && m.layerOwner(cursor) != sel.ID {
+		cursor = sel.ID
+	}
+	lp.tree.SelectID(cursor)
+	m.scrollLayers()
+}
+
+// addLayerNodes adds a node for each of comps, frontmost first. Nodes
+// of top-level components carry their canvas index.
+func (m *Model) addLayerNodes(parent *display.TreeNode, comps []schema.Component, collapsed map[string]bool, top bool) {
+	index := map[string]int{}
+	if top {
+		for i, comp := range comps {
+			index[comp.ID] = i
+		}
+	}
+	stacked := schema.Stack(comps)
+	for i := len(stacked) - 1; i >= 0; i-- {
+		comp := stacked[i]
+		label := comp.Name
+		var flags []string
+		if comp.Hidden {
+			flags = append(flags, "hidden")
+		}
+		if comp.Locked {
+			flags = append(flags, "locked")
+		}
+		if len(flags) > 0 {
+			label += lipgloss.NewStyle().Foreground(m.theme.TextMuted).Render(" · " + strings.Join(flags, ", "))
+		}
+		node := display.NewTreeNode(comp.ID, label).
+			SetIcon(m.typeIcon(comp.Type)).
+			SetExpanded(!collapsed[comp.ID])
+		if top {
+			node.Selected = m.canvas.IsSelected(index[comp.ID])
+		}
+		parent.AddChild(node)
+		m.addLayerNodes(node, comp.Children, collapsed, false)
+	}
+}
+
+// typeIcon returns the sidebar icon for a component type
+func (m Model) typeIcon(ctype schema.ComponentType) string {
+	for _, item := range m.components {
+		if item.Type == ctype {
+			return item.Icon
+		}
+	}
+	return "□"
+}
+
+// layerStyle styles the tree with the theme
+func (m Model) layerStyle() display.TreeStyle {
+	style := display.DefaultTreeStyle()
+	style.Node = lipgloss.NewStyle().Foreground(m.theme.TextSecondary)
+	style.NodeSel = lipgloss.NewStyle().Foreground(m.theme.TextPrimary).Background(m.theme.SurfaceLight).Bold(true)
+	style.Icon = lipgloss.NewStyle().Foreground(m.theme.Accent)
+	style.IconExpand = lipgloss.NewStyle().Foreground(m.theme.Secondary)
+	style.Connector = lipgloss.NewStyle().Foreground(m.theme.Border)
+	style.Guide = display.RoundGuideStyle()
+	return style
+}
+
+// layerOwner returns the ID of the top-level component that is, or
+// contains, the component with the given ID
+func (m Model) layerOwner(id string) string {
+	for _, comp := range m.canvas.Components {
+		if comp.ID == id || hasDescendant(comp, id) {
+			return comp.ID
+		}
+	}
+	return ""
+}
+
+func hasDescendant(comp schema.Component, id string) bool {
+	for _, child := range comp.Children {
+		if child.ID == id || hasDescendant(child, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// layerAtCursor returns the component under the layers cursor
+func (m Model) layerAtCursor() *schema.Component {
+	node := m.layers.tree.GetSelectedNode()
+	if node == nil {
+		return nil
+	}
+	return m.session.Canvas.Find(node.ID)
+}
+
+// selectLayerAtCursor selects on the canvas the component under the
+// layers cursor, or the top-level component that contains it
+func (m *Model) selectLayerAtCursor() {
+	node := m.layers.tree.GetSelectedNode()
+	if node == nil {
+		return
+	}
+	owner := m.layerOwner(node.ID)
+	for i, comp := range m.canvas.Components {
+		if comp.ID == owner {
+			m.canvas.Select(i)
+		}
+	}
+}
+
+// toggleLayers opens the layers panel, or goes back to the canvas
+func (m *Model) toggleLayers() {
+	if m.focus == FocusLayers {
+		m.focus = FocusCanvas
+		return
+	}
+	m.focus = FocusLayers
+	m.refreshLayers()
+}
+
+// updateLayers handles keys while the layers panel has focus
+func (m Model) updateLayers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	lp := m.layers
+	if lp.rename != nil {
+		m.updateRename(msg)
+		return m, nil
+	}
+
+	comp := m.layerAtCursor()
+	switch msg.String() {
+	case "esc":
+		m.focus = FocusCanvas
+	case "shift+up", "K":
+		if comp != nil {
+			m.restack([]string{comp.ID}, 1)
+		}
+	case "shift+down", "J":
+		if comp != nil {
+			m.restack([]string{comp.ID}, -1)
+		}
+	case "H":
+		if comp != nil {
+			m.setLayerFlag(*comp, func(c *schema.Component) { c.Hidden = !c.Hidden })
+		}
+	case "ctrl+l":
+		if comp != nil {
+			m.setLayerFlag(*comp, func(c *schema.Component) { c.Locked = !c.Locked })
+		}
+	case "r":
+		if comp != nil {
+			m.startRename(*comp)
+		}
+	default:
+		if step := restackStep(msg); step != 0 {
+			if comp != nil {
+				m.restack([]string{comp.ID}, step)
+			}
+			break
+		}
+		lp.tree.Update(msg)
+		m.selectLayerAtCursor()
+	}
+	return m, nil
+}
+
+// restackStep is how far the key brings components forward, or sends
+// them backward when negative: one step, or all the way to the front or
+// back. It is 0 for other keys.
+func restackStep(msg tea.KeyMsg) int {
+	switch {
+	case key.Matches(msg, keys.Forward):
+		return 1
+	case key.Matches(msg, keys.Backward):
+		return -1
+	case key.Matches(msg, keys.ToFront):
+		return 1 << 16
+	case key.Matches(msg, keys.ToBack):
+		return -(1 << 16)
+	}
+	return 0
+}
+
+// restack brings the components with the given IDs forward, or sends
+// them backward for negative steps, as one undo step, keeping the
+// selection
+func (m *Model) restack(ids []string, steps int) {
+	if len(ids) == 0 {
+		return
+	}
+	selected := map[string]bool{}
+	for _, comp := range m.selection() {
+		selected[comp.ID] = true
+	}
+	primary := ""
+	if comp := m.canvas.GetSelected(); comp != nil {
+		primary = comp.ID
+	}
+
+	// Move the frontmost first when bringing forward, so that the
+	// components never trade places with each other
+	var edits []edit
+	for i := range ids {
+		id := ids[i]
+		if steps > 0 {
+			id = ids[len(ids)-1-i]
+		}
+		edits = append(edits, edit{agent.CmdReorder, agent.ReorderParams{ID: id, Steps: steps}})
+	}
+	if m.execEdits(false, edits...) != nil {
+		return
+	}
+
+	m.canvas.Select(-1)
+	for i, comp := range m.canvas.Components {
+		if comp.ID == primary {
+			m.canvas.Select(i)
+		}
+	}
+	for i, comp := range m.canvas.Components {
+		if selected[comp.ID] && comp.ID != primary {
+			m.canvas.ToggleSelect(i)
+		}
+	}
+}
+
+// restackSelected brings the selection forward or sends it backward
+func (m *Model) restackSelected(steps int) {
+	var ids []string
+	for _, i := range m.canvas.StackOrder() {
+		if m.canvas.IsSelected(i) {
+			ids = append(ids, m.canvas.Components[i].ID)
+		}
+	}
+	m.restack(ids, steps)
+}
+
+// setLayerFlag applies change to a copy of comp and records it as an
+// undo step
+func (m *Model) setLayerFlag(comp schema.Component, change func(*schema.Component)) {
+	comp = comp.Clone()
+	change(&comp)
+	_ = m.exec(agent.CmdUpdateComponent, agent.UpdateComponentParams{Component: comp})
+}
+
+// startRename opens an editor for the name of comp
+func (m *Model) startRename(comp schema.Component) {
+	m.layers.rename = input.NewTextInput("layer-name").
+		SetWidth(layersPanelWidth - 8).
+		SetRequired(true)
+	m.layers.rename.SetValue(comp.Name)
+	m.layers.rename.CursorEnd()
+	m.layers.rename.Focus()
+}
+
+// updateRename edits the name being typed: enter keeps it and esc
+// drops it
+func (m *Model) updateRename(msg tea.KeyMsg) {
+	lp := m.layers
+	switch msg.String() {
+	case "esc":
+		lp.rename = nil
+	case "enter":
+		name := strings.TrimSpace(lp.rename.Value())
+		comp := m.layerAtCursor()
+		lp.rename = nil
+		if comp != nil && name != "" && name != comp.Name {
+			m.setLayerFlag(*comp, func(c *schema.Component) { c.Name = name })
+		}
+	default:
+		lp.rename.Update(msg)
+	}
+}
+
+// layerRows returns how many layers fit in the panel, above the hint
+func (m Model) layerRows() int {
+	_, _, _, height := m.layout()
+	return max(height-layersTop-4, 1)
+}
+
+// scrollLayers keeps the cursor within the visible rows
+func (m *Model) scrollLayers() {
+	lp := m.layers
+	rows := m.layerRows()
+	cursor := 0
+	if node := lp.tree.GetSelectedNode(); node != nil {
+		for i, n := range lp.tree.VisibleNodes() {
+			if n == node {
+				cursor = i
+			}
+		}
+	}
+	lp.offset = min(lp.offset, cursor)
+	lp.offset = max(lp.offset, cursor-rows+1)
+	lp.offset = max(min(lp.offset, len(lp.tree.VisibleNodes())-rows), 0)
+}
+
+// renderLayers renders the layers panel
+func (m Model) renderLayers(width, height int) string {
+	lp := m.layers
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Primary).
+		Bold(true).
+		Render("≡ Layers")
+	muted := lipgloss.NewStyle().Foreground(m.theme.TextMuted)
+
+	var body string
+	if len(m.canvas.Components) == 0 {
+		body = muted.Render("No components yet")
+	} else {
+		lines := strings.Split(lp.tree.View(), "\n")
+		end := min(lp.offset+m.layerRows(), len(lines))
+		for i := lp.offset; i < end; i++ {
+			lines[i] = lipgloss.NewStyle().MaxWidth(width - 2).Render(lines[i])
+		}
+		body = strings.Join(lines[lp.offset:end], "\n")
+	}
+
+	hint := "⇧↑↓ reorder · [ ] { } stack\nH hide · ^L lock · r rename"
+	if lp.rename != nil {
+		body += "\n\n" + lp.rename.View()
+		hint = "enter rename · esc cancel"
+	}
+
+	borderColor := m.theme.Border
+	if m.focus == FocusLayers {
+		borderColor = m.theme.Primary
+	}
+
+	content := title + "\n\n" + body + "\n\n" + muted.MaxWidth(width-2).Render(hint)
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		MaxHeight(height+2).
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Render(content)
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// stackNames returns the names of the components from the back to the
+// front
+func stackNames(m Model) []string {
+	var names []string
+	for _, i := range m.canvas.StackOrder() {
+		names = append(names, m.canvas.Components[i].Name)
+	}
+	return names
+}
+
+// newLayersModel returns the designer with boxes a, b and c stacked in
+// that order and the layers panel open on c
+func newLayersModel(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel(t)
+	for i, name := range []string{"a", "b", "c"} {
+		_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: name, X: 4 * i, Y: 2 * i, Width: 10, Height: 4})
+	}
+	m.canvas.Select(2)
+	m = press(m, "O")
+	if m.focus != FocusLayers || m.layerAtCursor() == nil || m.layerAtCursor().Name != "c" {
+		t.Fatalf("focus %v, want the layers panel on c", m.focus)
+	}
+	return m
+}
+
+func TestLayersReorder(t *testing.T) {
+	m := newLayersModel(t)
+	steps := len(m.session.UndoStack)
+
+	m = press(m, "J")
+	if got := stackNames(m); !slices.Equal(got, []string{"a", "c", "b"}) {
+		t.Fatalf("stack %v after sending c backward, want [a c b]", got)
+	}
+	if got := m.canvas.GetSelected(); got == nil || got.Name != "c" {
+		t.Errorf("selected %v after reordering, want c", got)
+	}
+	if got := len(m.session.UndoStack) - steps; got != 1 {
+		t.Errorf("reordering took %d undo steps, want 1", got)
+	}
+
+	m = press(m, "K")
+	if got := stackNames(m); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("stack %v after bringing c forward, want [a b c]", got)
+	}
+	m = press(m, "u")
+	if got := stackNames(m); !slices.Equal(got, []string{"a", "c", "b"}) {
+		t.Errorf("stack %v after undo, want [a c b]", got)
+	}
+}
+
+func TestLayersHideAndLock(t *testing.T) {
+	m := press(newLayersModel(t), "H")
+	if c := m.canvas.Components[2]; !c.Hidden || m.canvas.Selectable(2) {
+		t.Fatal("H did not hide c")
+	}
+	m = press(m, "H")
+	if m.canvas.Components[2].Hidden {
+		t.Fatal("H again did not show c")
+	}
+
+	// A locked component stays put in move mode
+	m = press(m, "ctrl+l")
+	if !m.canvas.Components[2].Locked || m.canvas.Selectable(2) {
+		t.Fatal("ctrl+l did not lock c")
+	}
+	pos := m.canvas.Components[2].Position
+	m = press(m, "esc", "m", "right")
+	if got := m.canvas.Components[2].Position; got != pos || m.message != "Locked" {
+		t.Errorf("locked c moved to %v, message %q", got, m.message)
+	}
+
+	m = press(m, "u")
+	if m.canvas.Components[2].Locked {
+		t.Error("undo kept c locked")
+	}
+}
+
+func TestLayersRename(t *testing.T) {
+	m := press(newLayersModel(t), "r", "x", "esc")
+	if m.canvas.Components[2].Name != "c" || m.layers.rename != nil {
+		t.Fatal("esc did not drop the new name")
+	}
+	m = press(m, "r", "x", "enter")
+	if got := m.canvas.Components[2].Name; got != "cx" {
+		t.Errorf("renamed to %q, want cx", got)
+	}
+}
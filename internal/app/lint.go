@@ -0,0 +1,92 @@
+// Package app - Live lint panel
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/lint"
+)
+
+const lintPanelWidth = 36
+
+// relint lints the design again. It runs whenever the design changes,
+// after an edit, undo or redo, a new theme or a new size, so that
+// drawing the panel and the status bar does not lint on every frame.
+func (m *Model) relint() {
+	canvas := m.GetCanvasSchema()
+	m.diagnostics = m.linter.Run(&canvas)
+}
+
+// renderLintSummary renders the issue count shown in the status bar
+func (m Model) renderLintSummary() string {
+	diags := m.diagnostics
+	errs := lint.Count(diags, lint.SeverityError)
+	warns := lint.Count(diags, lint.SeverityWarning)
+
+	style := lipgloss.NewStyle().
+		Background(m.theme.SurfaceLight).
+		Padding(0, 1)
+
+	switch {
+	case errs > 0:
+		return style.Foreground(m.theme.Error).Render(fmt.Sprintf("✗ %d  ⚠ %d", errs, warns))
+	case warns > 0:
+		return style.Foreground(m.theme.Warning).Render(fmt.Sprintf("⚠ %d", warns))
+	default:
+		return style.Foreground(m.theme.Success).Render("✓ lint")
+	}
+}
+
+// renderLintPanel renders the live list of lint diagnostics
+func (m Model) renderLintPanel(width, height int) string {
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Primary).
+		Bold(true).
+		Render("🔍 Lint")
+
+	diags := m.diagnostics
+	innerWidth := width - 4
+
+	var lines []string
+	if len(diags) == 0 {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(m.theme.Success).
+			Render("No issues found"))
+	}
+
+	for _, d := range diags {
+		color := m.theme.Info
+		icon := "•"
+		switch d.Severity {
+		case lint.SeverityError:
+			color, icon = m.theme.Error, "✗"
+		case lint.SeverityWarning:
+			color, icon = m.theme.Warning, "⚠"
+		}
+
+		head := lipgloss.NewStyle().Foreground(color).Bold(true).
+			Render(icon + " " + d.Component)
+		body := lipgloss.NewStyle().Foreground(m.theme.TextSecondary).Width(innerWidth).
+			Render(d.Message)
+		entry := head + "\n" + body
+		if d.Fix != "" {
+			entry += "\n" + lipgloss.NewStyle().Foreground(m.theme.TextMuted).Italic(true).Width(innerWidth).
+				Render("→ "+d.Fix)
+		}
+		lines = append(lines, entry)
+	}
+
+	content := title + "\n\n" + strings.Join(lines, "\n\n")
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		MaxHeight(height+2).
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Border).
+		Padding(0, 1).
+		Render(content)
+}
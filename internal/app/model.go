@@ -2,12 +2,21 @@
 package app
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/internal/ui/preview"
 	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/lint"
 	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/templates"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+	"github.com/makeatui/makeatui/pkg/widgets/mouse"
+	"github.com/makeatui/makeatui/pkg/widgets/navigation"
 )
 
 // FocusArea represents which area of the UI is focused
@@ -17,6 +26,7 @@ const (
 	FocusSidebar FocusArea = iota
 	FocusCanvas
 	FocusProperties
+	FocusLayers
 )
 
 // Model is the main application model
@@ -30,8 +40,38 @@ type Model struct {
 	components []ComponentItem
 	selected   int
 	showHelp   bool
+	showLint   bool
 	quitting   bool
 	projectName string
+	linter     *lint.Linter
+	diagnostics []lint.Diagnostic // the design linted, see relint
+	themeEditor *themeEditor
+	inspector   *inspector
+	layers      *layersPanel
+	preview     *preview.Preview // the design running, see startPreview
+	gallery     *gallery         // the design at several sizes, see startGallery
+	sized       bool             // the design has a size of its own rather than the window's
+	sizePrompt  *input.TextInput // open while typing the size of the design
+	textEdit    *textEdit        // enter or i on a selected component, see startTextEdit
+	fitText     bool             // text edits resize their component to fit
+	templates   *templateBrowser    // B, see openTemplates
+	placing     *templates.Template // drawing the region to insert a template into
+	commands    *navigation.CommandPalette // ctrl+p, see openCommandPalette
+
+	// Every edit goes through the session, which keeps the undo history
+	session   *agent.Session
+	clipboard []schema.Component
+	nudging   string // ID of the component being moved, see nudgeSelected
+	message   string // shown in the status bar until the next key
+
+	project    *project
+	fileDialog *fileDialog
+	confirm    string // key to press again to discard changes, see confirmDiscard
+
+	// Mouse editing, see mouse.go
+	zones       *mouse.ZoneManager
+	drag        *dragState
+	contextMenu *contextMenu
 }
 
 // ComponentItem represents a component in the sidebar
@@ -59,20 +99,36 @@ func New() Model {
 		{Type: schema.TypeTabs, Name: "Tabs", Icon: "⊟"},
 	}
 
-	return Model{
+	projectName := "Untitled Project"
+	session := agent.NewSession(projectName, 60, 20)
+	c := canvas.New(60, 20, theme)
+	c.Components = session.Canvas.Components
+
+	m := Model{
 		theme:       theme,
 		styles:      s,
-		canvas:      canvas.New(60, 20, theme),
+		canvas:      c,
 		focus:       FocusSidebar,
 		components:  componentList,
 		selected:    0,
-		projectName: "Untitled Project",
+		projectName: projectName,
+		linter:      lint.New(lint.DefaultConfig()),
+		inspector:   newInspector(),
+		layers:      newLayersPanel(),
+		session:     session,
+		project:     &project{recovered: readRecovery("")},
+		zones:       mouse.NewZoneManager(),
+		commands:    navigation.NewCommandPalette("commands"),
 	}
+	m.session.SetTheme(theme.Name)
+	m.project.saved = m.GetCanvasSchema().Clone()
+	m.relint()
+	return m
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return nil
+	return autosave()
 }
 
 // KeyMap defines keyboard shortcuts
@@ -88,9 +144,47 @@ type KeyMap struct {
 	Quit     key.Binding
 	Export   key.Binding
 	MoveMod  key.Binding
+	Lint     key.Binding
+	Theme    key.Binding
+	ThemeEditor key.Binding
+	Save      key.Binding
+	SaveAs    key.Binding
+	Open      key.Binding
+	Undo      key.Binding
+	Redo      key.Binding
+	Copy      key.Binding
+	Cut       key.Binding
+	Paste     key.Binding
+	Duplicate key.Binding
+	Resize    key.Binding
+	Draw      key.Binding
+	BoxSelect key.Binding
+	SelectAll key.Binding
+	SameType  key.Binding
+	Invert    key.Binding
+	Arrange   key.Binding
+	Group     key.Binding
+	Ungroup   key.Binding
+	Snap      key.Binding
+	Grid      key.Binding
+	Layers    key.Binding
+	Preview   key.Binding
+	Gallery   key.Binding
+	Fit       key.Binding
+	Minimap   key.Binding
+	Size      key.Binding
+	Commands  key.Binding
+	Forward   key.Binding
+	Backward  key.Binding
+	ToFront   key.Binding
+	ToBack    key.Binding
+	EditText  key.Binding
+	Templates key.Binding
 }
 
-var keys = KeyMap{
+// defaultKeys are the bindings of the default preset. Their help keys
+// are labelled from the keys, see KeyMap.with.
+var defaultKeys = KeyMap{
 	Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
 	Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
 	Left:     key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")),
@@ -102,23 +196,132 @@ var keys = KeyMap{
 	Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
 	Export:   key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export")),
 	MoveMod:  key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "move mode")),
+	Lint:     key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "lint panel")),
+	Theme:    key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "switch theme")),
+	ThemeEditor: key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "generate theme")),
+	Save:      key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save")),
+	SaveAs:    key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "save as")),
+	Open:      key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "open")),
+	Undo:      key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo")),
+	Redo:      key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "redo")),
+	Copy:      key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy")),
+	Cut:       key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "cut")),
+	Paste:     key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "paste")),
+	Duplicate: key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "duplicate")),
+	Resize:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "resize mode")),
+	Draw:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "draw component")),
+	BoxSelect: key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "box select")),
+	SelectAll: key.NewBinding(key.WithKeys("ctrl+a"), key.WithHelp("ctrl+a", "select all")),
+	SameType:  key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "select same type")),
+	Invert:    key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "invert selection")),
+	Arrange:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "align/distribute")),
+	Group:     key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "group")),
+	Ungroup:   key.NewBinding(key.WithKeys("U"), key.WithHelp("U", "ungroup")),
+	Snap:      key.NewBinding(key.WithKeys("#"), key.WithHelp("#", "toggle snap")),
+	Grid:      key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "grid size")),
+	Layers:    key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "layers")),
+	Preview:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "preview")),
+	Gallery:   key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "preview sizes")),
+	Fit:       key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "fit to view")),
+	Minimap:   key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "minimap")),
+	Size:      key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "design size")),
+	Commands:  key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "commands")),
+	Forward:   key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "bring forward")),
+	Backward:  key.NewBinding(key.WithKeys("["), key.WithHelp("[", "send backward")),
+	ToFront:   key.NewBinding(key.WithKeys("}"), key.WithHelp("}", "bring to front")),
+	ToBack:    key.NewBinding(key.WithKeys("{"), key.WithHelp("{", "send to back")),
+	EditText:  key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "edit text")),
+	Templates: key.NewBinding(key.WithKeys("B"), key.WithHelp("B", "templates")),
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok {
+		// Panels open and close with focus, so keep the canvas sized
+		// to the area it is drawn in
+		nm.fitCanvas()
+		// The view follows the cursor as keys move it; the mouse only
+		// puts the cursor where it points, which is in view already
+		if _, ok := msg.(tea.MouseMsg); ok {
+			nm.canvas.HoldView()
+		} else {
+			nm.canvas.FollowCursor()
+		}
+		if nm.focus == FocusLayers {
+			nm.refreshLayers()
+		}
+		nm.registerZones()
+		return nm, cmd
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Resize canvas
-		canvasWidth := m.width - 30 - 4 // sidebar width + padding
-		canvasHeight := m.height - 6     // toolbar + statusbar
-		m.canvas = canvas.New(canvasWidth, canvasHeight, m.theme)
+
+	case autosaveMsg:
+		if err := m.writeRecovery(); err != nil {
+			m.message = "Autosave failed: " + err.Error()
+		}
+		return m, autosave()
+
+	case tea.MouseMsg:
+		return m.updateMouse(msg)
+
+	case menuActionMsg:
+		m.runMenuAction(msg)
+		return m, nil
+
+	case paletteActionMsg:
+		return m, m.runDesignerCommand(msg)
 
 	case tea.KeyMsg:
+		m.message = ""
+		m.canvas.Guides = nil
+		if m.confirm != msg.String() {
+			m.confirm = ""
+		}
+
+		if msg.String() == "ctrl+c" {
+			return m.quit(true)
+		}
+
+		// Prompts and open editors take every other key
+		switch {
+		case m.project.recovered != nil:
+			return m.updateRecoveryPrompt(msg)
+		case m.fileDialog != nil:
+			return m.updateFileDialog(msg)
+		case m.contextMenu != nil:
+			return m.updateContextMenuKeys(msg)
+		case m.focus == FocusProperties && m.inspector.editing:
+			return m.updateProperties(msg)
+		case m.focus == FocusLayers && m.layers.rename != nil:
+			return m.updateLayers(msg)
+		case m.preview != nil:
+			return m.updatePreview(msg)
+		case m.gallery != nil:
+			return m.updateGallery(msg)
+		case m.sizePrompt != nil:
+			return m.updateSizePrompt(msg)
+		case m.textEdit != nil:
+			return m.updateTextEdit(msg)
+		case m.templates != nil:
+			return m.updateTemplates(msg)
+		case m.commands.Visible:
+			return m.updateCommandPalette(msg)
+		}
+
 		if key.Matches(msg, keys.Quit) {
-			m.quitting = true
-			return m, tea.Quit
+			return m.quit(false)
+		}
+
+		if m.themeEditor != nil {
+			return m.updateThemeEditor(msg)
 		}
 
 		if key.Matches(msg, keys.Help) {
@@ -126,8 +329,106 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if key.Matches(msg, keys.Commands) {
+			m.openCommandPalette()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Lint) {
+			m.showLint = !m.showLint
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Theme) {
+			m.cycleTheme()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.ThemeEditor) {
+			m.themeEditor = newThemeEditor(m.theme)
+			m.applyTheme(m.themeEditor.theme)
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Save) {
+			m.saveProject()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.SaveAs) {
+			m.openFileDialog(dialogSaveAs)
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Open) {
+			if m.confirmDiscard(shortKey(keys.Open)) {
+				m.openFileDialog(dialogOpen)
+			}
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Export) {
+			m.openFileDialog(dialogExport)
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Undo) {
+			m.undo()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Redo) {
+			m.redo()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Snap) {
+			m.toggleSnap()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Grid) {
+			m.cycleGrid()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Preview) {
+			m.startPreview()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Gallery) {
+			m.startGallery()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Templates) {
+			m.openTemplates("")
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Fit) {
+			m.toggleFit()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Minimap) {
+			m.toggleMinimap()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Size) {
+			m.openSizePrompt()
+			return m, nil
+		}
+
+		if key.Matches(msg, keys.Layers) {
+			m.toggleLayers()
+			return m, nil
+		}
+
 		if key.Matches(msg, keys.Tab) {
-			m.focus = (m.focus + 1) % 3
+			m.focus = (m.focus + 1) % 4
 			return m, nil
 		}
 
@@ -136,6 +437,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateSidebar(msg)
 		case FocusCanvas:
 			return m.updateCanvas(msg)
+		case FocusProperties:
+			return m.updateProperties(msg)
+		case FocusLayers:
+			return m.updateLayers(msg)
 		}
 	}
 
@@ -154,79 +459,185 @@ func (m Model) updateSidebar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case key.Matches(msg, keys.Enter):
 		comp := m.components[m.selected]
-		newComp := schema.NewComponent(comp.Type, comp.Name)
-		newComp.Text = comp.Name
-		m.canvas.AddComponent(newComp)
+		m.addComponent(comp.Type, comp.Name)
 		m.focus = FocusCanvas
+	case key.Matches(msg, keys.Draw):
+		m.startDrawing()
 	}
 	return m, nil
 }
 
 func (m Model) updateCanvas(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.canvas.Mode == canvas.ModeDraw && m.updateDraw(msg) {
+		return m, nil
+	}
+	if m.canvas.Mode != canvas.ModeDraw && m.canvas.Anchor != nil && m.updateBoxSelect(msg.String()) {
+		return m, nil
+	}
+	if m.updateViewport(msg) {
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, keys.Up):
-		if m.canvas.Mode == canvas.ModeMove && m.canvas.Selected >= 0 {
-			m.canvas.MoveSelected(0, -1)
-		} else {
+		if !m.arrowEdit(0, -1) {
 			m.canvas.CursorY--
 			if m.canvas.CursorY < 0 {
 				m.canvas.CursorY = 0
 			}
 		}
 	case key.Matches(msg, keys.Down):
-		if m.canvas.Mode == canvas.ModeMove && m.canvas.Selected >= 0 {
-			m.canvas.MoveSelected(0, 1)
-		} else {
+		if !m.arrowEdit(0, 1) {
 			m.canvas.CursorY++
 			if m.canvas.CursorY >= m.canvas.Height {
 				m.canvas.CursorY = m.canvas.Height - 1
 			}
 		}
 	case key.Matches(msg, keys.Left):
-		if m.canvas.Mode == canvas.ModeMove && m.canvas.Selected >= 0 {
-			m.canvas.MoveSelected(-1, 0)
-		} else {
+		if !m.arrowEdit(-1, 0) {
 			m.canvas.CursorX--
 			if m.canvas.CursorX < 0 {
 				m.canvas.CursorX = 0
 			}
 		}
 	case key.Matches(msg, keys.Right):
-		if m.canvas.Mode == canvas.ModeMove && m.canvas.Selected >= 0 {
-			m.canvas.MoveSelected(1, 0)
-		} else {
+		if !m.arrowEdit(1, 0) {
 			m.canvas.CursorX++
 			if m.canvas.CursorX >= m.canvas.Width {
 				m.canvas.CursorX = m.canvas.Width - 1
 			}
 		}
 	case key.Matches(msg, keys.Delete):
-		m.canvas.RemoveSelected()
+		m.removeSelected()
+	case key.Matches(msg, keys.Copy):
+		m.copySelected()
+	case key.Matches(msg, keys.Cut):
+		m.cutSelected()
+	case key.Matches(msg, keys.Paste):
+		m.paste()
+	case key.Matches(msg, keys.Duplicate):
+		m.duplicateSelected()
+	case key.Matches(msg, keys.BoxSelect):
+		m.startBoxSelect()
+	case key.Matches(msg, keys.SelectAll):
+		m.selectAll()
+	case key.Matches(msg, keys.SameType):
+		m.selectSameType()
+	case key.Matches(msg, keys.Invert):
+		m.invertSelection()
+	case key.Matches(msg, keys.Arrange):
+		ox, oy := m.canvasOrigin()
+		x, y := m.canvas.ToView(m.canvas.CursorX, m.canvas.CursorY)
+		m.openArrangeMenu(ox+x+1, oy+y+1)
+	case key.Matches(msg, keys.Group):
+		m.groupSelected()
+	case key.Matches(msg, keys.Ungroup):
+		m.ungroupSelected()
+	case key.Matches(msg, keys.Forward, keys.Backward, keys.ToFront, keys.ToBack):
+		m.restackSelected(restackStep(msg))
+	case key.Matches(msg, keys.Draw):
+		m.startDrawing()
 	case key.Matches(msg, keys.MoveMod):
-		if m.canvas.Mode == canvas.ModeMove {
-			m.canvas.Mode = canvas.ModeSelect
+		m.toggleMode(canvas.ModeMove)
+	case key.Matches(msg, keys.Resize):
+		m.toggleMode(canvas.ModeResize)
+	case key.Matches(msg, keys.EditText):
+		m.startTextEdit()
+	case key.Matches(msg, keys.Enter):
+		// Select the component at the cursor, or edit its text when it
+		// is already selected
+		if i := m.componentAtCursor(); i >= 0 && i == m.canvas.Selected {
+			m.startTextEdit()
 		} else {
-			m.canvas.Mode = canvas.ModeMove
+			m.canvas.Select(i)
 		}
-	case key.Matches(msg, keys.Enter):
-		// Select component at cursor position
-		m.selectComponentAtCursor()
 	}
 	return m, nil
 }
 
-// selectComponentAtCursor selects the component at the current cursor position
-func (m *Model) selectComponentAtCursor() {
-	for i, comp := range m.canvas.Components {
-		if m.canvas.CursorX >= comp.Position.X &&
+// arrowEdit moves or resizes the selection with an arrow key in move
+// and resize mode. It reports false when the arrow moves the cursor.
+func (m *Model) arrowEdit(dx, dy int) bool {
+	if m.canvas.Selected < 0 {
+		return false
+	}
+	switch m.canvas.Mode {
+	case canvas.ModeMove:
+		m.nudgeSelected(dx, dy)
+	case canvas.ModeResize:
+		m.resizeSelected(dx, dy)
+	default:
+		return false
+	}
+	return true
+}
+
+// toggleMode switches between select mode and move or resize mode
+func (m *Model) toggleMode(mode canvas.Mode) {
+	m.canvas.StopDrawing()
+	m.nudging = ""
+	if m.canvas.Mode == mode {
+		m.canvas.Mode = canvas.ModeSelect
+	} else {
+		m.canvas.Mode = mode
+	}
+}
+
+// cycleTheme switches the design to the next registered theme.
+// Components styled with tokens like "$primary" recolor automatically.
+func (m *Model) cycleTheme() {
+	themes := styles.All()
+	next := themes[0]
+	for i, t := range themes {
+		if strings.EqualFold(t.Name, m.theme.Name) {
+			next = themes[(i+1)%len(themes)]
+			break
+		}
+	}
+	m.applyTheme(next)
+}
+
+// fitCanvas sizes the canvas view to its on-screen area, and the
+// design too unless it has a size of its own, keeping the cursor
+// inside the design
+func (m *Model) fitCanvas() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+	_, width, _, height := m.layout()
+	viewWidth := max(width-2-canvas.RulerWidth, 1) // horizontal padding and left ruler
+	viewHeight := max(height-2, 1)                 // top ruler and mode line
+	if !m.sized && (m.session.Canvas.Width != viewWidth || m.session.Canvas.Height != viewHeight) {
+		m.session.Resize(viewWidth, viewHeight)
+		m.relint()
+	}
+	m.canvas.Width, m.canvas.Height = max(m.session.Canvas.Width, 1), max(m.session.Canvas.Height, 1)
+	m.canvas.SetView(viewWidth, viewHeight)
+	m.canvas.CursorX = min(m.canvas.CursorX, m.canvas.Width-1)
+	m.canvas.CursorY = min(m.canvas.CursorY, m.canvas.Height-1)
+	if m.preview != nil {
+		m.preview.Width, m.preview.Height = viewWidth+canvas.RulerWidth, viewHeight+1
+	}
+	if m.gallery != nil {
+		m.scrollGallery(0, 0)
+	}
+}
+
+// componentAtCursor returns the index of the topmost selectable
+// component under the cursor, or -1
+func (m *Model) componentAtCursor() int {
+	order := m.canvas.StackOrder()
+	for k := len(order) - 1; k >= 0; k-- {
+		i, comp := order[k], m.canvas.Components[order[k]]
+		if m.canvas.Selectable(i) &&
+			m.canvas.CursorX >= comp.Position.X &&
 			m.canvas.CursorX < comp.Position.X+comp.Size.Width &&
 			m.canvas.CursorY >= comp.Position.Y &&
 			m.canvas.CursorY < comp.Position.Y+comp.Size.Height {
-			m.canvas.Selected = i
-			return
+			return i
 		}
 	}
-	m.canvas.Selected = -1
+	return -1
 }
 
 // View renders the UI - continued in view.go
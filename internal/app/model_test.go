@@ -0,0 +1,82 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/internal/config"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// keyTypes maps key names such as "enter" or "ctrl+z" to their types
+var keyTypes = func() map[string]tea.KeyType {
+	types := map[string]tea.KeyType{}
+	for k := tea.KeyType(-200); k < 200; k++ {
+		if name := k.String(); name != "" {
+			types[name] = k
+		}
+	}
+	return types
+}()
+
+// newTestModel returns the designer in a 120×40 window, with its config
+// and recovery files in a temporary directory
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv(config.EnvDir, t.TempDir())
+	return send(New(), tea.WindowSizeMsg{Width: 120, Height: 40})
+}
+
+// send passes messages through Update as the program would
+func send(m Model, msgs ...tea.Msg) Model {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+// press sends keys by name: "enter", "ctrl+z", or runes typed as is
+func press(m Model, names ...string) Model {
+	for _, name := range names {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+		if k, ok := keyTypes[name]; ok {
+			msg = tea.KeyMsg{Type: k}
+		}
+		if msg.Type == tea.KeySpace {
+			msg.Runes = []rune{' '} // as the terminal sends it
+		}
+		m = send(m, msg)
+	}
+	return m
+}
+
+func countRule(diags []lint.Diagnostic, rule string) int {
+	n := 0
+	for _, d := range diags {
+		if d.Rule == rule {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLintFollowsEdits(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "a", Width: 10, Height: 4})
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "b", X: 5, Y: 2, Width: 10, Height: 4})
+	if n := countRule(m.diagnostics, lint.RuleOverlap); n != 1 {
+		t.Fatalf("%d overlaps after adding b over a, want 1", n)
+	}
+
+	m = press(m, "tab", "u")
+	if n := countRule(m.diagnostics, lint.RuleOverlap); n != 0 {
+		t.Errorf("%d overlaps after undoing b, want 0", n)
+	}
+	m = press(m, "ctrl+r")
+	if n := countRule(m.diagnostics, lint.RuleOverlap); n != 1 {
+		t.Errorf("%d overlaps after redoing b, want 1", n)
+	}
+}
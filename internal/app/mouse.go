@@ -0,0 +1,473 @@
+// Package app - Mouse editing on the canvas
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/widgets/mouse"
+	"github.com/makeatui/makeatui/pkg/widgets/navigation"
+)
+
+// Zone IDs. Components and handles carry their index or handle in Data.
+const (
+	zoneCanvas    = "canvas"
+	zoneComponent = "comp:"
+	zoneHandle    = "handle:"
+	zonePalette   = "palette:"
+	zoneLayer     = "layer:"
+	zoneMinimap   = "minimap"
+)
+
+// paletteTop is the screen row of the first sidebar item: below the
+// toolbar, the border, the padding and the title
+const paletteTop = 6
+
+// paletteItem is the Data of a sidebar item's zone
+type paletteItem int
+
+// layerRow is the Data of a row of the layers panel
+type layerRow int
+
+// dragState is a move, resize or box selection in progress
+type dragState struct {
+	edge  canvas.Edge // handle being dragged, 0 when moving
+	x, y  int         // canvas cell the drag started on
+	start []schema.Component
+	moved bool        // an undo step has been recorded
+	mode  canvas.Mode // mode to return to when the drag ends
+	box   bool        // dragging out a selection rectangle
+	add   bool        // the rectangle adds to the selection
+}
+
+// menuActionMsg is sent when a context menu item is chosen
+type menuActionMsg string
+
+// contextMenu is a right-click menu open at X, Y on screen
+type contextMenu struct {
+	menu *navigation.Menu
+	x, y int
+}
+
+// canvasOrigin returns the screen cell of the canvas's top-left corner:
+// past the toolbar, the sidebar, the canvas border and padding and the
+// rulers
+func (m Model) canvasOrigin() (int, int) {
+	sidebarWidth, _, _, _ := m.layout()
+	return sidebarWidth + 4 + canvas.RulerWidth, 3
+}
+
+// mouseEnabled reports whether the canvas takes the mouse, which it
+// does unless a prompt, dialog or editor has the keyboard
+func (m Model) mouseEnabled() bool {
+	return m.project.recovered == nil && m.fileDialog == nil && !m.showHelp &&
+		m.themeEditor == nil && !m.inspector.editing && m.layers.rename == nil && m.sizePrompt == nil &&
+		!m.commands.Visible && m.textEdit == nil && m.templates == nil
+}
+
+// registerZones maps the components and handles drawn on the canvas to
+// their screen areas. Later components are on top.
+func (m *Model) registerZones() {
+	m.zones.Clear()
+	ox, oy := m.canvasOrigin()
+	m.zones.Register(&mouse.Zone{
+		ID: zoneCanvas, X: ox, Y: oy,
+		Width: m.canvas.ViewWidth, Height: m.canvas.ViewHeight,
+		Z: -1, Cursor: mouse.CursorCrosshair,
+	})
+	if x, y, w, h, _, ok := m.canvas.Minimap(); ok {
+		m.zones.Register(&mouse.Zone{
+			ID: zoneMinimap, X: ox + x, Y: oy + y, Width: w, Height: h,
+			Z: len(m.canvas.Components) + 1, Cursor: mouse.CursorPointer,
+		})
+	}
+
+	sidebarWidth, _, _, _ := m.layout()
+	for i := range m.components {
+		m.zones.Register(&mouse.Zone{
+			ID: fmt.Sprintf("%s%d", zonePalette, i),
+			X:  1, Y: paletteTop + i, Width: sidebarWidth, Height: 1,
+			Cursor: mouse.CursorPointer, Data: paletteItem(i),
+		})
+	}
+
+	if m.focus == FocusLayers {
+		_, canvasWidth, panelWidth, _ := m.layout()
+		x := sidebarWidth + 2 + canvasWidth + 2 + 2
+		rows := min(len(m.layers.tree.VisibleNodes())-m.layers.offset, m.layerRows())
+		for i := 0; i < rows; i++ {
+			m.zones.Register(&mouse.Zone{
+				ID: fmt.Sprintf("%s%d", zoneLayer, i),
+				X:  x, Y: 1 + layersTop + i, Width: panelWidth - 2, Height: 1,
+				Cursor: mouse.CursorPointer, Data: layerRow(m.layers.offset + i),
+			})
+		}
+	}
+
+	// In fit mode a click only picks where to go back to full size
+	if m.canvas.Fit {
+		return
+	}
+
+	// Components and handles where they are in view
+	c := m.canvas
+	for z, i := range c.StackOrder() {
+		comp := c.Components[i]
+		if !c.Selectable(i) {
+			continue
+		}
+		x0, y0 := max(comp.Position.X, c.ScrollX, 0), max(comp.Position.Y, c.ScrollY, 0)
+		x1 := min(comp.Position.X+comp.Size.Width, c.Width, c.ScrollX+c.ViewWidth)
+		y1 := min(comp.Position.Y+comp.Size.Height, c.Height, c.ScrollY+c.ViewHeight)
+		if x1 <= x0 || y1 <= y0 {
+			continue
+		}
+		m.zones.Register(&mouse.Zone{
+			ID: fmt.Sprintf("%s%d", zoneComponent, i),
+			X:  ox + x0 - c.ScrollX, Y: oy + y0 - c.ScrollY, Width: x1 - x0, Height: y1 - y0,
+			Z: z, Cursor: mouse.CursorMove, Data: i,
+		})
+	}
+
+	if sel := c.SelectedIndices(); len(sel) == 1 {
+		for i, h := range canvas.Handles(c.Components[sel[0]]) {
+			x, y := c.ToView(h.X, h.Y)
+			if x < 0 || y < 0 || x >= c.ViewWidth || y >= c.ViewHeight {
+				continue
+			}
+			m.zones.Register(&mouse.Zone{
+				ID: fmt.Sprintf("%s%d", zoneHandle, i),
+				X:  ox + x, Y: oy + y, Width: 1, Height: 1,
+				Z: len(c.Components), Cursor: handleCursor(h.Edge), Data: h,
+			})
+		}
+	}
+}
+
+// handleCursor is the resize cursor for a handle
+func handleCursor(edge canvas.Edge) mouse.CursorType {
+	switch edge {
+	case canvas.EdgeTop, canvas.EdgeBottom:
+		return mouse.CursorResizeNS
+	case canvas.EdgeLeft, canvas.EdgeRight:
+		return mouse.CursorResizeEW
+	case canvas.EdgeTop | canvas.EdgeLeft, canvas.EdgeBottom | canvas.EdgeRight:
+		return mouse.CursorResizeNWSE
+	}
+	return mouse.CursorResizeNESW
+}
+
+// updateMouse handles clicks and drags
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.contextMenu != nil {
+		return m.updateContextMenu(msg)
+	}
+	if m.preview != nil {
+		return m.previewMouse(msg)
+	}
+	if m.gallery != nil {
+		return m.galleryMouse(msg)
+	}
+	if !m.mouseEnabled() {
+		return m, nil
+	}
+
+	ox, oy := m.canvasOrigin()
+	x, y := m.canvas.FromView(msg.X-ox, msg.Y-oy)
+
+	zone := m.zones.HitTest(msg.X, msg.Y)
+	if zone != nil && msg.Action == tea.MouseActionPress {
+		if item, ok := zone.Data.(paletteItem); ok {
+			// Picking a component type starts drawing one
+			if msg.Button == tea.MouseButtonLeft {
+				m.selected = int(item)
+				m.startDrawing()
+			}
+			return m, nil
+		}
+		if row, ok := zone.Data.(layerRow); ok {
+			m.layers.tree.SetCursor(int(row))
+			m.selectLayerAtCursor()
+			return m, nil
+		}
+	}
+	if m.drag == nil && m.viewportMouse(msg, zone) {
+		return m, nil
+	}
+	if m.canvas.Mode == canvas.ModeDraw {
+		switch {
+		case msg.Action != tea.MouseActionPress:
+			m.drawMouse(msg, x, y)
+		case zone == nil:
+		case msg.Button == tea.MouseButtonRight:
+			m.stopDrawing()
+		default:
+			m.drawMouse(msg, x, y)
+		}
+		return m, nil
+	}
+
+	switch msg.Action {
+	case tea.MouseActionMotion:
+		if m.drag != nil {
+			m.dragTo(x, y)
+		}
+		return m, nil
+	case tea.MouseActionRelease:
+		if m.drag != nil {
+			if m.drag.box {
+				m.finishBoxSelect(m.drag.add)
+			}
+			m.canvas.Mode = m.drag.mode
+			m.canvas.Guides = nil
+			m.drag = nil
+		}
+		return m, nil
+	}
+
+	if msg.Action != tea.MouseActionPress || zone == nil {
+		return m, nil
+	}
+
+	m.focus = FocusCanvas
+	m.canvas.CursorX, m.canvas.CursorY = x, y
+
+	switch msg.Button {
+	case tea.MouseButtonLeft:
+		switch data := zone.Data.(type) {
+		case canvas.Handle:
+			m.startDrag(x, y, data.Edge, canvas.ModeResize)
+		case int:
+			if msg.Shift {
+				m.canvas.ToggleSelect(data)
+			} else if !m.canvas.IsSelected(data) {
+				m.canvas.Select(data)
+			}
+			if m.canvas.IsSelected(data) {
+				m.startDrag(x, y, 0, canvas.ModeMove)
+			}
+		default:
+			// Dragging over empty canvas selects what it encloses
+			if !msg.Shift {
+				m.canvas.Select(-1)
+			}
+			m.startBoxSelect()
+			m.drag = &dragState{box: true, add: msg.Shift, mode: m.canvas.Mode}
+		}
+
+	case tea.MouseButtonRight:
+		switch data := zone.Data.(type) {
+		case int:
+			if !m.canvas.IsSelected(data) {
+				m.canvas.Select(data)
+			}
+		case nil:
+			m.canvas.Select(-1)
+		}
+		m.openContextMenu(msg.X, msg.Y)
+	}
+	return m, nil
+}
+
+// startDrag begins moving the selection, or resizing it from a handle
+func (m *Model) startDrag(x, y int, edge canvas.Edge, mode canvas.Mode) {
+	m.drag = &dragState{edge: edge, x: x, y: y, start: m.movable(), mode: m.canvas.Mode}
+	m.canvas.Mode = mode
+}
+
+// dragTo moves or resizes the dragged components to follow the mouse,
+// snapping them to the grid and to the other components. The whole drag
+// is a single undo step.
+func (m *Model) dragTo(x, y int) {
+	d := m.drag
+	if d.box {
+		m.canvas.CursorX = min(max(x, 0), m.canvas.Width-1)
+		m.canvas.CursorY = min(max(y, 0), m.canvas.Height-1)
+		return
+	}
+	dx, dy := x-d.x, y-d.y
+	if !d.moved && dx == 0 && dy == 0 {
+		return
+	}
+
+	snapper := m.canvas.Snapper(m.canvas.IsSelected)
+	if d.edge == 0 {
+		pos, size := canvas.Bounds(d.start)
+		pos.X += dx
+		pos.Y += dy
+		sx, sy := snapper.Move(pos, size)
+		dx, dy = dx+sx, dy+sy
+	}
+
+	var edits []edit
+	for _, comp := range d.start {
+		pos, size := comp.Position, comp.Size
+		if d.edge != 0 {
+			pos, size = canvas.Resize(pos, size, d.edge, dx, dy)
+			pos, size = snapper.Resize(pos, size, d.edge)
+			edits = append(edits, edit{agent.CmdResizeComponent, agent.ResizeComponentParams{
+				ID: comp.ID, Width: size.Width, Height: size.Height,
+			}})
+		} else {
+			pos.X += dx
+			pos.Y += dy
+		}
+		edits = append(edits, edit{agent.CmdMoveComponent, agent.MoveComponentParams{
+			ID: comp.ID, X: pos.X, Y: pos.Y,
+		}})
+	}
+	if m.execEdits(d.moved, edits...) == nil {
+		d.moved = true
+	}
+	m.showGuides()
+	m.canvas.CursorX = min(max(x, 0), m.canvas.Width-1)
+	m.canvas.CursorY = min(max(y, 0), m.canvas.Height-1)
+}
+
+// menuEntry is an item of a context menu
+type menuEntry struct {
+	id, label, shortcut string
+	disabled            bool
+}
+
+// openContextMenu shows the actions for the selection at x, y on screen,
+// or says there are none
+func (m *Model) openContextMenu(x, y int) {
+	none := len(m.canvas.SelectedIndices()) == 0
+	grouped := false
+	for _, c := range m.selection() {
+		grouped = grouped || len(c.Children) > 0
+	}
+	if !m.openMenu([]menuEntry{
+		{"properties", "Properties", shortKey(keys.Tab), m.canvas.GetSelected() == nil},
+		{"edit-text", "Edit text", shortKey(keys.EditText), m.canvas.GetSelected() == nil},
+		{"duplicate", "Duplicate", shortKey(keys.Duplicate), none},
+		{"copy", "Copy", shortKey(keys.Copy), none},
+		{"cut", "Cut", shortKey(keys.Cut), none},
+		{"paste", "Paste", shortKey(keys.Paste), len(m.clipboard) == 0},
+		{"delete", "Delete", shortKey(keys.Delete), none},
+		{"group", "Group", shortKey(keys.Group), none},
+		{"ungroup", "Ungroup", shortKey(keys.Ungroup), !grouped},
+		{"arrange", "Arrange…", shortKey(keys.Arrange), len(m.canvas.SelectedIndices()) < 2},
+		{"forward", "Bring forward", shortKey(keys.Forward), none},
+		{"backward", "Send backward", shortKey(keys.Backward), none},
+	}, x, y) {
+		m.message = "Nothing to do here: select a component or copy one to paste"
+	}
+}
+
+// openMenu opens a menu of entries at x, y on screen, each sending a
+// menuActionMsg with its id when chosen. A menu with every entry
+// disabled is not opened, and openMenu reports false.
+func (m *Model) openMenu(entries []menuEntry, x, y int) bool {
+	width, enabled := 0, false
+	for _, e := range entries {
+		width = max(width, lipgloss.Width(e.label))
+		enabled = enabled || !e.disabled
+	}
+	if !enabled {
+		return false
+	}
+
+	menu := navigation.NewMenu("context")
+	menu.Selected = -1
+	for _, e := range entries {
+		id := e.id
+		menu.Items = append(menu.Items, &navigation.MenuItem{
+			ID:       id,
+			Label:    e.label + strings.Repeat(" ", width-lipgloss.Width(e.label)),
+			Shortcut: e.shortcut,
+			Disabled: e.disabled,
+			Action:   func() tea.Cmd { return func() tea.Msg { return menuActionMsg(id) } },
+		})
+		if menu.Selected < 0 && !e.disabled {
+			menu.Selected = len(menu.Items) - 1
+		}
+	}
+	menu.Open = true
+
+	// Keep the menu on screen
+	w, h := lipgloss.Size(menu.View())
+	x = max(min(x, m.width-w), 0)
+	y = max(min(y, m.height-h), 0)
+	m.contextMenu = &contextMenu{menu: menu, x: x, y: y}
+	return true
+}
+
+// updateContextMenu passes the mouse to the open context menu. A click
+// anywhere else closes it.
+func (m Model) updateContextMenu(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	cm := m.contextMenu
+	w, h := lipgloss.Size(cm.menu.View())
+	inside := msg.X >= cm.x && msg.X < cm.x+w && msg.Y >= cm.y && msg.Y < cm.y+h
+	if !inside {
+		if msg.Action == tea.MouseActionPress {
+			m.contextMenu = nil
+		}
+		return m, nil
+	}
+	msg.X -= cm.x
+	msg.Y -= cm.y
+	_, cmd := cm.menu.Update(msg)
+	return m, cmd
+}
+
+// updateContextMenuKeys lets the keyboard drive the open context menu
+func (m Model) updateContextMenuKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.contextMenu = nil
+		return m, nil
+	}
+	_, cmd := m.contextMenu.menu.Update(msg)
+	return m, cmd
+}
+
+// runMenuAction carries out a context menu choice
+func (m *Model) runMenuAction(id menuActionMsg) {
+	cm := m.contextMenu
+	m.contextMenu = nil
+	if m.runArrangeAction(string(id)) {
+		return
+	}
+	switch id {
+	case "properties":
+		m.focus = FocusProperties
+	case "edit-text":
+		m.focus = FocusCanvas
+		m.startTextEdit()
+	case "duplicate":
+		m.duplicateSelected()
+	case "copy":
+		m.copySelected()
+	case "cut":
+		m.cutSelected()
+	case "paste":
+		m.paste()
+	case "delete":
+		m.removeSelected()
+	case "group":
+		m.groupSelected()
+	case "ungroup":
+		m.ungroupSelected()
+	case "forward":
+		m.restackSelected(1)
+	case "backward":
+		m.restackSelected(-1)
+	case "arrange":
+		if cm != nil {
+			m.openArrangeMenu(cm.x, cm.y)
+		}
+	}
+}
+
+// renderContextMenu draws the open context menu over view
+func (m Model) renderContextMenu(view string) string {
+	cm := m.contextMenu
+	return compositor.Overlay(view, cm.menu.View(), cm.x, cm.y)
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// mouseAt sends a mouse event at design cell x, y of the canvas
+func mouseAt(m Model, button tea.MouseButton, action tea.MouseAction, x, y int) Model {
+	ox, oy := m.canvasOrigin()
+	vx, vy := m.canvas.ToView(x, y)
+	return send(m, tea.MouseMsg{X: ox + vx, Y: oy + vy, Button: button, Action: action})
+}
+
+// screenAt returns what the view shows at design cell x, y of the canvas
+func screenAt(m Model, x, y int) string {
+	ox, oy := m.canvasOrigin()
+	vx, vy := m.canvas.ToView(x, y)
+	lines := strings.Split(regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(m.View(), ""), "\n")
+	if oy+vy >= len(lines) {
+		return ""
+	}
+	line := []rune(lines[oy+vy])
+	if ox+vx >= len(line) {
+		return ""
+	}
+	return string(line[ox+vx])
+}
+
+func TestContextMenuOnEmptyDesign(t *testing.T) {
+	// Nothing is selected and the clipboard is empty, so every entry
+	// would be disabled
+	m := mouseAt(newTestModel(t), tea.MouseButtonRight, tea.MouseActionPress, 5, 5)
+	if m.contextMenu != nil {
+		t.Fatal("opened a context menu with nothing enabled")
+	}
+	if m.message == "" {
+		t.Error("no message in place of the menu")
+	}
+	m = press(m, "up", "k", "down") // used to panic with nothing selectable
+	if m.contextMenu != nil {
+		t.Error("keys opened a context menu")
+	}
+}
+
+func TestContextMenu(t *testing.T) {
+	m := press(newTestModel(t), "enter", "esc") // add a box, then deselect it
+	box := m.canvas.Components[0].Position
+	m = mouseAt(m, tea.MouseButtonRight, tea.MouseActionPress, box.X+1, box.Y+1)
+	if m.contextMenu == nil || m.canvas.GetSelected() == nil {
+		t.Fatal("right-clicking a component did not select it and open a menu")
+	}
+	m = press(m, "up", "down", "esc")
+	if m.contextMenu != nil {
+		t.Error("esc left the context menu open")
+	}
+}
+
+func TestDragMove(t *testing.T) {
+	m := newTestModel(t)
+	// On the 4×2 snap grid, so only the drag decides where it lands
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "box", X: 32, Y: 10, Width: 20, Height: 4})
+	m.canvas.Select(0)
+	m.registerZones()
+	start := m.canvas.GetSelected().Position
+	steps := len(m.session.UndoStack)
+
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionPress, start.X+1, start.Y+1)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionMotion, start.X+3, start.Y+2)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionMotion, start.X+9, start.Y+5)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionRelease, start.X+9, start.Y+5)
+	if m.drag != nil {
+		t.Fatal("still dragging after release")
+	}
+	if got := m.canvas.GetSelected().Position; got.X != start.X+8 || got.Y != start.Y+4 {
+		t.Errorf("dragged to %d,%d, want %d,%d", got.X, got.Y, start.X+8, start.Y+4)
+	}
+	if got := len(m.session.UndoStack) - steps; got != 1 {
+		t.Errorf("the drag took %d undo steps, want 1", got)
+	}
+
+	m = press(m, "u")
+	if got := m.canvas.GetSelected().Position; got != start {
+		t.Errorf("at %d,%d after undo, want %d,%d", got.X, got.Y, start.X, start.Y)
+	}
+}
+
+func TestClickOnBorder(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "box", X: 32, Y: 10, Width: 20, Height: 4})
+	m.canvas.Select(-1)
+	m.registerZones()
+	box := m.canvas.Components[0]
+	if box.Style.Border == nil {
+		t.Fatal("a new box has no border")
+	}
+	right, bottom := box.Position.X+box.Size.Width-1, box.Position.Y+box.Size.Height-1
+	if got := screenAt(m, right, bottom); got != "╯" {
+		t.Fatalf("drawn %q at the corner of the box, want its border", got)
+	}
+	if got := screenAt(m, right+1, bottom+1); strings.ContainsAny(got, "╯─│") {
+		t.Fatalf("border drawn past the size of the box: %q", got)
+	}
+
+	// The bottom right corner is drawn border, the last cell of the box
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionPress, right, bottom)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionRelease, right, bottom)
+	if m.canvas.Selected != 0 {
+		t.Fatal("clicking the border of a box did not select it")
+	}
+	m.registerZones()
+	var onCorner bool
+	for _, h := range canvas.Handles(m.canvas.Components[0]) {
+		onCorner = onCorner || h.X == right && h.Y == bottom
+	}
+	if !onCorner {
+		t.Errorf("no resize handle on the corner at %d,%d", right, bottom)
+	}
+
+	// Just past the border is empty canvas
+	m.canvas.Select(-1)
+	m.registerZones()
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionPress, right+1, bottom+1)
+	m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionRelease, right+1, bottom+1)
+	if m.canvas.Selected != -1 {
+		t.Error("clicking outside the border selected the box")
+	}
+}
@@ -0,0 +1,51 @@
+// Package app - Live preview
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+	"github.com/makeatui/makeatui/internal/ui/preview"
+)
+
+// startPreview runs the design in place of the canvas until esc
+func (m *Model) startPreview() {
+	m.canvas.StopDrawing()
+	m.drag = nil
+	m.preview = preview.New(m.session.Canvas.Components,
+		m.canvas.ViewWidth+canvas.RulerWidth, m.canvas.ViewHeight+1, m.theme)
+}
+
+// updatePreview passes keys to the running design; esc goes back to
+// editing
+func (m Model) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.preview = nil
+		return m, nil
+	}
+	m.preview.Update(msg)
+	return m, nil
+}
+
+// previewMouse focuses what is clicked in the preview
+func (m Model) previewMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+	sidebarWidth, _, _, _ := m.layout()
+	m.preview.Click(msg.X-sidebarWidth-4, msg.Y-2)
+	return m, nil
+}
+
+// renderPreview renders the running design with a line of key hints,
+// or what the last key did
+func (m Model) renderPreview() string {
+	p := m.preview
+	line := lipgloss.NewStyle().Foreground(m.theme.TextMuted).Italic(true)
+	text := " PREVIEW | " + p.Hint() + " "
+	if p.Message != "" {
+		line = line.Foreground(m.theme.Success)
+		text = " PREVIEW | " + p.Message + " "
+	}
+	return p.View() + "\n" + line.MaxWidth(p.Width).Render(text)
+}
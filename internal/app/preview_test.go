@@ -0,0 +1,41 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestPreviewKeysAndEsc(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeInput, Name: "name", X: 2, Y: 2, Width: 20, Height: 3})
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeButton, Name: "ok", X: 2, Y: 6, Width: 8, Height: 3})
+	design := m.session.Canvas.Clone()
+	focus, steps := m.focus, len(m.session.UndoStack)
+
+	m = press(m, "p")
+	if m.preview == nil {
+		t.Fatal("p did not start the preview")
+	}
+	// Tab and typing go to the running design, not the designer
+	m = press(m, "tab", "tab", "x", "u", "D")
+	if got := m.preview.Focused(); got == nil || got.Name != "name" {
+		t.Errorf("tab twice focused %v, want the name input again", got)
+	}
+	if got := m.preview.Value(m.preview.Focused().ID); got != "xuD" {
+		t.Errorf("typed %q, want xuD", got)
+	}
+	if m.focus != focus {
+		t.Errorf("tab moved the designer's focus to %v", m.focus)
+	}
+
+	m = press(m, "esc")
+	if m.preview != nil {
+		t.Fatal("esc did not leave the preview")
+	}
+	if !reflect.DeepEqual(m.session.Canvas, design) || len(m.session.UndoStack) != steps {
+		t.Error("the preview changed the design")
+	}
+}
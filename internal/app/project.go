@@ -0,0 +1,335 @@
+// Package app - Project files, autosave and crash recovery
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/config"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// autosaveInterval is how often unsaved changes are written to the
+// recovery file
+const autosaveInterval = 10 * time.Second
+
+// project tracks the file the design lives in
+type project struct {
+	path  string        // empty until the design is first saved
+	saved schema.Canvas // the design as last opened or saved
+
+	// recovered is an autosave left behind by a session that did not
+	// exit cleanly, offered for restore at startup
+	recovered *recovery
+}
+
+// recovery is the content of a recovery file
+type recovery struct {
+	Path    string        `json:"path,omitempty"`
+	SavedAt time.Time     `json:"saved_at"`
+	Canvas  schema.Canvas `json:"canvas"`
+
+	file string // the recovery file it was read from
+}
+
+// autosaveMsg triggers a periodic autosave
+type autosaveMsg struct{}
+
+func autosave() tea.Cmd {
+	return tea.Tick(autosaveInterval, func(time.Time) tea.Msg { return autosaveMsg{} })
+}
+
+// recoveryPath returns the file unsaved changes to the design saved at
+// path are autosaved to. Each project file has its own, and an untitled
+// design one for the process editing it, so two designers running at
+// once keep their autosaves apart.
+func recoveryPath(path string) string {
+	name := fmt.Sprintf("untitled-%d.json", os.Getpid())
+	if path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		sum := sha256.Sum256([]byte(path))
+		name = hex.EncodeToString(sum[:8]) + ".json"
+	}
+	return config.Path("recovery", name)
+}
+
+// Open creates a model editing the design saved at path. A file that
+// does not exist yet starts an empty design that will be saved there.
+func Open(path string) (Model, error) {
+	m := New()
+	if err := m.load(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return m, err
+		}
+		m.project.path = path
+		m.projectName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		m.project.saved = m.GetCanvasSchema().Clone()
+	}
+	m.project.recovered = readRecovery(path)
+	return m, nil
+}
+
+// readRecovery returns the autosave an earlier session left of the
+// design saved at path, or with no path the latest autosave of any
+// design, if there is one
+func readRecovery(path string) *recovery {
+	files := []string{recoveryPath(path)}
+	if path == "" {
+		files, _ = filepath.Glob(config.Path("recovery", "*.json"))
+	}
+	var latest *recovery
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		r := &recovery{file: file}
+		if err := json.Unmarshal(data, r); err != nil {
+			continue
+		}
+		if latest == nil || r.SavedAt.After(latest.SavedAt) {
+			latest = r
+		}
+	}
+	return latest
+}
+
+// designFile is the design as it is written to a file. An unsized
+// design fills the window, so the size it has from the window is left
+// out for it to fill the window again when reopened.
+func (m *Model) designFile() schema.Canvas {
+	c := m.GetCanvasSchema()
+	if !m.sized {
+		c.Width, c.Height = 0, 0
+	}
+	return c
+}
+
+// writeRecovery autosaves the design if it has unsaved changes
+func (m *Model) writeRecovery() error {
+	if !m.dirty() {
+		return nil
+	}
+	data, err := json.MarshalIndent(recovery{
+		Path:    m.project.path,
+		SavedAt: time.Now(),
+		Canvas:  m.designFile(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := config.EnsureDir("recovery"); err != nil {
+		return err
+	}
+	return os.WriteFile(recoveryPath(m.project.path), data, 0644)
+}
+
+// removeRecovery deletes the recovery file of the design
+func (m *Model) removeRecovery() {
+	_ = os.Remove(recoveryPath(m.project.path))
+}
+
+// dirty reports whether the design has changed since it was opened or
+// last saved
+func (m Model) dirty() bool {
+	current, saved := m.GetCanvasSchema(), m.project.saved
+	if current.Theme != saved.Theme {
+		return true
+	}
+	if m.sized && (current.Width != saved.Width || current.Height != saved.Height) {
+		return true
+	}
+	if len(current.Components) == 0 && len(saved.Components) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(current.Components, saved.Components)
+}
+
+// setCanvas replaces the design with a fresh session holding c
+func (m *Model) setCanvas(c schema.Canvas) {
+	m.session = agent.NewSession(c.Name, m.canvas.Width, m.canvas.Height)
+	m.session.Canvas = c.Clone()
+	m.sized = c.Width > 0 && c.Height > 0
+	if m.session.Canvas.Components == nil {
+		m.session.Canvas.Components = []schema.Component{}
+	}
+	m.canvas.Select(-1)
+	m.clipboard, m.nudging = nil, ""
+	m.syncCanvas()
+
+	if c.Name != "" {
+		m.projectName = c.Name
+	}
+	if c.Theme != "" {
+		m.applyTheme(styles.Lookup(c.Theme))
+	}
+}
+
+// load opens the design saved at path
+func (m *Model) load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var c schema.Canvas
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	if c.Name == "" {
+		c.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	// The design it replaces no longer needs its autosave
+	m.removeRecovery()
+	m.setCanvas(c)
+	m.project.path = path
+	m.project.saved = m.GetCanvasSchema().Clone()
+	return nil
+}
+
+// save writes the design to path and makes it the project file
+func (m *Model) save(path string) error {
+	if path == "" {
+		return errors.New("no file name")
+	}
+	c := m.designFile()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	// The autosave of the design as untitled, or as saved elsewhere, is
+	// out of date now too
+	m.removeRecovery()
+	m.project.path = path
+	m.project.saved = m.GetCanvasSchema().Clone()
+	m.removeRecovery()
+	return nil
+}
+
+// restoreRecovery loads the recovered design. It keeps its unsaved
+// changes, so it is still dirty against its project file.
+func (m *Model) restoreRecovery() {
+	r := m.project.recovered
+	m.project.recovered = nil
+	if r == nil {
+		return
+	}
+
+	// Compare against the project file as it is on disk, if it still is
+	m.project.saved = schema.Canvas{}
+	if r.Path != "" {
+		_ = m.load(r.Path)
+	}
+	m.project.path = r.Path
+	m.setCanvas(r.Canvas)
+	m.message = "Restored unsaved changes"
+
+	// Autosaves of the restored design go to this session's recovery
+	// file from now on
+	if r.file != recoveryPath(r.Path) {
+		_ = os.Remove(r.file)
+	}
+}
+
+// updateRecoveryPrompt handles keys while the restore prompt is shown
+func (m Model) updateRecoveryPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		m.restoreRecovery()
+	case "n", "esc":
+		_ = os.Remove(m.project.recovered.file)
+		m.project.recovered = nil
+	}
+	return m, nil
+}
+
+// saveProject saves to the project file, asking for a name the first time
+func (m *Model) saveProject() {
+	if m.project.path == "" {
+		m.openFileDialog(dialogSaveAs)
+		return
+	}
+	if err := m.save(m.project.path); err != nil {
+		m.message = "Save failed: " + err.Error()
+		return
+	}
+	m.message = "Saved " + filepath.Base(m.project.path)
+}
+
+// quit exits the designer. q asks before discarding unsaved changes;
+// ctrl+c quits at once, leaving them in the recovery file for the next
+// launch to offer back.
+func (m Model) quit(force bool) (tea.Model, tea.Cmd) {
+	if force {
+		if err := m.writeRecovery(); err != nil || !m.dirty() {
+			m.removeRecovery()
+		}
+	} else {
+		if !m.confirmDiscard(shortKey(keys.Quit)) {
+			return m, nil
+		}
+		m.removeRecovery()
+	}
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// renderRecoveryPrompt draws the offer to restore an autosave over view
+func (m Model) renderRecoveryPrompt(view string) string {
+	r := m.project.recovered
+	name := "an untitled design"
+	if r.Path != "" {
+		name = filepath.Base(r.Path)
+	}
+
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Warning).
+		Bold(true).
+		Render("⚠ Recover unsaved changes?")
+	body := fmt.Sprintf("MakeaTUI did not exit cleanly. Unsaved changes\nto %s were autosaved %s.",
+		name, r.SavedAt.Format("Jan 2 15:04"))
+	hint := lipgloss.NewStyle().
+		Foreground(m.theme.TextMuted).
+		Render("y restore · n discard")
+
+	box := lipgloss.NewStyle().
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Warning).
+		Padding(1, 2).
+		Render(title + "\n\n" + body + "\n\n" + hint)
+
+	w, h := lipgloss.Size(box)
+	return compositor.Overlay(view, box, max((m.width-w)/2, 0), max((m.height-h)/2, 0))
+}
+
+// confirmDiscard reports whether unsaved changes may be thrown away:
+// when there are none, or when the same key is pressed twice in a row
+func (m *Model) confirmDiscard(k string) bool {
+	if !m.dirty() || m.confirm == k {
+		m.confirm = ""
+		return true
+	}
+	m.confirm = k
+	m.message = "Unsaved changes: press " + k + " again to discard them"
+	return false
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestSaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login.json")
+	m := press(newTestModel(t), "enter") // add a box
+	if !m.dirty() {
+		t.Fatal("a new box did not make the design dirty")
+	}
+	if err := m.save(path); err != nil {
+		t.Fatal(err)
+	}
+	if m.dirty() {
+		t.Error("dirty after saving")
+	}
+
+	// ctrl+s saves further edits to the same file
+	m = press(m, "D", "ctrl+s")
+	if m.message != "Saved login.json" || m.dirty() {
+		t.Errorf("ctrl+s: message %q, dirty %v", m.message, m.dirty())
+	}
+
+	opened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(opened.session.Canvas.Components, m.session.Canvas.Components) {
+		t.Errorf("opened %+v, want %+v", opened.session.Canvas.Components, m.session.Canvas.Components)
+	}
+	if opened.projectName != m.projectName || opened.project.path != path || opened.dirty() {
+		t.Errorf("opened %q, dirty %v", opened.projectName, opened.dirty())
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	m := press(newTestModel(t), "enter")
+	m = send(m, autosaveMsg{})
+	if _, err := os.Stat(recoveryPath("")); err != nil {
+		t.Fatalf("autosave wrote no recovery file: %v", err)
+	}
+
+	// The next launch offers the autosave back
+	restored := send(New(), tea.WindowSizeMsg{Width: 120, Height: 40})
+	if restored.project.recovered == nil {
+		t.Fatal("no recovery offered")
+	}
+	restored = press(restored, "y")
+	if !reflect.DeepEqual(restored.session.Canvas.Components, m.session.Canvas.Components) || !restored.dirty() {
+		t.Errorf("restored %d components, dirty %v; want the autosaved design, unsaved", len(restored.canvas.Components), restored.dirty())
+	}
+
+	// Declining it deletes the recovery file
+	declined := press(send(New(), tea.WindowSizeMsg{Width: 120, Height: 40}), "n")
+	if declined.project.recovered != nil || len(declined.canvas.Components) != 0 {
+		t.Error("declining still restored the design")
+	}
+	if _, err := os.Stat(recoveryPath("")); !os.IsNotExist(err) {
+		t.Errorf("recovery file left after declining: %v", err)
+	}
+
+	// ctrl+c leaves unsaved changes for the next launch
+	_, _ = press(newTestModel(t), "enter").quit(true)
+	if readRecovery("") == nil {
+		t.Error("ctrl+c with unsaved changes left no recovery file")
+	}
+}
+
+func TestRecoveryPerDesign(t *testing.T) {
+	dir := t.TempDir()
+	login, signup := filepath.Join(dir, "login.json"), filepath.Join(dir, "signup.json")
+	// Designers sharing one config directory, as on one machine
+	untitled := press(newTestModel(t), "enter")
+	designer := func() Model { return send(New(), tea.WindowSizeMsg{Width: 120, Height: 40}) }
+	first := press(designer(), "enter")
+	if err := first.save(login); err != nil {
+		t.Fatal(err)
+	}
+	if err := untitled.writeRecovery(); err != nil {
+		t.Fatal(err)
+	}
+	if err := untitled.save(signup); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(recoveryPath("")); !os.IsNotExist(err) {
+		t.Errorf("saving an untitled design left its autosave: %v", err)
+	}
+
+	// Two designs open at once autosave to files of their own
+	a, err := Open(login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a = press(send(a, tea.WindowSizeMsg{Width: 120, Height: 40}), "enter")
+	b := press(designer(), "enter", "enter")
+	if err := a.writeRecovery(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.writeRecovery(); err != nil {
+		t.Fatal(err)
+	}
+	if recoveryPath(login) == recoveryPath("") || recoveryPath(login) == recoveryPath(signup) {
+		t.Fatal("designs share a recovery file")
+	}
+
+	// Opening a design offers its own autosave, not another's
+	opened, err := Open(login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := opened.project.recovered; r == nil || r.Path != login || len(r.Canvas.Components) != 2 {
+		t.Errorf("opening login.json offered %+v", r)
+	}
+	if opened, _ := Open(signup); opened.project.recovered != nil {
+		t.Errorf("opening signup.json offered %+v", opened.project.recovered)
+	}
+
+	// The designs fill the window, so no size is saved for them
+	if r := readRecovery(""); r == nil || r.Canvas.Width != 0 || r.Canvas.Height != 0 {
+		t.Errorf("autosaved an unsized design as %+v", r)
+	}
+	data, err := os.ReadFile(login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved schema.Canvas
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Width != 0 || saved.Height != 0 {
+		t.Errorf("saved an unsized design as %dx%d (%v)", saved.Width, saved.Height, err)
+	}
+	if reopened, _ := Open(login); reopened.sized {
+		t.Error("an unsized design came back sized")
+	}
+}
@@ -0,0 +1,571 @@
+// Package app - Properties inspector
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+)
+
+const propertiesPanelWidth = 36
+
+// propKind selects the widget used to edit a property
+type propKind int
+
+const (
+	propText   propKind = iota // input.TextInput
+	propNumber                 // input.Spinner
+	propChoice                 // input.Select
+	propColor                  // input.ColorPicker
+)
+
+// property is one editable field of a component. Values pass through
+// get and set as strings; set validates before changing anything.
+type property struct {
+	label   string
+	kind    propKind
+	min     int
+	max     int
+	choices []string
+	show    func(c *schema.Component) bool
+	get     func(c *schema.Component) string
+	set     func(c *schema.Component, value string) error
+
+	// style properties are set on every selected component at once
+	style bool
+}
+
+var borderStyles = []string{"none", "normal", "rounded", "thick", "double", "hidden"}
+
+func isType(types ...schema.ComponentType) func(c *schema.Component) bool {
+	return func(c *schema.Component) bool {
+		for _, t := range types {
+			if c.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func hasBorder(c *schema.Component) bool { return c.Style.Border != nil }
+
+// numberProp edits an int field within [min, max]
+func numberProp(label string, min, max int, field func(c *schema.Component) *int) property {
+	return property{
+		label: label,
+		kind:  propNumber,
+		min:   min,
+		max:   max,
+		get:   func(c *schema.Component) string { return strconv.Itoa(*field(c)) },
+		set: func(c *schema.Component, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%s must be a number", label)
+			}
+			if n < min || n > max {
+				return fmt.Errorf("%s must be between %d and %d", label, min, max)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+// paddingProp edits a pair of opposite padding sides together
+func paddingProp(label string, sides func(c *schema.Component) (*int, *int)) property {
+	p := numberProp(label, 0, 20, func(c *schema.Component) *int {
+		a, _ := sides(c)
+		return a
+	})
+	set := p.set
+	p.set = func(c *schema.Component, v string) error {
+		if err := set(c, v); err != nil {
+			return err
+		}
+		a, b := sides(c)
+		*b = *a
+		return nil
+	}
+	p.style = true
+	return p
+}
+
+// colorProp edits a style color; "" means the theme default
+func colorProp(label string, field func(c *schema.Component) *string) property {
+	return property{
+		label: label,
+		kind:  propColor,
+		get:   func(c *schema.Component) string { return *field(c) },
+		set: func(c *schema.Component, v string) error {
+			*field(c) = v
+			return nil
+		},
+		style: true,
+	}
+}
+
+// componentProperties lists the properties of a component on a canvas
+// of the given size
+func componentProperties(width, height int) []property {
+	return []property{
+		{
+			label: "Name",
+			kind:  propText,
+			get:   func(c *schema.Component) string { return c.Name },
+			set: func(c *schema.Component, v string) error {
+				if strings.TrimSpace(v) == "" {
+					return errors.New("name is required")
+				}
+				c.Name = v
+				return nil
+			},
+		},
+		{
+			label: "Text",
+			kind:  propText,
+			show: func(c *schema.Component) bool {
+				return !isType(schema.TypeList, schema.TypeProgress, schema.TypeTabs)(c)
+			},
+			get: func(c *schema.Component) string { return c.Text },
+			set: func(c *schema.Component, v string) error {
+				c.Text = v
+				return nil
+			},
+		},
+		{
+			label: "Placeholder",
+			kind:  propText,
+			show:  isType(schema.TypeInput),
+			get:   func(c *schema.Component) string { return c.Placeholder },
+			set: func(c *schema.Component, v string) error {
+				c.Placeholder = v
+				return nil
+			},
+		},
+		{
+			label: "Items",
+			kind:  propText,
+			show:  isType(schema.TypeList, schema.TypeTabs),
+			get:   func(c *schema.Component) string { return strings.Join(c.Items, ", ") },
+			set: func(c *schema.Component, v string) error {
+				var items []string
+				for _, item := range strings.Split(v, ",") {
+					if item = strings.TrimSpace(item); item != "" {
+						items = append(items, item)
+					}
+				}
+				if len(items) == 0 {
+					return errors.New("enter at least one item, separated by commas")
+				}
+				c.Items = items
+				return nil
+			},
+		},
+		{
+			label: "Value %",
+			kind:  propNumber,
+			min:   0,
+			max:   100,
+			show:  isType(schema.TypeProgress),
+			get: func(c *schema.Component) string {
+				v, _ := c.Value.(float64)
+				return strconv.Itoa(int(v*100 + 0.5))
+			},
+			set: func(c *schema.Component, v string) error {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 0 || n > 100 {
+					return errors.New("value must be between 0 and 100")
+				}
+				c.Value = float64(n) / 100
+				return nil
+			},
+		},
+		numberProp("X", 0, max(width-1, 0), func(c *schema.Component) *int { return &c.Position.X }),
+		numberProp("Y", 0, max(height-1, 0), func(c *schema.Component) *int { return &c.Position.Y }),
+		numberProp("Width", 1, 500, func(c *schema.Component) *int { return &c.Size.Width }),
+		numberProp("Height", 1, 500, func(c *schema.Component) *int { return &c.Size.Height }),
+		{
+			label:   "Border",
+			kind:    propChoice,
+			choices: borderStyles,
+			get: func(c *schema.Component) string {
+				if c.Style.Border == nil {
+					return "none"
+				}
+				return c.Style.Border.Style
+			},
+			set: func(c *schema.Component, v string) error {
+				if v == "none" {
+					c.Style.Border = nil
+					return nil
+				}
+				if c.Style.Border == nil {
+					c.Style.Border = &schema.Border{Top: true, Right: true, Bottom: true, Left: true}
+				}
+				c.Style.Border.Style = v
+				return nil
+			},
+			style: true,
+		},
+		func() property {
+			p := colorProp("Border color", func(c *schema.Component) *string { return &c.Style.Border.Color })
+			p.show = hasBorder
+			return p
+		}(),
+		paddingProp("Padding V", func(c *schema.Component) (*int, *int) { return &c.Style.Padding.Top, &c.Style.Padding.Bottom }),
+		paddingProp("Padding H", func(c *schema.Component) (*int, *int) { return &c.Style.Padding.Left, &c.Style.Padding.Right }),
+		{
+			label:   "Align",
+			kind:    propChoice,
+			choices: []string{"left", "center", "right"},
+			get: func(c *schema.Component) string {
+				if c.Style.Align == "" {
+					return "left"
+				}
+				return c.Style.Align
+			},
+			set: func(c *schema.Component, v string) error {
+				c.Style.Align = v
+				return nil
+			},
+			style: true,
+		},
+		colorProp("Foreground", func(c *schema.Component) *string { return &c.Style.Foreground }),
+		colorProp("Background", func(c *schema.Component) *string { return &c.Style.Background }),
+	}
+}
+
+// inspector edits the selected component with the input widgets
+type inspector struct {
+	cursor   int
+	editing  bool
+	original schema.Component // restored by esc
+	typed    string           // digits typed into a number field
+	err      string
+
+	text   *input.TextInput
+	number *input.Spinner
+	choice *input.Select
+	color  *input.ColorPicker
+}
+
+func newInspector() *inspector {
+	return &inspector{}
+}
+
+// visibleProperties returns the properties that apply to c
+func (m Model) visibleProperties(c *schema.Component) []property {
+	var result []property
+	for _, p := range componentProperties(m.canvas.Width, m.canvas.Height) {
+		if p.show == nil || p.show(c) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// colorChoices lists the theme tokens followed by the default palette
+func colorChoices(theme styles.Theme) (values, swatches []string) {
+	values = append(values, "")
+	swatches = append(swatches, string(theme.TextPrimary))
+	for _, tok := range styles.Tokens {
+		values = append(values, styles.TokenPrefix+tok.Name)
+		swatches = append(swatches, string(tok.Color(theme)))
+	}
+	for _, hex := range input.DefaultPalette() {
+		values = append(values, hex)
+		swatches = append(swatches, hex)
+	}
+	return values, swatches
+}
+
+// updateProperties handles keys while the properties panel has focus
+func (m Model) updateProperties(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	comp := m.canvas.GetSelected()
+	if comp == nil {
+		return m, nil
+	}
+	in := m.inspector
+	props := m.visibleProperties(comp)
+	if in.cursor >= len(props) {
+		in.cursor = len(props) - 1
+	}
+	p := props[in.cursor]
+
+	if in.editing {
+		m.updateEditor(comp, p, msg)
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if in.cursor > 0 {
+			in.cursor--
+		}
+	case "down", "j":
+		if in.cursor < len(props)-1 {
+			in.cursor++
+		}
+	case "enter", " ":
+		m.beginEdit(comp, p)
+	}
+	return m, nil
+}
+
+// beginEdit opens the widget for the property under the cursor
+func (m Model) beginEdit(comp *schema.Component, p property) {
+	in := m.inspector
+	value := p.get(comp)
+	in.editing, in.original, in.typed, in.err = true, *comp, "", ""
+	if comp.Style.Border != nil {
+		border := *comp.Style.Border
+		in.original.Style.Border = &border
+	}
+
+	switch p.kind {
+	case propText:
+		in.text = input.NewTextInput("prop-" + p.label).
+			SetWidth(propertiesPanelWidth - 10).
+			SetValidator(func(v string) error {
+				scratch := *comp
+				return p.set(&scratch, v)
+			})
+		in.text.SetValue(value)
+		in.text.CursorEnd()
+		in.text.Focus()
+	case propNumber:
+		n, _ := strconv.Atoi(value)
+		in.number = input.NewSpinner("prop-"+p.label, p.min, p.max, n).
+			SetWidth(propertiesPanelWidth - 14)
+		in.number.Focused = true
+		in.number.Editable = false
+	case propChoice:
+		in.choice = input.NewSelect("prop-" + p.label).SetMaxVisible(len(p.choices))
+		for i, c := range p.choices {
+			in.choice.AddOption(c, c)
+			if c == value {
+				in.choice.SelectIndex(i)
+			}
+		}
+		in.choice.Toggle()
+	case propColor:
+		values, swatches := colorChoices(m.theme)
+		in.color = input.NewColorPicker("prop-" + p.label).
+			SetPalette(swatches).
+			SetCols(8)
+		for i, v := range values {
+			if strings.EqualFold(v, value) {
+				in.color.SelectColor(i)
+				break
+			}
+		}
+		in.color.Open = true
+	}
+}
+
+// updateEditor routes a key to the open widget. Every change is applied
+// to the component immediately; enter keeps it and esc restores the
+// value the field had before editing.
+func (m Model) updateEditor(comp *schema.Component, p property, msg tea.KeyMsg) {
+	in := m.inspector
+	apply := func(value string) bool {
+		if err := p.set(comp, value); err != nil {
+			in.err = err.Error()
+			return false
+		}
+		in.err = ""
+		return true
+	}
+
+	switch msg.String() {
+	case "esc":
+		*comp = in.original
+		in.editing, in.err = false, ""
+		return
+	case "enter":
+		switch p.kind {
+		case propText:
+			if !in.text.IsValid() {
+				return
+			}
+		case propChoice:
+			in.choice.Update(msg)
+			apply(in.choice.GetValue())
+		}
+		in.editing = false
+		value := p.get(comp)
+		changed := m.commitComponent(in.original)
+		if p.style {
+			m.styleSelection(p, value, changed)
+		}
+		return
+	}
+
+	switch p.kind {
+	case propText:
+		// The input shows its own validation errors
+		in.text.Update(msg)
+		if in.text.IsValid() {
+			apply(in.text.Value())
+		}
+	case propNumber:
+		key := msg.String()
+		switch {
+		case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+			in.typed += key
+		case key == "backspace" && in.typed != "":
+			in.typed = in.typed[:len(in.typed)-1]
+		default:
+			in.typed = ""
+			in.number.Update(msg)
+		}
+		if in.typed != "" {
+			n, _ := strconv.Atoi(in.typed)
+			in.number.Value = min(max(n, in.number.Min), in.number.Max)
+		}
+		apply(strconv.Itoa(in.number.Value))
+	case propChoice:
+		in.choice.Update(msg)
+	case propColor:
+		values, _ := colorChoices(m.theme)
+		if key := msg.String(); key == "x" || key == "backspace" {
+			in.color.SelectColor(0)
+		} else {
+			in.color.Update(msg)
+			in.color.Open = true
+		}
+		apply(values[in.color.Selected])
+	}
+}
+
+// styleSelection sets a style property to value on the other selected
+// components too. With amend set the changes join the undo step of the
+// edit to the primary selection.
+func (m *Model) styleSelection(p property, value string, amend bool) {
+	var edits []edit
+	for _, i := range m.canvas.SelectedIndices() {
+		if i == m.canvas.Selected {
+			continue
+		}
+		c := m.canvas.Components[i].Clone()
+		if (p.show != nil && !p.show(&c)) || p.get(&c) == value || p.set(&c, value) != nil {
+			continue
+		}
+		edits = append(edits, edit{agent.CmdUpdateComponent, agent.UpdateComponentParams{Component: c}})
+	}
+	if len(edits) > 0 {
+		_ = m.execEdits(amend, edits...)
+	}
+}
+
+// renderProperties renders the inspector for the selected component
+func (m Model) renderProperties(width, height int) string {
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Primary).
+		Bold(true).
+		Render("⚙ Properties")
+
+	muted := lipgloss.NewStyle().Foreground(m.theme.TextMuted)
+	comp := m.canvas.GetSelected()
+
+	var lines []string
+	if comp == nil {
+		lines = append(lines, muted.Render("Select a component on the\ncanvas to edit it"))
+	} else {
+		in := m.inspector
+		props := m.visibleProperties(comp)
+		lines = append(lines, muted.Render(string(comp.Type)+" · "+comp.ID))
+		if n := len(m.canvas.SelectedIndices()); n > 1 {
+			lines = append(lines, muted.Render(fmt.Sprintf("Style changes apply to all %d", n)))
+		}
+		lines = append(lines, "")
+
+		labelWidth := 13
+		for i, p := range props {
+			value := p.get(comp)
+			if p.kind == propColor {
+				value = colorLabel(value, m.theme)
+			}
+			label := lipgloss.NewStyle().Width(labelWidth).Foreground(m.theme.TextSecondary).Render(p.label)
+			val := lipgloss.NewStyle().Foreground(m.theme.TextPrimary).
+				MaxWidth(width - labelWidth - 6).Render(value)
+
+			row := label + val
+			if i == in.cursor && m.focus == FocusProperties {
+				row = lipgloss.NewStyle().Foreground(m.theme.Accent).Render("▸ ") + row
+			} else {
+				row = "  " + row
+			}
+			lines = append(lines, row)
+
+			if i == in.cursor && in.editing {
+				lines = append(lines, m.renderEditor(p))
+			}
+		}
+
+		if in.err != "" {
+			lines = append(lines, "", lipgloss.NewStyle().Foreground(m.theme.Error).Width(width-4).Render("⚠ "+in.err))
+		}
+
+		hint := "↑↓ field · enter edit"
+		if in.editing {
+			hint = "enter keep · esc revert"
+		}
+		lines = append(lines, "", muted.Render(hint))
+	}
+
+	borderColor := m.theme.Border
+	if m.focus == FocusProperties {
+		borderColor = m.theme.Primary
+	}
+
+	content := title + "\n\n" + strings.Join(lines, "\n")
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		MaxHeight(height+2).
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Render(content)
+}
+
+// renderEditor renders the widget editing p
+func (m Model) renderEditor(p property) string {
+	in := m.inspector
+	var view string
+	switch p.kind {
+	case propText:
+		view = in.text.View()
+	case propNumber:
+		view = in.number.View()
+	case propChoice:
+		view = in.choice.View()
+	case propColor:
+		view = in.color.View() + "\n" +
+			lipgloss.NewStyle().Foreground(m.theme.TextMuted).Render("x: theme default")
+	}
+	return lipgloss.NewStyle().PaddingLeft(2).Render(view)
+}
+
+// colorLabel shows a style color with a swatch
+func colorLabel(value string, theme styles.Theme) string {
+	if value == "" {
+		return "default"
+	}
+	color := theme.Resolve(value)
+	if color == "" {
+		return value + " (unknown)"
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("██") + " " + value
+}
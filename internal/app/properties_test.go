@@ -0,0 +1,65 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+// focusProperty puts the inspector's cursor on the labelled property of
+// the selected component
+func focusProperty(t *testing.T, m Model, label string) Model {
+	t.Helper()
+	for i, p := range m.visibleProperties(m.canvas.GetSelected()) {
+		if p.label == label {
+			for range i {
+				m = press(m, "down")
+			}
+			return m
+		}
+	}
+	t.Fatalf("no %s property", label)
+	return m
+}
+
+func TestPropertyEditIsOneUndoStep(t *testing.T) {
+	m := press(newTestModel(t), "enter", "tab") // add a box, focus the inspector
+	if m.focus != FocusProperties || m.canvas.GetSelected() == nil {
+		t.Fatalf("focus %v with %d components, want the inspector on a new box", m.focus, len(m.canvas.Components))
+	}
+	width := m.canvas.GetSelected().Size.Width
+	steps := len(m.session.UndoStack)
+
+	m = focusProperty(t, m, "Width")
+	m = press(m, "enter", "3", "4")
+	if got := m.canvas.GetSelected().Size.Width; got != 34 {
+		t.Fatalf("width %d while typing, want 34 applied live", got)
+	}
+	m = press(m, "enter")
+	if got := len(m.session.UndoStack) - steps; got != 1 {
+		t.Errorf("editing the width took %d undo steps, want 1", got)
+	}
+
+	m = press(m, "u")
+	if got := m.canvas.GetSelected().Size.Width; got != width {
+		t.Errorf("width %d after undo, want %d", got, width)
+	}
+}
+
+func TestPropertyEditCancel(t *testing.T) {
+	m := press(newTestModel(t), "enter", "tab")
+	name := m.canvas.GetSelected().Name
+	steps := len(m.session.UndoStack)
+
+	m = press(m, "enter", "x", "y", "esc") // Name is the first property
+	if got := m.canvas.GetSelected().Name; got != name {
+		t.Errorf("name %q after esc, want %q", got, name)
+	}
+	if len(m.session.UndoStack) != steps {
+		t.Errorf("a cancelled edit added an undo step")
+	}
+
+	m = press(m, "enter", "x", "enter")
+	if got := m.canvas.GetSelected().Name; !strings.HasSuffix(got, "x") {
+		t.Errorf("name %q after enter, want it to end in x", got)
+	}
+}
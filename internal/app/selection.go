@@ -0,0 +1,125 @@
+// Package app - Selection sets
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// startBoxSelect anchors a selection rectangle at the cursor
+func (m *Model) startBoxSelect() {
+	m.canvas.StartDrawing()
+	m.nudging = ""
+}
+
+// finishBoxSelect selects the components lying entirely inside the
+// rectangle from the anchor to the cursor. With add set they join the
+// current selection instead of replacing it.
+func (m *Model) finishBoxSelect(add bool) {
+	pos, size, ok := m.canvas.DrawRect()
+	m.canvas.StopDrawing()
+	if !ok {
+		return
+	}
+	if !add {
+		m.canvas.Select(-1)
+	}
+	for i, comp := range m.canvas.Components {
+		inside := comp.Position.X >= pos.X && comp.Position.Y >= pos.Y &&
+			comp.Position.X+comp.Size.Width <= pos.X+size.Width &&
+			comp.Position.Y+comp.Size.Height <= pos.Y+size.Height
+		if inside && m.canvas.Selectable(i) && !m.canvas.IsSelected(i) {
+			m.canvas.ToggleSelect(i)
+		}
+	}
+}
+
+// updateBoxSelect handles keys while a selection rectangle is open:
+// space or enter selects what it covers and esc drops it. It reports
+// whether it took the key.
+func (m *Model) updateBoxSelect(key string) bool {
+	switch key {
+	case "enter", " ":
+		m.finishBoxSelect(false)
+	case "esc":
+		m.canvas.StopDrawing()
+	default:
+		return false
+	}
+	return true
+}
+
+// selectWhere selects the components for which keep returns true
+func (m *Model) selectWhere(keep func(i int, comp schema.Component) bool) {
+	m.nudging = ""
+	m.canvas.Select(-1)
+	for i, comp := range m.canvas.Components {
+		if keep(i, comp) {
+			m.canvas.ToggleSelect(i)
+		}
+	}
+	if len(m.canvas.SelectedIndices()) == 0 {
+		m.message = "Nothing selected"
+	}
+}
+
+// selectAll selects every component that can be picked on the canvas
+func (m *Model) selectAll() {
+	m.selectWhere(func(i int, _ schema.Component) bool { return m.canvas.Selectable(i) })
+}
+
+// selectSameType selects every component of the selected component's
+// type, or of the sidebar's type when nothing is selected
+func (m *Model) selectSameType() {
+	ctype := m.components[m.selected].Type
+	if comp := m.canvas.GetSelected(); comp != nil {
+		ctype = comp.Type
+	}
+	m.selectWhere(func(i int, comp schema.Component) bool { return comp.Type == ctype && m.canvas.Selectable(i) })
+}
+
+// invertSelection selects exactly the components that are not selected
+func (m *Model) invertSelection() {
+	selected := map[int]bool{}
+	for _, i := range m.canvas.SelectedIndices() {
+		selected[i] = true
+	}
+	m.selectWhere(func(i int, _ schema.Component) bool { return !selected[i] && m.canvas.Selectable(i) })
+}
+
+// selectionBounds returns the rectangle around the selected components
+func (m Model) selectionBounds() (schema.Position, schema.Size) {
+	pos, size, _ := m.canvas.SelectionBounds()
+	return pos, size
+}
+
+// selectionSummary describes the selection for the status bar, such as
+// "3 selected: 2 box, 1 text · 40×12"
+func (m Model) selectionSummary() string {
+	comps := m.selection()
+	switch len(comps) {
+	case 0:
+		return ""
+	case 1:
+		c := comps[0]
+		return fmt.Sprintf("%s (%s) · %d×%d", c.Name, c.Type, c.Size.Width, c.Size.Height)
+	}
+
+	counts := map[schema.ComponentType]int{}
+	for _, c := range comps {
+		counts[c.Type]++
+	}
+	var types []string
+	for t := range counts {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for i, t := range types {
+		types[i] = fmt.Sprintf("%d %s", counts[schema.ComponentType(t)], t)
+	}
+	_, size := m.selectionBounds()
+	return fmt.Sprintf("%d selected: %s · %d×%d", len(comps), strings.Join(types, ", "), size.Width, size.Height)
+}
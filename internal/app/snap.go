@@ -0,0 +1,88 @@
+// Package app - Snapping and guides
+package app
+
+import (
+	"fmt"
+
+	"github.com/makeatui/makeatui/internal/ui/canvas"
+)
+
+// gridStep returns how far to move v, in direction d, to reach the next
+// grid line
+func gridStep(v, d, step int) int {
+	switch {
+	case d > 0:
+		return canvas.NextGridLine(v, 1, step) - v
+	case d < 0:
+		return canvas.NextGridLine(v, -1, step) - v
+	}
+	return 0
+}
+
+// showGuides shows where the selection lines up with the other
+// components
+func (m *Model) showGuides() {
+	pos, size, ok := m.canvas.SelectionBounds()
+	if !ok {
+		m.canvas.Guides = nil
+		return
+	}
+	m.canvas.Guides = m.canvas.Snapper(m.canvas.IsSelected).GuidesFor(pos, size)
+}
+
+// toggleSnap turns snapping to the grid and guides on or off
+func (m *Model) toggleSnap() {
+	m.canvas.Snap = !m.canvas.Snap
+	m.message = "Snap off"
+	if m.canvas.Snap {
+		m.message = fmt.Sprintf("Snap to %d×%d grid", m.canvas.Grid.Width, m.canvas.Grid.Height)
+	}
+}
+
+// cycleGrid switches to the next grid size, turning snapping on
+func (m *Model) cycleGrid() {
+	next := 0
+	for i, size := range canvas.GridSizes {
+		if size == m.canvas.Grid {
+			next = (i + 1) % len(canvas.GridSizes)
+		}
+	}
+	m.canvas.Grid = canvas.GridSizes[next]
+	m.canvas.Snap = false
+	m.toggleSnap()
+}
+
+// snapPoint snaps a point drawn with the mouse to the grid. Past the
+// anchor it snaps to the cell before a grid line, so that rectangles
+// drawn on the grid span whole grid cells.
+func (m *Model) snapPoint(x, y int) (int, int) {
+	if !m.canvas.Snap {
+		return x, y
+	}
+	grid, anchor := m.canvas.Grid, m.canvas.Anchor
+	if anchor != nil && x > anchor.X {
+		x = max(canvas.RoundToGrid(x+1, grid.Width)-1, anchor.X)
+	} else {
+		x = canvas.RoundToGrid(x, grid.Width)
+	}
+	if anchor != nil && y > anchor.Y {
+		y = max(canvas.RoundToGrid(y+1, grid.Height)-1, anchor.Y)
+	} else {
+		y = canvas.RoundToGrid(y, grid.Height)
+	}
+	return x, y
+}
+
+// geometryInfo is the status bar readout: the position and size of the
+// selection while it is being moved or resized, or of the rectangle
+// being drawn, and otherwise the cursor
+func (m Model) geometryInfo() string {
+	if pos, size, ok := m.canvas.DrawRect(); ok {
+		return fmt.Sprintf(" x %d  y %d  w %d  h %d ", pos.X, pos.Y, size.Width, size.Height)
+	}
+	editing := m.canvas.Mode == canvas.ModeMove || m.canvas.Mode == canvas.ModeResize
+	if pos, size, ok := m.canvas.SelectionBounds(); ok && editing {
+		return fmt.Sprintf(" x %d  y %d  w %d  h %d ", pos.X, pos.Y, size.Width, size.Height)
+	}
+	return fmt.Sprintf(" Cursor: (%d, %d) ", m.canvas.CursorX, m.canvas.CursorY)
+}
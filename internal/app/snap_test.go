@@ -0,0 +1,63 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestNudgeSnapsToGrid(t *testing.T) {
+	m := newTestModel(t)
+	_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "box", X: 30, Y: 11, Width: 10, Height: 3})
+	m.canvas.Select(0)
+	m = press(m, "tab", "m") // move mode on the canvas
+
+	// From 30,11 on the 4×2 grid, each arrow goes to the next grid line
+	for _, step := range []struct {
+		key  string
+		x, y int
+	}{{"right", 32, 11}, {"right", 36, 11}, {"down", 36, 12}, {"left", 32, 12}, {"up", 32, 10}} {
+		m = press(m, step.key)
+		if got := m.canvas.GetSelected().Position; got.X != step.x || got.Y != step.y {
+			t.Fatalf("%s: at %d,%d, want %d,%d", step.key, got.X, got.Y, step.x, step.y)
+		}
+	}
+
+	// With snap off an arrow moves one cell
+	m = press(m, "#", "right")
+	if got := m.canvas.GetSelected().Position; got.X != 33 {
+		t.Errorf("at x %d with snap off, want 33", got.X)
+	}
+	// + picks the next grid and turns snap back on
+	m = press(m, "+", "right")
+	if got := m.canvas.GetSelected().Position; !m.canvas.Snap || got.X != 40 {
+		t.Errorf("at x %d on the 8×4 grid, want 40", got.X)
+	}
+}
+
+func TestDragSnapsToComponents(t *testing.T) {
+	for _, snap := range []bool{true, false} {
+		m := newTestModel(t)
+		_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "a", X: 32, Y: 10, Width: 10, Height: 4})
+		_ = m.exec(agent.CmdAddComponent, agent.AddComponentParams{Type: schema.TypeBox, Name: "b", X: 8, Y: 20, Width: 10, Height: 4})
+		if !snap {
+			m = press(m, "#")
+		}
+		m.canvas.Select(1)
+		m.registerZones()
+
+		// Drop b a cell right of a's right edge, at 42
+		m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionPress, 9, 21)
+		m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionMotion, 43, 21)
+		m = mouseAt(m, tea.MouseButtonLeft, tea.MouseActionRelease, 43, 21)
+		want := 41 // pulled into line with a's right edge
+		if !snap {
+			want = 42
+		}
+		if got := m.canvas.Components[1].Position; got.X != want || got.Y != 20 {
+			t.Errorf("snap %v: dropped at %d,%d, want %d,20", snap, got.X, got.Y, want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package app - Template browser
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/preview"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/templates"
+)
+
+// templateBrowser lists the built-in templates by category, with the
+// one under the cursor rendered beside them
+type templateBrowser struct {
+	list    []*templates.Template // by category, then name
+	current int
+}
+
+// categoryRank orders categories as templates.Categories does, any
+// others last
+func categoryRank(c templates.TemplateCategory) int {
+	for i, known := range templates.Categories {
+		if c == known {
+			return i
+		}
+	}
+	return len(templates.Categories)
+}
+
+// openTemplates opens the template browser at the template named, or at
+// the first one
+func (m *Model) openTemplates(name string) {
+	list := templates.NewTemplateEngine().List()
+	sort.Slice(list, func(i, j int) bool {
+		if ri, rj := categoryRank(list[i].Category), categoryRank(list[j].Category); ri != rj {
+			return ri < rj
+		}
+		if list[i].Category != list[j].Category {
+			return list[i].Category < list[j].Category
+		}
+		return list[i].Name < list[j].Name
+	})
+	b := &templateBrowser{list: list}
+	for i, t := range list {
+		if t.Name == name {
+			b.current = i
+		}
+	}
+	m.canvas.StopDrawing()
+	m.drag = nil
+	m.templates = b
+}
+
+// updateTemplates picks a template: enter makes it a new design and i
+// inserts it, scaled, into the selection or a region drawn next
+func (m Model) updateTemplates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := m.templates
+	if len(b.list) == 0 {
+		m.templates = nil
+		return m, nil
+	}
+	t := b.list[b.current]
+	switch {
+	case msg.String() == "esc" || key.Matches(msg, keys.Templates):
+		m.templates = nil
+	case msg.String() == "up" || msg.String() == "k":
+		b.current = max(b.current-1, 0)
+	case msg.String() == "down" || msg.String() == "j":
+		b.current = min(b.current+1, len(b.list)-1)
+	case msg.String() == "enter":
+		if m.confirmDiscard("enter") {
+			m.newFromTemplate(t)
+		}
+	case msg.String() == "i":
+		m.templates = nil
+		if len(m.canvas.SelectedIndices()) == 0 {
+			m.startPlacing(t)
+			break
+		}
+		pos, size := m.selectionBounds()
+		if sel := m.selection(); len(sel) == 1 {
+			// Inside the border of a single component, such as a panel
+			dx, dy, w, h := textArea(&sel[0])
+			pos = schema.Position{X: pos.X + dx, Y: pos.Y + dy}
+			size = schema.Size{Width: w, Height: h}
+		}
+		m.insertTemplate(t, pos, size)
+	}
+	return m, nil
+}
+
+// newFromTemplate starts a new design, not yet saved, from a template
+func (m *Model) newFromTemplate(t *templates.Template) {
+	c := t.Canvas.Clone()
+	c.Name = t.Name
+	m.setCanvas(c)
+	m.project.path = ""
+	m.project.saved = m.GetCanvasSchema().Clone()
+	m.templates = nil
+	m.focus = FocusCanvas
+	m.message = "New design from the " + t.Name + " template"
+}
+
+// startPlacing draws the region to insert a template into, as drawing a
+// component does
+func (m *Model) startPlacing(t *templates.Template) {
+	m.startDrawing()
+	m.placing = t
+	m.message = "Place " + t.Name + ": space at two corners, or drag"
+}
+
+// insertTemplate adds the components of a template, scaled to fill the
+// rectangle at pos, as one undo step, and selects them
+func (m *Model) insertTemplate(t *templates.Template, pos schema.Position, size schema.Size) {
+	comps := t.Scaled(pos.X, pos.Y, size.Width, size.Height)
+	if m.exec(agent.CmdPasteComponents, agent.PasteComponentsParams{Components: comps}) == nil {
+		m.selectLast(len(comps))
+		m.message = fmt.Sprintf("Inserted %s at %d,%d, %d×%d", t.Name, pos.X, pos.Y, size.Width, size.Height)
+	}
+}
+
+// renderTemplates draws the template browser over view: the templates
+// by category and the current one rendered from its design, cropped to
+// the space there is
+func (m Model) renderTemplates(view string) string {
+	b := m.templates
+	if len(b.list) == 0 {
+		return view
+	}
+	t := b.list[b.current]
+
+	heading := lipgloss.NewStyle().Foreground(m.theme.Secondary).Bold(true)
+	item := lipgloss.NewStyle().Foreground(m.theme.TextPrimary)
+	current := lipgloss.NewStyle().Foreground(m.theme.TextPrimary).Background(m.theme.Primary).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(m.theme.TextMuted)
+
+	var lines []string
+	width := 0
+	for _, tpl := range b.list {
+		width = max(width, lipgloss.Width(tpl.Name)+4)
+	}
+	for i, tpl := range b.list {
+		if i == 0 || tpl.Category != b.list[i-1].Category {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, heading.Render(strings.ToUpper(string(tpl.Category))))
+		}
+		style, marker := item, "  "
+		if i == b.current {
+			style, marker = current, "▸ "
+		}
+		lines = append(lines, style.Width(width).Render(marker+tpl.Name))
+	}
+	list := lipgloss.NewStyle().Width(width).MarginRight(2).Render(strings.Join(lines, "\n"))
+
+	// The design at full size, cropped to fit
+	pw := max(min(t.Canvas.Width, m.width-width-12), 10)
+	ph := max(min(t.Canvas.Height, m.height-12), 4)
+	shot := compositor.NewBuffer(pw, ph)
+	shot.DrawBuffer(0, 0, preview.Still(&t.Canvas, m.theme))
+	size := fmt.Sprintf("%d×%d", t.Canvas.Width, t.Canvas.Height)
+	if pw < t.Canvas.Width || ph < t.Canvas.Height {
+		size += ", cropped"
+	}
+	framed := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(m.theme.Border).
+		Render(shot.String())
+	title := lipgloss.NewStyle().Foreground(m.theme.Primary).Bold(true).Render(t.Name) +
+		muted.Render(" · "+size)
+	desc := lipgloss.NewStyle().Foreground(m.theme.TextSecondary).Width(pw + 2).Render(t.Description)
+	detail := lipgloss.JoinVertical(lipgloss.Left, title, desc, "", framed)
+
+	insert := "i draw a region to insert into"
+	if len(m.canvas.SelectedIndices()) > 0 {
+		insert = "i insert into the selection"
+	}
+	hint := muted.Render("↑↓ choose · enter new design · " + insert + " · esc close")
+
+	box := lipgloss.NewStyle().
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.JoinHorizontal(lipgloss.Top, list, detail),
+			"",
+			hint))
+
+	w, h := lipgloss.Size(box)
+	return compositor.Overlay(view, box, max((m.width-w)/2, 0), max((m.height-h)/2, 0))
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// collectIDs counts the IDs of comps and their children
+func collectIDs(ids map[string]int, comps []schema.Component) {
+	for _, c := range comps {
+		ids[c.ID]++
+		collectIDs(ids, c.Children)
+	}
+}
+
+func TestInsertTemplateRenewsIDs(t *testing.T) {
+	m := press(newTestModel(t), "tab", "B")
+	if m.templates == nil || len(m.templates.list) == 0 {
+		t.Fatal("B opened no templates")
+	}
+	tpl := m.templates.list[m.templates.current]
+	m = press(m, "esc")
+	steps := len(m.session.UndoStack)
+
+	// Insert it into a region drawn with the keys
+	m = press(m, "B", "i")
+	if m.placing == nil || m.placing.Name != tpl.Name {
+		t.Fatalf("i is not placing %s", tpl.Name)
+	}
+	m = press(m, "enter")
+	for range 30 {
+		m = press(m, "right")
+	}
+	for range 10 {
+		m = press(m, "down")
+	}
+	m = press(m, "enter")
+	if got := len(m.canvas.SelectedIndices()); got != len(tpl.Canvas.Components) {
+		t.Fatalf("%d selected after inserting, want the %d inserted", got, len(tpl.Canvas.Components))
+	}
+
+	// And again over the selection, which is the first copy
+	m = press(m, "B", "i")
+	if got := len(m.session.UndoStack) - steps; got != 2 {
+		t.Errorf("two inserts took %d undo steps, want 2", got)
+	}
+	if got, want := len(m.canvas.Components), 2*len(tpl.Canvas.Components); got != want {
+		t.Fatalf("%d components after two inserts, want %d", got, want)
+	}
+
+	used := map[string]int{}
+	collectIDs(used, tpl.Canvas.Components)
+	ids := map[string]int{}
+	collectIDs(ids, m.canvas.Components)
+	for id, n := range ids {
+		if n > 1 {
+			t.Errorf("ID %s is used %d times", id, n)
+		}
+		if used[id] > 0 {
+			t.Errorf("ID %s is the template's own", id)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package app - Editing a component's text in place on the canvas
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+)
+
+// textEdit is the text of the selected component being edited over it
+// on the canvas. Each change shows on the component at once; before is
+// the component as it was, to go back to or to record the edit from.
+type textEdit struct {
+	before schema.Component
+	line   *input.TextInput // text that fits on one line
+	area   *input.TextArea  // text of several lines, and items one a line
+}
+
+// editsItems reports whether editing c's text changes its items, one a
+// line, rather than its text
+var editsItems = isType(schema.TypeList, schema.TypeTabs, schema.TypeTable)
+
+// multiline reports whether c's text is edited in a text area
+func multiline(c *schema.Component) bool {
+	switch c.Type {
+	case schema.TypeBox, schema.TypeViewport:
+		return true
+	case schema.TypeText:
+		return c.Size.Height > 1 || strings.Contains(c.Text, "\n")
+	}
+	return editsItems(c)
+}
+
+// textArea returns where the text of c goes inside it: the offset from
+// its top left corner and the size, inside its border and padding
+func textArea(c *schema.Component) (x, y, w, h int) {
+	if c.Type == schema.TypeText {
+		return 0, 0, c.Size.Width, c.Size.Height
+	}
+	frameX, frameY := 0, 0
+	switch {
+	case c.Type == schema.TypeButton:
+		// Buttons draw their own rounded border and padding
+		frameX, frameY = 4, 1
+	case c.Style.Border != nil && c.Style.Border.Style != "none":
+		frameX, frameY = 1, 1
+	}
+	p := c.Style.Padding
+	x, y = frameX+p.Left, frameY+p.Top
+	w = c.Size.Width - 2*frameX - p.Left - p.Right
+	h = c.Size.Height - 2*frameY - p.Top - p.Bottom
+	return x, y, max(w, 1), max(h, 1)
+}
+
+// startTextEdit opens an editor for the text of the selected component
+func (m *Model) startTextEdit() {
+	comp := m.canvas.GetSelected()
+	switch {
+	case comp == nil:
+		m.message = "Select a component to edit its text"
+		return
+	case isType(schema.TypeProgress)(comp):
+		m.message = "A progress bar has no text; set its value in the properties"
+		return
+	}
+	m.canvas.Fit = false
+	m.canvas.Select(m.canvas.Selected)
+	m.canvas.CursorX, m.canvas.CursorY = comp.Position.X, comp.Position.Y
+	m.canvas.FollowCursor()
+
+	e := &textEdit{before: comp.Clone()}
+	_, _, w, h := textArea(comp)
+	if multiline(comp) {
+		value := comp.Text
+		if editsItems(comp) {
+			value = strings.Join(comp.Items, "\n")
+		}
+		e.area = input.NewTextArea("text-"+comp.ID).
+			SetShowLineNumbers(false).
+			SetSize(w, h)
+		e.area.Prompt = ""
+		e.area.SetValue(value)
+		e.area.Focus()
+	} else {
+		e.line = input.NewTextInput("text-" + comp.ID).SetWidth(w)
+		e.line.Prompt = ""
+		e.line.SetValue(comp.Text)
+		e.line.CursorEnd()
+		e.line.Focus()
+	}
+	m.textEdit = e
+}
+
+// value is the text being edited
+func (e *textEdit) value() string {
+	if e.area != nil {
+		return e.area.Value()
+	}
+	return e.line.Value()
+}
+
+// updateTextEdit edits the text: enter or ctrl+s keeps it as one undo
+// step and esc puts it back. In a text area alt+enter starts a new
+// line. ctrl+g turns resizing the component to fit its text on or off.
+func (m Model) updateTextEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := m.textEdit
+	comp := m.canvas.GetSelected()
+	switch msg.String() {
+	case "esc":
+		*comp = e.before
+		m.textEdit = nil
+		return m, nil
+	case "enter", "ctrl+s":
+		if editsItems(comp) && len(comp.Items) == 0 {
+			m.message = "Enter at least one item, one a line"
+			return m, nil
+		}
+		m.textEdit = nil
+		m.commitComponent(e.before)
+		return m, nil
+	case "ctrl+g":
+		m.fitText = !m.fitText
+		m.message = "Components keep their size"
+		if m.fitText {
+			m.message = "Components fit their text"
+		}
+	case "alt+enter", "ctrl+j":
+		if e.area != nil {
+			e.area.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+	default:
+		if e.area != nil {
+			e.area.Update(msg)
+		} else {
+			e.line.Update(msg)
+		}
+	}
+	m.applyTextEdit()
+	return m, nil
+}
+
+// applyTextEdit shows the text being edited on the selected component,
+// resized to fit it when fitText is on
+func (m *Model) applyTextEdit() {
+	e := m.textEdit
+	comp := m.canvas.GetSelected()
+	*comp = e.before.Clone()
+
+	lines := strings.Split(e.value(), "\n")
+	if editsItems(comp) {
+		comp.Items = nil
+		for _, item := range lines {
+			if item = strings.TrimSpace(item); item != "" {
+				comp.Items = append(comp.Items, item)
+			}
+		}
+		lines = comp.Items
+	} else {
+		comp.Text = e.value()
+	}
+
+	if m.fitText {
+		_, _, w, h := textArea(&e.before)
+		width := 1
+		for _, line := range lines {
+			width = max(width, lipgloss.Width(line))
+		}
+		comp.Size.Width = e.before.Size.Width - w + width
+		comp.Size.Height = e.before.Size.Height - h + max(len(lines), 1)
+	}
+
+	_, _, w, h := textArea(comp)
+	if e.area != nil {
+		e.area.SetSize(w, h)
+	} else {
+		e.line.Width = w
+	}
+}
+
+// renderTextEdit draws the editor over the text of the selected
+// component on view, with its keys below
+func (m Model) renderTextEdit(view string) string {
+	e := m.textEdit
+	comp := m.canvas.GetSelected()
+	dx, dy, w, h := textArea(comp)
+	ox, oy := m.canvasOrigin()
+	x, y := m.canvas.ToView(comp.Position.X+dx, comp.Position.Y+dy)
+
+	editor := ""
+	if e.area != nil {
+		editor = e.area.Model.View()
+	} else {
+		editor = e.line.Model.View()
+		h = 1
+	}
+	box := lipgloss.NewStyle().
+		Background(m.theme.Surface).
+		Foreground(m.theme.TextPrimary).
+		Width(w).
+		Height(h).
+		Render(editor)
+
+	fit := "off"
+	if m.fitText {
+		fit = "on"
+	}
+	hints := "enter keep · esc cancel · ctrl+g fit " + fit
+	if e.area != nil {
+		hints = "alt+enter new line · " + hints
+	}
+	hint := lipgloss.NewStyle().
+		Foreground(m.theme.TextMuted).
+		Background(m.theme.SurfaceLight).
+		Render(" " + hints + " ")
+
+	view = compositor.Overlay(view, box, ox+x, oy+y)
+	return compositor.Overlay(view, hint, ox+x-dx, oy+y-dy+comp.Size.Height)
+}
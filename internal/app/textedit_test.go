@@ -0,0 +1,91 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// newTextEditModel returns the designer with comp added and selected on
+// the canvas
+func newTextEditModel(t *testing.T, params agent.AddComponentParams) Model {
+	t.Helper()
+	m := newTestModel(t)
+	if err := m.exec(agent.CmdAddComponent, params); err != nil {
+		t.Fatal(err)
+	}
+	m.canvas.Select(0)
+	return press(m, "tab")
+}
+
+func TestTextEditCommit(t *testing.T) {
+	m := newTextEditModel(t, agent.AddComponentParams{Type: schema.TypeText, Name: "title", Text: "Hi", Width: 20, Height: 1})
+	steps := len(m.session.UndoStack)
+
+	m = press(m, "i")
+	if m.textEdit == nil || m.textEdit.line == nil {
+		t.Fatal("i did not open a one-line editor")
+	}
+	m = press(m, " ", "y", "o", "u")
+	if got := m.canvas.GetSelected().Text; got != "Hi you" {
+		t.Fatalf("text %q while typing, want Hi you applied live", got)
+	}
+	m = press(m, "enter")
+	if m.textEdit != nil || m.canvas.GetSelected().Text != "Hi you" {
+		t.Fatalf("enter: text %q", m.canvas.GetSelected().Text)
+	}
+	if got := len(m.session.UndoStack) - steps; got != 1 {
+		t.Errorf("editing the text took %d undo steps, want 1", got)
+	}
+
+	m = press(m, "u")
+	if got := m.canvas.GetSelected().Text; got != "Hi" {
+		t.Errorf("text %q after undo, want Hi", got)
+	}
+}
+
+func TestTextEditCancel(t *testing.T) {
+	m := newTextEditModel(t, agent.AddComponentParams{Type: schema.TypeText, Name: "title", Text: "Hi", Width: 20, Height: 1})
+	steps := len(m.session.UndoStack)
+
+	m = press(m, "i", "backspace", "ctrl+g", "!", "esc")
+	c := m.canvas.GetSelected()
+	if m.textEdit != nil || c.Text != "Hi" || c.Size.Width != 20 {
+		t.Errorf("esc left %q %v, want Hi as it was", c.Text, c.Size)
+	}
+	if len(m.session.UndoStack) != steps {
+		t.Error("a cancelled edit added an undo step")
+	}
+}
+
+func TestTextEditItems(t *testing.T) {
+	m := newTextEditModel(t, agent.AddComponentParams{Type: schema.TypeList, Name: "list", Width: 20, Height: 5})
+	list := m.canvas.GetSelected().Clone()
+	list.Items = []string{"a", "b"}
+	_ = m.exec(agent.CmdUpdateComponent, agent.UpdateComponentParams{Component: list})
+
+	m = press(m, "i")
+	if m.textEdit == nil || m.textEdit.area == nil {
+		t.Fatal("i did not open a text area for the items")
+	}
+	m = press(m, "ctrl+j", "c", "enter")
+	if got := m.canvas.GetSelected().Items; !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("items %q, want [a b c]", got)
+	}
+
+	// A list keeps at least one item
+	m = press(m, "i", "backspace", "backspace", "backspace", "backspace", "backspace", "enter")
+	if m.textEdit == nil {
+		t.Errorf("enter kept a list with items %q", m.canvas.GetSelected().Items)
+	}
+}
+
+func TestTextEditFit(t *testing.T) {
+	m := newTextEditModel(t, agent.AddComponentParams{Type: schema.TypeText, Name: "title", Text: "Hi", Width: 20, Height: 1})
+	m = press(m, "i", "ctrl+g", "!", "enter")
+	if c := m.canvas.GetSelected(); c.Text != "Hi!" || c.Size.Width != 3 {
+		t.Errorf("fitted %q to width %d, want 3", c.Text, c.Size.Width)
+	}
+}
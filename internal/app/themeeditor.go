@@ -0,0 +1,157 @@
+// Package app - Live-preview theme editor
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/contrast"
+	"github.com/makeatui/makeatui/pkg/palette"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+)
+
+const themeEditorWidth = 36
+
+// themeEditor generates a theme from a seed color picked from a
+// palette. The design previews every change until it is saved or
+// cancelled.
+type themeEditor struct {
+	picker   *input.ColorPicker
+	mode     *input.Toggle
+	previous styles.Theme
+	theme    styles.Theme
+	err      string
+}
+
+func newThemeEditor(current styles.Theme) *themeEditor {
+	picker := input.NewColorPicker("theme-seed").
+		SetLabel("Seed color").
+		SetPalette(append(input.UltravioletPalette(), input.DefaultPalette()...)).
+		SetCols(8).
+		SetColor(string(current.Primary))
+	picker.Open = true
+
+	e := &themeEditor{
+		picker:   picker,
+		mode:     input.NewToggle("theme-mode", "Mode").SetLabels("light", "dark"),
+		previous: current,
+	}
+	e.generate()
+	return e
+}
+
+func (e *themeEditor) seed() string {
+	return e.picker.Palette[e.picker.Selected]
+}
+
+// generate rebuilds the preview theme from the current seed and mode
+func (e *themeEditor) generate() {
+	mode := palette.ModeDark
+	if e.mode.On {
+		mode = palette.ModeLight
+	}
+	t, err := palette.Generate(e.seed(), palette.Options{Mode: mode})
+	if err != nil {
+		e.err = err.Error()
+		return
+	}
+	e.theme, e.err = t, ""
+}
+
+// applyTheme switches the designer and the design to a theme
+func (m *Model) applyTheme(t styles.Theme) {
+	m.theme = t
+	m.styles = styles.NewStyles(t)
+	m.canvas.Theme = t
+	m.relint()
+}
+
+// updateThemeEditor handles keys while the theme editor is open:
+// arrows pick the seed, tab flips light/dark, enter saves, esc cancels
+func (m Model) updateThemeEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := m.themeEditor
+	switch msg.String() {
+	case "esc":
+		m.applyTheme(e.previous)
+		m.themeEditor = nil
+		return m, nil
+	case "enter":
+		if _, err := styles.SaveUserTheme(e.theme); err != nil {
+			e.err = err.Error()
+			return m, nil
+		}
+		m.themeEditor = nil
+		return m, nil
+	case "tab":
+		e.mode.Toggle()
+	default:
+		e.picker.Update(msg)
+		e.picker.Open = true
+	}
+	e.generate()
+	m.applyTheme(e.theme)
+	return m, nil
+}
+
+// renderThemeEditor renders the seed picker and a summary of the
+// generated theme
+func (m Model) renderThemeEditor(width, height int) string {
+	e := m.themeEditor
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Primary).
+		Bold(true).
+		Render("🎨 Theme Editor")
+
+	var swatches []string
+	for _, tok := range []string{"primary", "secondary", "accent", "success", "warning", "error", "info"} {
+		t, _ := styles.LookupToken(tok)
+		swatches = append(swatches, lipgloss.NewStyle().Foreground(t.Color(e.theme)).Render("██"))
+	}
+
+	status := lipgloss.NewStyle().Foreground(m.theme.Success).Render("✓ WCAG AA")
+	if fails := countFailures(contrast.CheckTheme(e.theme, contrast.LevelAA)); fails > 0 {
+		status = lipgloss.NewStyle().Foreground(m.theme.Error).Render(fmt.Sprintf("✗ %d pairs fail AA", fails))
+	}
+	if e.err != "" {
+		status = lipgloss.NewStyle().Foreground(m.theme.Error).Width(width - 4).Render(e.err)
+	}
+
+	hint := lipgloss.NewStyle().Foreground(m.theme.TextMuted).Render(
+		"arrows pick · tab light/dark\nenter save · esc cancel")
+
+	content := strings.Join([]string{
+		title,
+		"",
+		e.picker.View(),
+		e.mode.View(),
+		"",
+		lipgloss.NewStyle().Foreground(m.theme.TextPrimary).Bold(true).Render(e.theme.Name),
+		strings.Join(swatches, " "),
+		status,
+		"",
+		hint,
+	}, "\n")
+
+	return lipgloss.NewStyle().
+		Width(width).
+		Height(height).
+		MaxHeight(height+2).
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(0, 1).
+		Render(content)
+}
+
+func countFailures(pairs []contrast.Pair) int {
+	n := 0
+	for _, p := range pairs {
+		if !p.Pass {
+			n++
+		}
+	}
+	return n
+}
@@ -5,19 +5,41 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// renderView builds the complete UI
-func (m Model) renderView() string {
-	// Calculate dimensions
-	sidebarWidth := 28
-	canvasWidth := m.width - sidebarWidth - 4
+// layout returns the widths of the sidebar, the canvas and the
+// right-hand panel (0 when none is shown), and the height of the row
+// they share
+func (m Model) layout() (sidebarWidth, canvasWidth, panelWidth, contentHeight int) {
+	sidebarWidth = 28
+	canvasWidth = m.width - sidebarWidth - 4
 	if canvasWidth < 40 {
 		canvasWidth = 40
 	}
 
-	contentHeight := m.height - 5 // toolbar + statusbar
+	switch {
+	case m.themeEditor != nil:
+		panelWidth = themeEditorWidth
+	case m.focus == FocusProperties:
+		panelWidth = propertiesPanelWidth
+	case m.focus == FocusLayers:
+		panelWidth = layersPanelWidth
+	case m.showLint:
+		panelWidth = lintPanelWidth
+	}
+	if panelWidth > 0 {
+		canvasWidth -= panelWidth + 2
+	}
+
+	contentHeight = m.height - 4 // toolbar, statusbar and borders
+	return sidebarWidth, canvasWidth, panelWidth, contentHeight
+}
+
+// renderView builds the complete UI
+func (m Model) renderView() string {
+	sidebarWidth, canvasWidth, panelWidth, contentHeight := m.layout()
 
 	// Build each section
 	toolbar := m.renderToolbar()
@@ -31,12 +53,39 @@ func (m Model) renderView() string {
 
 	// Layout: Toolbar on top, then sidebar + canvas side by side, statusbar at bottom
 	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, canvasView)
+	switch {
+	case m.themeEditor != nil:
+		mainContent = lipgloss.JoinHorizontal(lipgloss.Top, mainContent, m.renderThemeEditor(panelWidth, contentHeight))
+	case m.focus == FocusProperties:
+		mainContent = lipgloss.JoinHorizontal(lipgloss.Top, mainContent, m.renderProperties(panelWidth, contentHeight))
+	case m.focus == FocusLayers:
+		mainContent = lipgloss.JoinHorizontal(lipgloss.Top, mainContent, m.renderLayers(panelWidth, contentHeight))
+	case m.showLint:
+		mainContent = lipgloss.JoinHorizontal(lipgloss.Top, mainContent, m.renderLintPanel(panelWidth, contentHeight))
+	}
 	fullView := lipgloss.JoinVertical(lipgloss.Left, toolbar, mainContent, statusBar)
 
 	if m.showHelp {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, helpOverlay)
 	}
 
+	switch {
+	case m.project.recovered != nil:
+		return m.renderRecoveryPrompt(fullView)
+	case m.fileDialog != nil:
+		return m.renderFileDialog(fullView)
+	case m.contextMenu != nil:
+		return m.renderContextMenu(fullView)
+	case m.sizePrompt != nil:
+		return m.renderSizePrompt(fullView)
+	case m.textEdit != nil:
+		return m.renderTextEdit(fullView)
+	case m.templates != nil:
+		return m.renderTemplates(fullView)
+	case m.commands.Visible:
+		return m.renderCommandPalette(fullView)
+	}
+
 	return fullView
 }
 
@@ -51,13 +100,14 @@ func (m Model) renderToolbar() string {
 		Foreground(m.theme.TextSecondary).
 		Render(" │ " + m.projectName)
 
-	spacer := lipgloss.NewStyle().
-		Width(m.width - lipgloss.Width(logo) - lipgloss.Width(projectName) - 20).
-		Render("")
-
 	actions := lipgloss.NewStyle().
 		Foreground(m.theme.TextMuted).
-		Render("[?] Help  [e] Export  [q] Quit")
+		Render(toolbarHints())
+
+	// Fill the row between the padding, so the toolbar stays one line
+	spacer := lipgloss.NewStyle().
+		Width(max(m.width-4-lipgloss.Width(logo)-lipgloss.Width(projectName)-lipgloss.Width(actions), 0)).
+		Render("")
 
 	toolbar := lipgloss.NewStyle().
 		Background(m.theme.SurfaceLight).
@@ -68,6 +118,21 @@ func (m Model) renderToolbar() string {
 	return toolbar
 }
 
+// toolbarHints are the toolbar's reminders of the help, export and quit
+// keys, leaving out those unbound
+func toolbarHints() string {
+	var hints []string
+	for _, h := range []struct {
+		binding key.Binding
+		label   string
+	}{{keys.Help, "Help"}, {keys.Export, "Export"}, {keys.Quit, "Quit"}} {
+		if k := shortKey(h.binding); k != "" {
+			hints = append(hints, "["+k+"] "+h.label)
+		}
+	}
+	return strings.Join(hints, "  ")
+}
+
 // renderSidebar renders the component palette
 func (m Model) renderSidebar(width, height int) string {
 	titleStyle := lipgloss.NewStyle().
@@ -128,6 +193,14 @@ func (m Model) renderCanvas(width, height int) string {
 	}
 
 	canvasContent := m.canvas.Render()
+	if m.preview != nil {
+		borderColor = m.theme.Success
+		canvasContent = m.renderPreview()
+	}
+	if m.gallery != nil {
+		borderColor = m.theme.Success
+		canvasContent = m.renderGallery()
+	}
 
 	canvasStyle := lipgloss.NewStyle().
 		Width(width).
@@ -141,13 +214,20 @@ func (m Model) renderCanvas(width, height int) string {
 
 // renderStatusBar renders the bottom status bar
 func (m Model) renderStatusBar() string {
-	focusNames := []string{"SIDEBAR", "CANVAS", "PROPERTIES"}
+	focusNames := []string{"SIDEBAR", "CANVAS", "PROPERTIES", "LAYERS"}
 
 	leftInfo := lipgloss.NewStyle().
 		Foreground(m.theme.TextPrimary).
 		Background(m.theme.Primary).
 		Padding(0, 2).
 		Render(fmt.Sprintf(" %s ", focusNames[m.focus]))
+	if m.preview != nil || m.gallery != nil {
+		leftInfo = lipgloss.NewStyle().
+			Foreground(m.theme.Background).
+			Background(m.theme.Success).
+			Padding(0, 2).
+			Render(" PREVIEW ")
+	}
 
 	modeInfo := lipgloss.NewStyle().
 		Foreground(m.theme.TextSecondary).
@@ -155,17 +235,42 @@ func (m Model) renderStatusBar() string {
 		Padding(0, 2).
 		Render(fmt.Sprintf(" Components: %d ", len(m.canvas.Components)))
 
+	fileInfo := lipgloss.NewStyle().
+		Foreground(m.theme.TextSecondary).
+		Background(m.theme.SurfaceLight).
+		Padding(0, 1).
+		Render(m.fileStatus())
+
 	cursorInfo := lipgloss.NewStyle().
 		Foreground(m.theme.TextMuted).
-		Render(fmt.Sprintf(" Cursor: (%d, %d) ", m.canvas.CursorX, m.canvas.CursorY))
+		Render(m.geometryInfo())
+
+	lintInfo := m.renderLintSummary()
+
+	selectionInfo := ""
+	if summary := m.selectionSummary(); summary != "" {
+		selectionInfo = lipgloss.NewStyle().
+			Foreground(m.theme.Accent).
+			Background(m.theme.SurfaceLight).
+			Padding(0, 1).
+			Render(summary)
+	}
 
-	spacerWidth := m.width - lipgloss.Width(leftInfo) - lipgloss.Width(modeInfo) - lipgloss.Width(cursorInfo)
+	messageInfo := ""
+	if m.message != "" {
+		messageInfo = lipgloss.NewStyle().
+			Foreground(m.theme.TextSecondary).
+			Background(m.theme.SurfaceLight).
+			Render(" " + m.message + " ")
+	}
+
+	spacerWidth := m.width - lipgloss.Width(leftInfo) - lipgloss.Width(modeInfo) - lipgloss.Width(fileInfo) - lipgloss.Width(selectionInfo) - lipgloss.Width(lintInfo) - lipgloss.Width(messageInfo) - lipgloss.Width(cursorInfo)
 	spacer := lipgloss.NewStyle().
 		Background(m.theme.SurfaceLight).
 		Width(spacerWidth).
 		Render("")
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftInfo, modeInfo, spacer, cursorInfo)
+	return lipgloss.JoinHorizontal(lipgloss.Top, leftInfo, modeInfo, fileInfo, selectionInfo, lintInfo, messageInfo, spacer, cursorInfo)
 }
 
 // renderHelp renders the help overlay
@@ -174,24 +279,20 @@ func (m Model) renderHelp() string {
 		Background(m.theme.Surface).
 		BorderStyle(lipgloss.DoubleBorder()).
 		BorderForeground(m.theme.Primary).
-		Padding(2, 4).
-		Width(50)
+		Padding(2, 4)
 
 	title := lipgloss.NewStyle().
 		Foreground(m.theme.Primary).
 		Bold(true).
 		Render("⌨️  Keyboard Shortcuts\n\n")
 
-	shortcuts := `
-↑/k, ↓/j     Navigate up/down
-←/h, →/l     Navigate left/right
-Tab          Switch focus area
-Enter/Space  Add selected component
-d/Delete     Delete selected
-m            Toggle move mode
-e            Export to Go code
-?            Toggle this help
-q/Ctrl+C     Quit
+	shortcuts := "\n" + strings.Join(keys.helpLines(false), "\n") + `
+
+Mouse: click to select, shift-click to add to
+the selection, drag empty canvas to box
+select, drag to move, drag a ■ handle
+to resize, right-click for actions. Click a
+sidebar item, then drag on the canvas to draw
 `
 	return helpStyle.Render(title + shortcuts)
 }
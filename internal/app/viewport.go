@@ -0,0 +1,170 @@
+// Package app - Canvas viewport and design size
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/preview"
+	"github.com/makeatui/makeatui/pkg/agent"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/widgets/input"
+	"github.com/makeatui/makeatui/pkg/widgets/mouse"
+)
+
+// panStep is how far ctrl+arrows pan the view, in columns; rows pan
+// half as far
+const panStep = 8
+
+// panKeys pan the view over a design larger than it
+var panKeys = map[string][2]int{
+	"ctrl+left":  {-panStep, 0},
+	"ctrl+right": {panStep, 0},
+	"ctrl+up":    {0, -panStep / 2},
+	"ctrl+down":  {0, panStep / 2},
+}
+
+// updateViewport handles the keys that pan and scale the view. It
+// reports whether it took the key.
+func (m *Model) updateViewport(msg tea.KeyMsg) bool {
+	if d, ok := panKeys[msg.String()]; ok {
+		m.canvas.ScrollBy(d[0], d[1])
+		return true
+	}
+	switch msg.String() {
+	case "pgup":
+		m.canvas.ScrollBy(0, -m.canvas.ViewHeight)
+	case "pgdown":
+		m.canvas.ScrollBy(0, m.canvas.ViewHeight)
+	default:
+		return false
+	}
+	return true
+}
+
+// toggleFit switches between the design at full size and all of it
+// scaled down to fit the view. Leaving fit mode centers the view on the
+// cursor.
+func (m *Model) toggleFit() {
+	m.canvas.Fit = !m.canvas.Fit
+	if !m.canvas.Fit {
+		m.canvas.CenterOn(m.canvas.CursorX, m.canvas.CursorY)
+	}
+}
+
+// toggleMinimap shows or hides the minimap
+func (m *Model) toggleMinimap() {
+	m.canvas.ShowMinimap = !m.canvas.ShowMinimap
+	m.message = "Minimap off"
+	if m.canvas.ShowMinimap {
+		m.message = "Minimap on"
+	}
+}
+
+// viewportMouse pans the view with the wheel, sideways with shift, and
+// jumps to what is clicked on the minimap or, in fit mode, the
+// overview. It reports whether it took the event.
+func (m *Model) viewportMouse(msg tea.MouseMsg, zone *mouse.Zone) bool {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp && msg.Shift, msg.Button == tea.MouseButtonWheelLeft:
+		m.canvas.ScrollBy(-4, 0)
+		return true
+	case msg.Button == tea.MouseButtonWheelDown && msg.Shift, msg.Button == tea.MouseButtonWheelRight:
+		m.canvas.ScrollBy(4, 0)
+		return true
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.canvas.ScrollBy(0, -2)
+		return true
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.canvas.ScrollBy(0, 2)
+		return true
+	}
+
+	ox, oy := m.canvasOrigin()
+	isMinimap := zone != nil && zone.ID == zoneMinimap
+	if !isMinimap && !m.canvas.Fit {
+		return false
+	}
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || zone == nil || zone.ID != zoneCanvas && !isMinimap {
+		return true
+	}
+
+	x, y := m.canvas.FromView(msg.X-ox, msg.Y-oy)
+	if isMinimap {
+		mx, my, _, _, s, _ := m.canvas.Minimap()
+		x, y = int(float64(msg.X-ox-mx)*s), int(float64(msg.Y-oy-my)*s)
+	}
+	m.focus = FocusCanvas
+	m.canvas.Fit = false
+	m.canvas.CursorX = min(max(x, 0), m.canvas.Width-1)
+	m.canvas.CursorY = min(max(y, 0), m.canvas.Height-1)
+	m.canvas.CenterOn(m.canvas.CursorX, m.canvas.CursorY)
+	return true
+}
+
+// openSizePrompt asks for the size of the design
+func (m *Model) openSizePrompt() {
+	m.sizePrompt = input.NewTextInput("canvas-size").
+		SetPlaceholder("follow the window").
+		SetWidth(24)
+	if m.sized {
+		m.sizePrompt.SetValue(fmt.Sprintf("%dx%d", m.canvas.Width, m.canvas.Height))
+	}
+	m.sizePrompt.CursorEnd()
+	m.sizePrompt.Focus()
+}
+
+// updateSizePrompt edits the size being typed: enter sets it, as one
+// undo step, and esc closes the prompt. An empty size makes the design
+// follow the window again.
+func (m Model) updateSizePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.sizePrompt = nil
+	case "enter":
+		value := strings.TrimSpace(m.sizePrompt.Value())
+		if value == "" {
+			m.sizePrompt, m.sized = nil, false
+			m.message = "The design follows the window"
+			return m, nil
+		}
+		sizes, err := preview.ParseSizes(value)
+		if err == nil && len(sizes) != 1 {
+			err = fmt.Errorf("want a single size such as 200x60")
+		}
+		if err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		if m.exec(agent.CmdResizeCanvas, agent.ResizeCanvasParams{Width: sizes[0].Width, Height: sizes[0].Height}) == nil {
+			m.sizePrompt, m.sized = nil, true
+			m.message = fmt.Sprintf("Design is %d×%d", sizes[0].Width, sizes[0].Height)
+		}
+	default:
+		m.sizePrompt.Update(msg)
+	}
+	return m, nil
+}
+
+// renderSizePrompt draws the size prompt over view
+func (m Model) renderSizePrompt(view string) string {
+	title := lipgloss.NewStyle().
+		Foreground(m.theme.Primary).
+		Bold(true).
+		Render("Design size")
+	hint := lipgloss.NewStyle().
+		Foreground(m.theme.TextMuted).
+		Render("WIDTHxHEIGHT, empty to follow the window\nenter set · esc cancel")
+
+	box := lipgloss.NewStyle().
+		Background(m.theme.Surface).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Primary).
+		Padding(1, 2).
+		Render(title + "\n\n" + m.sizePrompt.View() + "\n\n" + hint)
+
+	w, h := lipgloss.Size(box)
+	return compositor.Overlay(view, box, max((m.width-w)/2, 0), max((m.height-h)/2, 0))
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newLargeDesign returns the designer on the canvas with the design
+// set to 200×60, larger than the view
+func newLargeDesign(t *testing.T) Model {
+	t.Helper()
+	m := press(newTestModel(t), "tab", "W", "200x60", "enter")
+	if m.sizePrompt != nil || !m.sized || m.canvas.Width != 200 || m.canvas.Height != 60 {
+		t.Fatalf("design %d×%d, %q", m.canvas.Width, m.canvas.Height, m.message)
+	}
+	if !m.canvas.Overflows() {
+		t.Fatalf("a 200×60 design fits a %d×%d view", m.canvas.ViewWidth, m.canvas.ViewHeight)
+	}
+	return m
+}
+
+func TestDesignSize(t *testing.T) {
+	m := newLargeDesign(t)
+	m = press(m, "u")
+	if m.canvas.Width == 200 {
+		t.Error("undo kept the design size")
+	}
+
+	// An empty size follows the window again
+	m = newLargeDesign(t)
+	m = press(m, "W", "ctrl+u", "enter")
+	if m.sized || m.canvas.Width != m.canvas.ViewWidth {
+		t.Errorf("design %d×%d for a %d wide view, want it to follow the window", m.canvas.Width, m.canvas.Height, m.canvas.ViewWidth)
+	}
+}
+
+func TestPan(t *testing.T) {
+	m := newLargeDesign(t)
+	cx, cy := m.canvas.CursorX, m.canvas.CursorY
+	m = press(m, "ctrl+right", "ctrl+down")
+	if m.canvas.ScrollX != panStep || m.canvas.ScrollY != panStep/2 {
+		t.Fatalf("scrolled to %d,%d, want %d,%d", m.canvas.ScrollX, m.canvas.ScrollY, panStep, panStep/2)
+	}
+	// Panning leaves the cursor where it is
+	if m.canvas.CursorX != cx || m.canvas.CursorY != cy {
+		t.Errorf("panning moved the cursor to %d,%d", m.canvas.CursorX, m.canvas.CursorY)
+	}
+
+	// As far as the design goes and no further
+	for range 40 {
+		m = press(m, "ctrl+right", "pgdown")
+	}
+	if m.canvas.ScrollX != 200-m.canvas.ViewWidth || m.canvas.ScrollY != 60-m.canvas.ViewHeight {
+		t.Errorf("scrolled to %d,%d past the end of the design", m.canvas.ScrollX, m.canvas.ScrollY)
+	}
+
+	// The wheel pans too, sideways with shift
+	ox, oy := m.canvasOrigin()
+	m = send(m,
+		tea.MouseMsg{X: ox + 1, Y: oy + 1, Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress},
+		tea.MouseMsg{X: ox + 1, Y: oy + 1, Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress, Shift: true})
+	if m.canvas.ScrollX != 200-m.canvas.ViewWidth-4 || m.canvas.ScrollY != 60-m.canvas.ViewHeight-2 {
+		t.Errorf("wheel scrolled to %d,%d", m.canvas.ScrollX, m.canvas.ScrollY)
+	}
+}
+
+func TestViewFollowsCursor(t *testing.T) {
+	m := newLargeDesign(t)
+	for range m.canvas.ViewWidth + 10 {
+		m = press(m, "right")
+	}
+	if x, _ := m.canvas.ToView(m.canvas.CursorX, m.canvas.CursorY); m.canvas.ScrollX == 0 || x < 0 || x >= m.canvas.ViewWidth {
+		t.Errorf("cursor at view column %d, scrolled to %d", x, m.canvas.ScrollX)
+	}
+}
+
+func TestFit(t *testing.T) {
+	m := press(newLargeDesign(t), "z")
+	if !m.canvas.Fit {
+		t.Fatal("z did not fit the design to the view")
+	}
+	// A click in the overview goes back to full size, centered there
+	s := m.canvas.FitScale()
+	ox, oy := m.canvasOrigin()
+	m = send(m, tea.MouseMsg{X: ox + int(150/s), Y: oy + int(40/s), Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if m.canvas.Fit {
+		t.Fatal("clicking the overview stayed in fit mode")
+	}
+	if x, y := m.canvas.ToView(m.canvas.CursorX, m.canvas.CursorY); x < m.canvas.ViewWidth/2-4 || x > m.canvas.ViewWidth/2+4 || y < 0 || y >= m.canvas.ViewHeight {
+		t.Errorf("cursor %d,%d at view %d,%d, want near the middle", m.canvas.CursorX, m.canvas.CursorY, x, y)
+	}
+	if m.canvas.CursorX < 146 || m.canvas.CursorX > 154 {
+		t.Errorf("cursor at x %d, want about 150", m.canvas.CursorX)
+	}
+}
@@ -5,12 +5,17 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/makeatui/makeatui/internal/ui/styles"
 	"github.com/makeatui/makeatui/pkg/schema"
 )
 
 // Generator generates Go code from a canvas
 type Generator struct {
 	Canvas schema.Canvas
+
+	// Warnings lists what the last Generate had to work around, such as
+	// unknown theme tokens. The code is generated all the same.
+	Warnings []string
 }
 
 // NewGenerator creates a new code generator
@@ -20,6 +25,16 @@ func NewGenerator(canvas schema.Canvas) *Generator {
 
 // Generate generates complete Go code for the TUI
 func (g *Generator) Generate() string {
+	g.Warnings = nil
+	sections := []string{
+		g.generateStyles(),
+		g.generateModel(),
+		g.generateInit(),
+		g.generateUpdate(),
+		g.generateView(),
+		g.generateMain(),
+	}
+
 	var sb strings.Builder
 
 	// Package and imports
@@ -35,39 +50,34 @@ import (
 
 `)
 
-	// Styles
-	sb.WriteString(g.generateStyles())
-
-	// Model
-	sb.WriteString(g.generateModel())
-
-	// Init
-	sb.WriteString(g.generateInit())
-
-	// Update
-	sb.WriteString(g.generateUpdate())
-
-	// View
-	sb.WriteString(g.generateView())
+	for _, w := range g.Warnings {
+		sb.WriteString("// Warning: " + w + "\n")
+	}
+	if len(g.Warnings) > 0 {
+		sb.WriteString("\n")
+	}
 
-	// Main
-	sb.WriteString(g.generateMain())
+	for _, section := range sections {
+		sb.WriteString(section)
+	}
 
 	return sb.String()
 }
 
 func (g *Generator) generateStyles() string {
-	return `// Styles - Ultraviolet theme
-var (
-	primaryColor   = lipgloss.Color("#9D4EDD")
-	secondaryColor = lipgloss.Color("#7B2CBF")
-	accentColor    = lipgloss.Color("#E040FB")
-	bgColor        = lipgloss.Color("#0D0221")
-	surfaceColor   = lipgloss.Color("#1A1333")
-	textColor      = lipgloss.Color("#FFFFFF")
-	mutedColor     = lipgloss.Color("#6B6B8D")
-	borderColor    = lipgloss.Color("#3D2C5E")
+	theme := styles.Lookup(g.Canvas.Theme)
 
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// Styles - %s theme\n", theme.Name))
+	sb.WriteString("var (\n")
+	width := 0
+	for _, tok := range styles.Tokens {
+		width = max(width, len(tokenVar(tok.Name)))
+	}
+	for _, tok := range styles.Tokens {
+		sb.WriteString(fmt.Sprintf("\t%-*s = lipgloss.Color(%q)\n", width, tokenVar(tok.Name), string(tok.Color(theme))))
+	}
+	sb.WriteString(`
 	titleStyle = lipgloss.NewStyle().
 		Foreground(primaryColor).
 		Bold(true).
@@ -80,21 +90,90 @@ var (
 
 	buttonStyle = lipgloss.NewStyle().
 		Background(surfaceColor).
-		Foreground(textColor).
+		Foreground(textPrimaryColor).
 		Padding(0, 3).
 		BorderStyle(lipgloss.RoundedBorder()).
 		BorderForeground(borderColor)
 
 	buttonActiveStyle = lipgloss.NewStyle().
 		Background(primaryColor).
-		Foreground(textColor).
+		Foreground(textPrimaryColor).
 		Padding(0, 3).
 		BorderStyle(lipgloss.RoundedBorder()).
 		BorderForeground(accentColor).
 		Bold(true)
 )
 
-`
+`)
+	return sb.String()
+}
+
+// tokenVar returns the generated variable name for a design token,
+// e.g. "text-muted" becomes "textMutedColor"
+func tokenVar(name string) string {
+	parts := strings.Split(name, "-")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "") + "Color"
+}
+
+// colorExpr returns Go code for a style color of the named component:
+// theme tokens become the generated theme variables, anything else a
+// lipgloss.Color literal. An unknown token falls back to the theme's
+// text color, with a warning.
+func (g *Generator) colorExpr(component, color string) string {
+	if styles.IsToken(color) {
+		if tok, ok := styles.LookupToken(color); ok {
+			return tokenVar(tok.Name)
+		}
+		g.Warnings = append(g.Warnings, fmt.Sprintf("%s: unknown theme token %q, using $text-primary", component, color))
+		return tokenVar("text-primary")
+	}
+	return fmt.Sprintf("lipgloss.Color(%q)", color)
+}
+
+// styleCalls returns the chained lipgloss calls for a component's colors
+// and text attributes, e.g. ".Foreground(errorColor).Bold(true)"
+func (g *Generator) styleCalls(component string, st schema.Style) string {
+	var sb strings.Builder
+	if st.Foreground != "" {
+		sb.WriteString(".Foreground(" + g.colorExpr(component, st.Foreground) + ")")
+	}
+	if st.Background != "" {
+		sb.WriteString(".Background(" + g.colorExpr(component, st.Background) + ")")
+	}
+	if st.Border != nil && st.Border.Color != "" {
+		sb.WriteString(".BorderForeground(" + g.colorExpr(component, st.Border.Color) + ")")
+	}
+	if st.Bold {
+		sb.WriteString(".Bold(true)")
+	}
+	if st.Italic {
+		sb.WriteString(".Italic(true)")
+	}
+	if st.Underline {
+		sb.WriteString(".Underline(true)")
+	}
+	return sb.String()
+}
+
+// components lists the visible components in stacking order, each
+// container followed by its children
+func (g *Generator) components() []schema.Component {
+	var result []schema.Component
+	var walk func(comps []schema.Component)
+	walk = func(comps []schema.Component) {
+		for _, comp := range schema.Stack(comps) {
+			if comp.Hidden {
+				continue
+			}
+			result = append(result, comp)
+			walk(comp.Children)
+		}
+	}
+	walk(g.Canvas.Components)
+	return result
 }
 
 func (g *Generator) generateModel() string {
@@ -105,7 +184,7 @@ func (g *Generator) generateModel() string {
 	sb.WriteString("\theight int\n")
 
 	// Add state for each component
-	for i, comp := range g.Canvas.Components {
+	for i, comp := range g.components() {
 		switch comp.Type {
 		case schema.TypeInput:
 			sb.WriteString(fmt.Sprintf("\tinput%d string\n", i))
@@ -158,7 +237,7 @@ func (g *Generator) generateView() string {
 	sb.WriteString("\tvar content string\n\n")
 
 	// Generate view code for each component
-	for i, comp := range g.Canvas.Components {
+	for i, comp := range g.components() {
 		sb.WriteString(g.generateComponentView(i, comp))
 	}
 
@@ -173,19 +252,23 @@ func (g *Generator) generateComponentView(index int, comp schema.Component) stri
 	switch comp.Type {
 	case schema.TypeBox:
 		sb.WriteString(fmt.Sprintf("\t// Box: %s\n", comp.Name))
-		sb.WriteString(fmt.Sprintf("\tbox%d := boxStyle.Width(%d).Height(%d).Render(%q)\n",
-			index, comp.Size.Width, comp.Size.Height, comp.Text))
+		sb.WriteString(fmt.Sprintf("\tbox%d := boxStyle.Width(%d).Height(%d)%s.Render(%q)\n",
+			index, comp.Size.Width, comp.Size.Height, g.styleCalls(comp.Name, comp.Style), comp.Text))
 		sb.WriteString(fmt.Sprintf("\tcontent += box%d + \"\\n\"\n\n", index))
 
 	case schema.TypeText:
 		sb.WriteString(fmt.Sprintf("\t// Text: %s\n", comp.Name))
-		sb.WriteString(fmt.Sprintf("\ttext%d := lipgloss.NewStyle().Foreground(textColor).Render(%q)\n",
-			index, comp.Text))
+		style := comp.Style
+		if style.Foreground == "" {
+			style.Foreground = styles.TokenPrefix + "text-primary"
+		}
+		sb.WriteString(fmt.Sprintf("\ttext%d := lipgloss.NewStyle()%s.Render(%q)\n",
+			index, g.styleCalls(comp.Name, style), comp.Text))
 		sb.WriteString(fmt.Sprintf("\tcontent += text%d + \"\\n\"\n\n", index))
 
 	case schema.TypeButton:
 		sb.WriteString(fmt.Sprintf("\t// Button: %s\n", comp.Name))
-		sb.WriteString(fmt.Sprintf("\tbutton%d := buttonStyle.Render(%q)\n", index, comp.Text))
+		sb.WriteString(fmt.Sprintf("\tbutton%d := buttonStyle%s.Render(%q)\n", index, g.styleCalls(comp.Name, comp.Style), comp.Text))
 		sb.WriteString(fmt.Sprintf("\tcontent += button%d + \"\\n\"\n\n", index))
 
 	default:
@@ -0,0 +1,35 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestUnknownToken(t *testing.T) {
+	text := schema.NewComponent(schema.TypeText, "Title")
+	text.Text = "Hello"
+	text.Style.Foreground = "$primray"
+	text.Style.Background = "$surface"
+
+	g := NewGenerator(schema.Canvas{Components: []schema.Component{text}})
+	code := g.Generate()
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", code, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	if !strings.Contains(code, ".Foreground(textPrimaryColor).Background(surfaceColor)") {
+		t.Errorf("unknown token did not fall back to the text color:\n%s", code)
+	}
+	want := `Title: unknown theme token "$primray", using $text-primary`
+	if len(g.Warnings) != 1 || g.Warnings[0] != want || !strings.Contains(code, "// Warning: "+want) {
+		t.Errorf("warnings = %q, want %q", g.Warnings, want)
+	}
+
+	g.Generate()
+	if len(g.Warnings) != 1 {
+		t.Errorf("warnings pile up across runs: %q", g.Warnings)
+	}
+}
@@ -0,0 +1,34 @@
+// Package config locates MakeaTUI's per-user configuration files
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// EnvDir overrides the configuration directory when set
+const EnvDir = "MAKEATUI_CONFIG_DIR"
+
+// Dir returns the MakeaTUI configuration directory, normally
+// $XDG_CONFIG_HOME/makeatui (or the platform equivalent)
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = "."
+	}
+	return filepath.Join(base, "makeatui")
+}
+
+// Path returns a path inside the configuration directory
+func Path(elem ...string) string {
+	return filepath.Join(append([]string{Dir()}, elem...)...)
+}
+
+// EnsureDir creates a directory inside the configuration directory
+func EnsureDir(elem ...string) (string, error) {
+	dir := Path(elem...)
+	return dir, os.MkdirAll(dir, 0755)
+}
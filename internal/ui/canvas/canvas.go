@@ -3,24 +3,40 @@ package canvas
 
 import (
 	"fmt"
-	"strings"
+	"sort"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/makeatui/makeatui/internal/ui/components"
 	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/render"
 	"github.com/makeatui/makeatui/pkg/schema"
 )
 
-// Canvas represents the main design area
+// Canvas represents the main design area. Width and Height are the
+// size of the design, of which the view shows ViewWidth by ViewHeight
+// cells from ScrollX, ScrollY.
 type Canvas struct {
-	Width      int
-	Height     int
-	Components []schema.Component
-	Selected   int
-	Theme      styles.Theme
-	CursorX    int
-	CursorY    int
-	Mode       Mode
+	Width       int
+	Height      int
+	ViewWidth   int
+	ViewHeight  int
+	ScrollX     int
+	ScrollY     int
+	Fit         bool // show the whole design scaled down instead
+	ShowMinimap bool // show a minimap when the design is larger than the view
+	Components  []schema.Component
+	Selected    int
+	Multi       map[string]bool // IDs selected along with Selected
+	Theme       styles.Theme
+	CursorX     int
+	CursorY     int
+	Mode        Mode
+	Anchor      *schema.Position // corner of the rectangle being drawn, see DrawRect
+	Snap        bool             // placement snaps to Grid and to guides
+	Grid        schema.Size      // snap grid, one of GridSizes
+	Guides      []Guide          // alignment guides to show while editing
+
+	followed schema.Position // cursor position the view last followed
 }
 
 // Mode represents canvas interaction mode
@@ -36,14 +52,19 @@ const (
 // New creates a new canvas
 func New(width, height int, theme styles.Theme) *Canvas {
 	return &Canvas{
-		Width:      width,
-		Height:     height,
-		Components: []schema.Component{},
-		Selected:   -1,
-		Theme:      theme,
-		CursorX:    width / 2,
-		CursorY:    height / 2,
-		Mode:       ModeSelect,
+		Width:       width,
+		Height:      height,
+		ViewWidth:   width,
+		ViewHeight:  height,
+		ShowMinimap: true,
+		Components:  []schema.Component{},
+		Selected:    -1,
+		Theme:       theme,
+		CursorX:     width / 2,
+		CursorY:     height / 2,
+		Mode:        ModeSelect,
+		Snap:        true,
+		Grid:        GridSizes[2],
 	}
 }
 
@@ -73,6 +94,60 @@ func (c *Canvas) MoveSelected(dx, dy int) {
 	}
 }
 
+// Select makes component i the only selected one (-1 selects nothing)
+func (c *Canvas) Select(i int) {
+	c.Selected = i
+	c.Multi = nil
+}
+
+// ToggleSelect adds component i to the selection, or removes it, as a
+// shift-click does
+func (c *Canvas) ToggleSelect(i int) {
+	if i < 0 || i >= len(c.Components) {
+		return
+	}
+	id := c.Components[i].ID
+	switch {
+	case c.Selected < 0:
+		c.Selected = i
+	case !c.IsSelected(i):
+		if c.Multi == nil {
+			c.Multi = map[string]bool{}
+		}
+		c.Multi[id] = true
+	case i != c.Selected:
+		delete(c.Multi, id)
+	default:
+		// Hand the primary selection to another selected component
+		c.Selected = -1
+		for _, j := range c.SelectedIndices() {
+			c.Selected = j
+			delete(c.Multi, c.Components[j].ID)
+			break
+		}
+	}
+}
+
+// IsSelected reports whether component i is selected
+func (c *Canvas) IsSelected(i int) bool {
+	if i < 0 || i >= len(c.Components) {
+		return false
+	}
+	return i == c.Selected || c.Multi[c.Components[i].ID]
+}
+
+// SelectedIndices returns the indices of all selected components, in
+// stacking order
+func (c *Canvas) SelectedIndices() []int {
+	var result []int
+	for i := range c.Components {
+		if c.IsSelected(i) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
 // GetSelected returns the selected component
 func (c *Canvas) GetSelected() *schema.Component {
 	if c.Selected >= 0 && c.Selected < len(c.Components) {
@@ -81,85 +156,109 @@ func (c *Canvas) GetSelected() *schema.Component {
 	return nil
 }
 
-// Render renders the canvas with all components
+// Render renders the view of the canvas inside rulers, above a mode
+// line
 func (c *Canvas) Render() string {
-	// Create the grid
-	grid := make([][]rune, c.Height)
-	for y := range grid {
-		grid[y] = make([]rune, c.Width)
-		for x := range grid[y] {
-			grid[y][x] = ' '
-		}
+	// Add mode indicator
+	modeStyle := lipgloss.NewStyle().
+		Foreground(c.Theme.TextMuted).
+		Italic(true).
+		MaxWidth(RulerWidth + c.ViewWidth)
+	modeText := fmt.Sprintf(" Mode: %s | Components: %d | %s | %s ", c.modeString(), len(c.Components), c.snapString(), c.viewString())
+	_, size, drawing := c.DrawRect()
+	switch {
+	case c.Mode == ModeDraw && drawing:
+		modeText += fmt.Sprintf("| %d×%d · space/release to create · esc to cancel ", size.Width, size.Height)
+	case c.Mode == ModeDraw:
+		modeText += "| space or drag to start a rectangle · esc to leave "
+	case drawing:
+		modeText += fmt.Sprintf("| selecting %d×%d · space/release to select · esc to cancel ", size.Width, size.Height)
 	}
 
+	framed := compositor.NewBuffer(RulerWidth+c.ViewWidth, c.ViewHeight+1)
+	framed.DrawBuffer(RulerWidth, 1, c.renderView())
+	c.drawRulers(framed)
+
+	return framed.String() + "\n" + modeStyle.Render(modeText)
+}
+
+// renderDesign renders the whole design at full size, with the
+// selection, guides and cursor
+func (c *Canvas) renderDesign() *compositor.Buffer {
+	buf := compositor.NewBuffer(c.Width, c.Height)
+
 	// Draw grid dots for visual reference
+	dot := compositor.Cell{Content: "·", Width: 1}
 	for y := 0; y < c.Height; y += 4 {
 		for x := 0; x < c.Width; x += 8 {
-			if x < c.Width && y < c.Height {
-				grid[y][x] = '·'
-			}
+			buf.Fill(x, y, 1, 1, dot)
 		}
 	}
 
-	// Convert grid to string
-	var lines []string
-	for _, row := range grid {
-		lines = append(lines, string(row))
+	// Components stacked by z-index, later ones on top when equal
+	var layers []compositor.Layer
+	for i, comp := range c.Components {
+		comp.Selected = c.IsSelected(i)
+		layers = c.componentLayers(layers, comp, 0, 0, comp.ZIndex)
 	}
-	background := strings.Join(lines, "\n")
+	buf.Compose(layers...)
 
-	// Render components on top
-	renderedComps := []string{}
-	for i, comp := range c.Components {
-		comp.Selected = (i == c.Selected)
-		rendered := c.renderComponent(comp)
-		renderedComps = append(renderedComps, rendered)
+	// Alignment guides, under the handles
+	c.drawGuides(buf)
+
+	// Resize handles when a single component is selected
+	if sel := c.SelectedIndices(); len(sel) == 1 && !c.Components[sel[0]].Hidden {
+		handleStyle := lipgloss.NewStyle().Foreground(c.Theme.Accent)
+		for _, h := range Handles(c.Components[sel[0]]) {
+			buf.Draw(h.X, h.Y, handleStyle.Render("■"))
+		}
 	}
 
+	// Rectangle being drawn or selecting
+	c.drawOutline(buf)
+
 	// Draw cursor
 	cursorStyle := lipgloss.NewStyle().
 		Foreground(c.Theme.Accent).
 		Bold(true)
-	cursor := cursorStyle.Render("╋")
+	buf.Draw(c.CursorX, c.CursorY, cursorStyle.Render("╋"))
+	return buf
+}
 
-	// Combine background and components
-	result := background
-	for i, comp := range c.Components {
-		result = c.placeAt(result, renderedComps[i], comp.Position.X, comp.Position.Y)
+// componentLayers appends the layers of a component and its children,
+// which are placed relative to it, offset by dx, dy. They all share
+// the component's z-index, children stacked above it in their own
+// order. Hidden components are left out.
+func (c *Canvas) componentLayers(layers []compositor.Layer, comp schema.Component, dx, dy, z int) []compositor.Layer {
+	if comp.Hidden {
+		return layers
 	}
+	x, y := dx+comp.Position.X, dy+comp.Position.Y
+	content := render.Component(comp, c.Theme, render.State{Selected: comp.Selected})
+	layers = append(layers, compositor.Layer{Content: content, X: x, Y: y, Z: z})
+	for _, child := range schema.Stack(comp.Children) {
+		layers = c.componentLayers(layers, child, x, y, z)
+	}
+	return layers
+}
 
-	// Place cursor
-	result = c.placeAt(result, cursor, c.CursorX, c.CursorY)
-
-	// Add mode indicator
-	modeStyle := lipgloss.NewStyle().
-		Foreground(c.Theme.TextMuted).
-		Italic(true)
-	modeText := fmt.Sprintf(" Mode: %s | Components: %d ", c.modeString(), len(c.Components))
-
-	return result + "\n" + modeStyle.Render(modeText)
-}
-
-func (c *Canvas) renderComponent(comp schema.Component) string {
-	switch comp.Type {
-	case schema.TypeBox:
-		return components.RenderBox(comp, c.Theme)
-	case schema.TypeText:
-		return components.RenderText(comp, c.Theme)
-	case schema.TypeButton:
-		return components.RenderButton(comp, c.Theme)
-	case schema.TypeProgress:
-		return components.RenderProgress(comp, c.Theme)
-	case schema.TypeList:
-		return components.RenderList(comp, c.Theme, 0)
-	default:
-		return components.RenderBox(comp, c.Theme)
+// StackOrder returns the indices of the components from the bottom of
+// the stack to the top
+func (c *Canvas) StackOrder() []int {
+	order := make([]int, len(c.Components))
+	for i := range order {
+		order[i] = i
 	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.Components[order[i]].ZIndex < c.Components[order[j]].ZIndex
+	})
+	return order
 }
 
-func (c *Canvas) placeAt(base, overlay string, x, y int) string {
-	// Simple placement - in production use proper overlay logic
-	return base // Simplified for now
+// Selectable reports whether component i can be picked on the canvas,
+// which hidden and locked components cannot
+func (c *Canvas) Selectable(i int) bool {
+	return i >= 0 && i < len(c.Components) && !c.Components[i].Hidden && !c.Components[i].Locked
 }
 
 func (c *Canvas) modeString() string {
@@ -177,3 +276,9 @@ func (c *Canvas) modeString() string {
 	}
 }
 
+func (c *Canvas) snapString() string {
+	if !c.Snap {
+		return "Snap: off"
+	}
+	return fmt.Sprintf("Snap: %d×%d", c.Grid.Width, c.Grid.Height)
+}
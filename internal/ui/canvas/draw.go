@@ -0,0 +1,57 @@
+// Package canvas - Rubber-band drawing
+package canvas
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// StartDrawing anchors a new rectangle at the cursor, to draw a
+// component in draw mode or to select components in the others
+func (c *Canvas) StartDrawing() {
+	c.Anchor = &schema.Position{X: c.CursorX, Y: c.CursorY}
+}
+
+// StopDrawing drops the rectangle being drawn
+func (c *Canvas) StopDrawing() {
+	c.Anchor = nil
+}
+
+// DrawRect returns the rectangle spanned by the anchor and the cursor,
+// both included. ok is false when no rectangle is being drawn.
+func (c *Canvas) DrawRect() (pos schema.Position, size schema.Size, ok bool) {
+	if c.Anchor == nil {
+		return pos, size, false
+	}
+	x0, x1 := min(c.Anchor.X, c.CursorX), max(c.Anchor.X, c.CursorX)
+	y0, y1 := min(c.Anchor.Y, c.CursorY), max(c.Anchor.Y, c.CursorY)
+	return schema.Position{X: x0, Y: y0}, schema.Size{Width: x1 - x0 + 1, Height: y1 - y0 + 1}, true
+}
+
+// drawOutline draws the dashed outline of the rectangle being drawn,
+// if any, leaving the components under it visible
+func (c *Canvas) drawOutline(buf *compositor.Buffer) {
+	pos, size, ok := c.DrawRect()
+	if !ok {
+		return
+	}
+	style := lipgloss.NewStyle().Foreground(c.Theme.Accent)
+	if size.Width == 1 || size.Height == 1 {
+		line := strings.Repeat("▪", size.Width)
+		for y := 0; y < size.Height; y++ {
+			buf.Draw(pos.X, pos.Y+y, style.Render(line))
+		}
+		return
+	}
+
+	inner := strings.Repeat("╌", size.Width-2)
+	buf.Draw(pos.X, pos.Y, style.Render("┌"+inner+"┐"))
+	buf.Draw(pos.X, pos.Y+size.Height-1, style.Render("└"+inner+"┘"))
+	for y := pos.Y + 1; y < pos.Y+size.Height-1; y++ {
+		buf.Draw(pos.X, y, style.Render("╎"))
+		buf.Draw(pos.X+size.Width-1, y, style.Render("╎"))
+	}
+}
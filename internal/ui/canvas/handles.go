@@ -0,0 +1,73 @@
+// Package canvas - Resize handles
+package canvas
+
+import "github.com/makeatui/makeatui/pkg/schema"
+
+// Edge is a set of component sides moved by a resize handle
+type Edge int
+
+const (
+	EdgeLeft Edge = 1 << iota
+	EdgeRight
+	EdgeTop
+	EdgeBottom
+)
+
+// Handle is a resize handle on a component's outline
+type Handle struct {
+	X, Y int
+	Edge Edge
+}
+
+// Handles returns the resize handles of a component, in canvas
+// coordinates: its corners and the middle of each side. Handles that
+// would overlap on a small component are left out.
+func Handles(comp schema.Component) []Handle {
+	x0, y0 := comp.Position.X, comp.Position.Y
+	x1, y1 := x0+comp.Size.Width-1, y0+comp.Size.Height-1
+	xm, ym := (x0+x1)/2, (y0+y1)/2
+
+	candidates := []Handle{
+		{x0, y0, EdgeTop | EdgeLeft},
+		{x1, y0, EdgeTop | EdgeRight},
+		{x0, y1, EdgeBottom | EdgeLeft},
+		{x1, y1, EdgeBottom | EdgeRight},
+		{xm, y0, EdgeTop},
+		{xm, y1, EdgeBottom},
+		{x0, ym, EdgeLeft},
+		{x1, ym, EdgeRight},
+	}
+
+	var handles []Handle
+	seen := map[[2]int]bool{}
+	for _, h := range candidates {
+		if seen[[2]int{h.X, h.Y}] {
+			continue
+		}
+		seen[[2]int{h.X, h.Y}] = true
+		handles = append(handles, h)
+	}
+	return handles
+}
+
+// Resize returns a component's bounds after dragging a handle on edge
+// by dx, dy. The opposite sides stay put and sizes never drop below 1.
+func Resize(pos schema.Position, size schema.Size, edge Edge, dx, dy int) (schema.Position, schema.Size) {
+	if edge&EdgeLeft != 0 {
+		dx = min(dx, size.Width-1)
+		pos.X += dx
+		size.Width -= dx
+	}
+	if edge&EdgeRight != 0 {
+		size.Width = max(size.Width+dx, 1)
+	}
+	if edge&EdgeTop != 0 {
+		dy = min(dy, size.Height-1)
+		pos.Y += dy
+		size.Height -= dy
+	}
+	if edge&EdgeBottom != 0 {
+		size.Height = max(size.Height+dy, 1)
+	}
+	return pos, size
+}
@@ -0,0 +1,113 @@
+// Package canvas - Coordinate rulers
+package canvas
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// RulerWidth is the width of the left ruler; the top ruler is one row
+const RulerWidth = 4
+
+// SelectionBounds returns the rectangle around the selected
+// components. ok is false when nothing is selected.
+func (c *Canvas) SelectionBounds() (pos schema.Position, size schema.Size, ok bool) {
+	var comps []schema.Component
+	for _, i := range c.SelectedIndices() {
+		comps = append(comps, c.Components[i])
+	}
+	if len(comps) == 0 {
+		return pos, size, false
+	}
+	pos, size = Bounds(comps)
+	return pos, size, true
+}
+
+// Bounds returns the rectangle around comps, which must not be empty
+func Bounds(comps []schema.Component) (schema.Position, schema.Size) {
+	x0, y0 := comps[0].Position.X, comps[0].Position.Y
+	x1, y1 := x0, y0
+	for _, comp := range comps {
+		x0, y0 = min(x0, comp.Position.X), min(y0, comp.Position.Y)
+		x1 = max(x1, comp.Position.X+comp.Size.Width)
+		y1 = max(y1, comp.Position.Y+comp.Size.Height)
+	}
+	return schema.Position{X: x0, Y: y0}, schema.Size{Width: x1 - x0, Height: y1 - y0}
+}
+
+// drawRulers draws the top ruler, numbered every 10 columns, and the
+// left ruler, numbered every 5 rows, around a view drawn at
+// RulerWidth, 1. The numbers are design coordinates, so they follow
+// the scrolling and the scale of fit mode. The extent of the selection
+// is marked on both.
+func (c *Canvas) drawRulers(buf *compositor.Buffer) {
+	muted := lipgloss.NewStyle().Foreground(c.Theme.TextMuted)
+	accent := lipgloss.NewStyle().Foreground(c.Theme.Accent)
+	pos, size, selected := c.SelectionBounds()
+	sx, sy, sw, sh := pos.X, pos.Y, size.Width, size.Height
+
+	top := []rune(strings.Repeat(" ", c.ViewWidth))
+	for x := range top {
+		if lo, hi := c.span(x, false); lo < c.Width && multiple(lo, hi, 5) >= 0 {
+			top[x] = '╵'
+		}
+	}
+	free := 0 // first column a number may start at without running into the last
+	for x := range top {
+		lo, hi := c.span(x, false)
+		n := multiple(lo, hi, 10)
+		if lo >= c.Width || n < 0 || x < free {
+			continue
+		}
+		label := fmt.Sprint(n)
+		for i, r := range label {
+			if x+i < len(top) {
+				top[x+i] = r
+			}
+		}
+		free = x + len(label) + 1
+	}
+	for x, r := range top {
+		lo, hi := c.span(x, false)
+		style := muted
+		if selected && lo < sx+sw && hi > sx {
+			style = accent
+			if r == ' ' || r == '╵' {
+				r = '▁'
+			}
+		}
+		if c.CursorX >= lo && c.CursorX < hi {
+			style, r = accent.Bold(true), '▼'
+		}
+		buf.Draw(RulerWidth+x, 0, style.Render(string(r)))
+	}
+
+	for y := 0; y < c.ViewHeight; y++ {
+		lo, hi := c.span(y, true)
+		label, edge := "   ", "│"
+		if n := multiple(lo, hi, 5); n >= 0 && lo < c.Height {
+			label = fmt.Sprintf("%3d", n%1000)
+		}
+		style := muted
+		if selected && lo < sy+sh && hi > sy {
+			style, edge = accent, "┃"
+		}
+		if c.CursorY >= lo && c.CursorY < hi {
+			style, edge = accent.Bold(true), "▶"
+		}
+		buf.Draw(0, y+1, style.Render(label+edge))
+	}
+}
+
+// multiple returns the first multiple of n from lo up to hi, or -1
+func multiple(lo, hi, n int) int {
+	m := (lo + n - 1) / n * n
+	if lo < 0 || m >= hi {
+		return -1
+	}
+	return m
+}
@@ -0,0 +1,173 @@
+// Package canvas - Snap grid and smart guides
+package canvas
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// GridSizes are the snap grids offered, finest first
+var GridSizes = []schema.Size{{Width: 1, Height: 1}, {Width: 2, Height: 1}, {Width: 4, Height: 2}, {Width: 8, Height: 4}}
+
+// guideDistance is how close, in cells, an edge must come to another
+// component's for a guide to pull it into line
+const guideDistance = 1
+
+// Guide is a line across the canvas where an edge or the center of the
+// component being moved lines up with another component's
+type Guide struct {
+	Vertical bool
+	At       int // column of a vertical guide, row of a horizontal one
+}
+
+// Snapper pulls coordinates onto the grid and onto the edges and
+// centers of the components that are not being moved
+type Snapper struct {
+	grid   schema.Size // zero when snapping is off
+	xs, ys map[int]bool
+}
+
+// Snapper returns a snapper for moving the components for which moving
+// returns true. Guides work with snapping off too, but only show.
+func (c *Canvas) Snapper(moving func(i int) bool) *Snapper {
+	s := &Snapper{xs: map[int]bool{}, ys: map[int]bool{}}
+	if c.Snap {
+		s.grid = c.Grid
+	}
+	for i, comp := range c.Components {
+		if moving(i) || comp.Hidden {
+			continue
+		}
+		for _, x := range lines(comp.Position.X, comp.Size.Width) {
+			s.xs[x] = true
+		}
+		for _, y := range lines(comp.Position.Y, comp.Size.Height) {
+			s.ys[y] = true
+		}
+	}
+	return s
+}
+
+// lines returns the first cell, the middle and the last cell of a span
+func lines(start, length int) []int {
+	return []int{start, start + (length-1)/2, start + length - 1}
+}
+
+// SnapX returns how far to shift a component along x so that one of
+// the columns (left edge first) lands on a guide, or failing that the
+// first of them on the grid
+func (s *Snapper) SnapX(columns ...int) int {
+	return snap(s.xs, s.grid.Width, columns)
+}
+
+// SnapY is SnapX for rows
+func (s *Snapper) SnapY(rows ...int) int {
+	return snap(s.ys, s.grid.Height, rows)
+}
+
+func snap(targets map[int]bool, step int, values []int) int {
+	if step > 0 {
+		for d := 0; d <= guideDistance; d++ {
+			for _, v := range values {
+				if targets[v-d] {
+					return -d
+				}
+				if targets[v+d] {
+					return d
+				}
+			}
+		}
+	}
+	if step > 1 && len(values) > 0 {
+		return RoundToGrid(values[0], step) - values[0]
+	}
+	return 0
+}
+
+// Move returns how far to shift a rectangle at pos with size so that
+// its edges or middle meet a guide or the grid
+func (s *Snapper) Move(pos schema.Position, size schema.Size) (dx, dy int) {
+	return s.SnapX(lines(pos.X, size.Width)...), s.SnapY(lines(pos.Y, size.Height)...)
+}
+
+// Resize snaps the edges of a rectangle that are being dragged
+func (s *Snapper) Resize(pos schema.Position, size schema.Size, edge Edge) (schema.Position, schema.Size) {
+	if edge&EdgeLeft != 0 {
+		d := min(s.SnapX(pos.X), size.Width-1)
+		pos.X += d
+		size.Width -= d
+	}
+	if edge&EdgeRight != 0 {
+		size.Width = max(size.Width+s.SnapX(pos.X+size.Width-1), 1)
+	}
+	if edge&EdgeTop != 0 {
+		d := min(s.SnapY(pos.Y), size.Height-1)
+		pos.Y += d
+		size.Height -= d
+	}
+	if edge&EdgeBottom != 0 {
+		size.Height = max(size.Height+s.SnapY(pos.Y+size.Height-1), 1)
+	}
+	return pos, size
+}
+
+// RoundToGrid returns the grid line nearest to v
+func RoundToGrid(v, step int) int {
+	if step <= 1 {
+		return v
+	}
+	if v < 0 {
+		return -RoundToGrid(-v, step)
+	}
+	return (v + step/2) / step * step
+}
+
+// NextGridLine returns the first grid line past v in direction dir
+// (-1 or 1)
+func NextGridLine(v, dir, step int) int {
+	if step <= 1 {
+		return v + dir
+	}
+	next := v + dir
+	for next%step != 0 {
+		next += dir
+	}
+	return next
+}
+
+// GuidesFor returns the guides for a component at pos with size: the
+// lines where its edges or center meet those of the other components
+func (s *Snapper) GuidesFor(pos schema.Position, size schema.Size) []Guide {
+	var guides []Guide
+	seen := map[Guide]bool{}
+	for _, x := range lines(pos.X, size.Width) {
+		if g := (Guide{Vertical: true, At: x}); s.xs[x] && !seen[g] {
+			seen[g] = true
+			guides = append(guides, g)
+		}
+	}
+	for _, y := range lines(pos.Y, size.Height) {
+		if g := (Guide{At: y}); s.ys[y] && !seen[g] {
+			seen[g] = true
+			guides = append(guides, g)
+		}
+	}
+	return guides
+}
+
+// drawGuides draws the guides across the canvas
+func (c *Canvas) drawGuides(buf *compositor.Buffer) {
+	style := lipgloss.NewStyle().Foreground(c.Theme.Warning)
+	for _, g := range c.Guides {
+		if g.Vertical {
+			for y := 0; y < c.Height; y++ {
+				buf.Draw(g.At, y, style.Render("┆"))
+			}
+		} else {
+			buf.Draw(0, g.At, style.Render(strings.Repeat("┄", c.Width)))
+		}
+	}
+}
@@ -0,0 +1,286 @@
+// Package canvas - Viewport, fit mode and minimap
+package canvas
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
+)
+
+// followMargin is how close, in cells, the cursor may come to the edge
+// of the view before the view scrolls along
+const followMargin = 2
+
+// Minimap size limits, in cells inside its border
+const (
+	minimapWidth  = 24
+	minimapHeight = 8
+)
+
+// SetView sets the size of the area the canvas is shown in, keeping
+// the scroll position inside the design
+func (c *Canvas) SetView(width, height int) {
+	c.ViewWidth, c.ViewHeight = max(width, 1), max(height, 1)
+	c.ScrollBy(0, 0)
+}
+
+// Overflows reports whether the design is larger than the view
+func (c *Canvas) Overflows() bool {
+	return c.Width > c.ViewWidth || c.Height > c.ViewHeight
+}
+
+// ScrollBy pans the view by dx, dy cells, as far as the design goes
+func (c *Canvas) ScrollBy(dx, dy int) {
+	c.ScrollX = max(min(c.ScrollX+dx, c.Width-c.ViewWidth), 0)
+	c.ScrollY = max(min(c.ScrollY+dy, c.Height-c.ViewHeight), 0)
+}
+
+// CenterOn pans the view so that x, y is in its middle
+func (c *Canvas) CenterOn(x, y int) {
+	c.ScrollX, c.ScrollY = x-c.ViewWidth/2, y-c.ViewHeight/2
+	c.ScrollBy(0, 0)
+}
+
+// FollowCursor pans the view to keep the cursor in sight after it has
+// moved. Panning on its own leaves the cursor where it is.
+func (c *Canvas) FollowCursor() {
+	if c.CursorX == c.followed.X && c.CursorY == c.followed.Y {
+		return
+	}
+	c.followed.X, c.followed.Y = c.CursorX, c.CursorY
+	mx, my := min(followMargin, (c.ViewWidth-1)/2), min(followMargin, (c.ViewHeight-1)/2)
+	if c.CursorX < c.ScrollX+mx {
+		c.ScrollX = c.CursorX - mx
+	}
+	if c.CursorX >= c.ScrollX+c.ViewWidth-mx {
+		c.ScrollX = c.CursorX - c.ViewWidth + mx + 1
+	}
+	if c.CursorY < c.ScrollY+my {
+		c.ScrollY = c.CursorY - my
+	}
+	if c.CursorY >= c.ScrollY+c.ViewHeight-my {
+		c.ScrollY = c.CursorY - c.ViewHeight + my + 1
+	}
+	c.ScrollBy(0, 0)
+}
+
+// HoldView keeps the view where it is although the cursor has moved,
+// as when the mouse puts it somewhere in view
+func (c *Canvas) HoldView() {
+	c.followed.X, c.followed.Y = c.CursorX, c.CursorY
+}
+
+// ToView returns the cell of the view that shows design cell x, y
+func (c *Canvas) ToView(x, y int) (int, int) {
+	if c.Fit {
+		s := c.FitScale()
+		return int(float64(x) / s), int(float64(y) / s)
+	}
+	return x - c.ScrollX, y - c.ScrollY
+}
+
+// FromView returns the design cell shown at column x, row y of the
+// view: the first of those it covers in fit mode
+func (c *Canvas) FromView(x, y int) (int, int) {
+	if c.Fit {
+		s := c.FitScale()
+		return int(float64(x) * s), int(float64(y) * s)
+	}
+	return x + c.ScrollX, y + c.ScrollY
+}
+
+// FitScale is how many design cells a cell of the view stands for, in
+// each direction, in fit mode
+func (c *Canvas) FitScale() float64 {
+	return max(float64(c.Width)/float64(c.ViewWidth), float64(c.Height)/float64(c.ViewHeight), 1)
+}
+
+// span returns the range of design cells that column or row v of the
+// view covers
+func (c *Canvas) span(v int, vertical bool) (lo, hi int) {
+	if !c.Fit {
+		if vertical {
+			return v + c.ScrollY, v + c.ScrollY + 1
+		}
+		return v + c.ScrollX, v + c.ScrollX + 1
+	}
+	s := c.FitScale()
+	lo = int(float64(v) * s)
+	return lo, max(int(float64(v+1)*s), lo+1)
+}
+
+// renderView renders the part of the design in view, or all of it
+// scaled down in fit mode
+func (c *Canvas) renderView() *compositor.Buffer {
+	if c.Fit {
+		view := c.overview(c.ViewWidth, c.ViewHeight, c.FitScale())
+		s := c.FitScale()
+		style := lipgloss.NewStyle().Foreground(c.Theme.Accent)
+		if c.Overflows() {
+			// Where the view goes back to at full size
+			x0, y0 := int(float64(c.ScrollX)/s), int(float64(c.ScrollY)/s)
+			x1 := int(math.Ceil(float64(c.ScrollX+c.ViewWidth) / s))
+			y1 := int(math.Ceil(float64(c.ScrollY+c.ViewHeight) / s))
+			drawFrame(view, x0, y0, x1-x0, y1-y0, style)
+		}
+		view.Draw(int(float64(c.CursorX)/s), int(float64(c.CursorY)/s), style.Bold(true).Render("╋"))
+		return view
+	}
+
+	view := compositor.NewBuffer(c.ViewWidth, c.ViewHeight)
+	view.DrawBuffer(-c.ScrollX, -c.ScrollY, c.renderDesign())
+
+	// Shade what lies past the edges of the design
+	past := compositor.Parse(lipgloss.NewStyle().Foreground(c.Theme.Surface).Render("░")).Cell(0, 0)
+	if right := c.Width - c.ScrollX; right < c.ViewWidth {
+		view.Fill(right, 0, c.ViewWidth-right, c.ViewHeight, past)
+	}
+	if bottom := c.Height - c.ScrollY; bottom < c.ViewHeight {
+		view.Fill(0, bottom, c.ViewWidth, c.ViewHeight-bottom, past)
+	}
+
+	c.drawMinimap(view)
+	return view
+}
+
+// Minimap returns where the minimap is drawn inside its border, in
+// view cells, and how many design cells each of its cells stands for.
+// ok is false when no minimap is shown.
+func (c *Canvas) Minimap() (x, y, w, h int, s float64, ok bool) {
+	if !c.ShowMinimap || c.Fit || !c.Overflows() {
+		return 0, 0, 0, 0, 0, false
+	}
+	w = min(minimapWidth, c.ViewWidth/3)
+	h = min(minimapHeight, c.ViewHeight/3)
+	if w < 4 || h < 2 {
+		return 0, 0, 0, 0, 0, false
+	}
+	s = max(float64(c.Width)/float64(w), float64(c.Height)/float64(h))
+	w = int(math.Ceil(float64(c.Width) / s))
+	h = int(math.Ceil(float64(c.Height) / s))
+	return c.ViewWidth - w - 1, c.ViewHeight - h - 1, w, h, s, true
+}
+
+// drawMinimap draws the whole design, scaled down, in the bottom right
+// corner of the view, with a frame around the part in view
+func (c *Canvas) drawMinimap(view *compositor.Buffer) {
+	mx, my, w, h, s, ok := c.Minimap()
+	if !ok {
+		return
+	}
+	mini := c.overview(w, h, s)
+	x0, y0 := int(float64(c.ScrollX)/s), int(float64(c.ScrollY)/s)
+	x1 := min(int(math.Ceil(float64(c.ScrollX+c.ViewWidth)/s)), w)
+	y1 := min(int(math.Ceil(float64(c.ScrollY+c.ViewHeight)/s)), h)
+	drawFrame(mini, x0, y0, x1-x0, y1-y0, lipgloss.NewStyle().Foreground(c.Theme.Accent))
+
+	box := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(c.Theme.Border).
+		Render(mini.String())
+	view.Draw(mx-1, my-1, box)
+}
+
+// overview renders the design scaled down by s into width by height
+// cells, each split into two pixels with half blocks. Components are
+// filled in, the selection in the accent color.
+func (c *Canvas) overview(width, height int, s float64) *compositor.Buffer {
+	// The topmost visible component over each design cell
+	owner := make([]int, c.Width*c.Height)
+	for i := range owner {
+		owner[i] = -1
+	}
+	for _, i := range c.StackOrder() {
+		comp := c.Components[i]
+		if comp.Hidden {
+			continue
+		}
+		for y := max(comp.Position.Y, 0); y < min(comp.Position.Y+comp.Size.Height, c.Height); y++ {
+			for x := max(comp.Position.X, 0); x < min(comp.Position.X+comp.Size.Width, c.Width); x++ {
+				owner[y*c.Width+x] = i
+			}
+		}
+	}
+
+	palette := []lipgloss.Color{c.Theme.Primary, c.Theme.Secondary, c.Theme.Info, c.Theme.Success, c.Theme.Warning}
+	pixel := func(px, py int) lipgloss.Color {
+		x, y := int((float64(px)+0.5)*s), int((float64(py)+0.5)*s/2)
+		if x >= c.Width || y >= c.Height {
+			return ""
+		}
+		i := owner[y*c.Width+x]
+		switch {
+		case i < 0:
+			return c.Theme.SurfaceLight
+		case c.IsSelected(i):
+			return c.Theme.Accent
+		case c.Components[i].Style.Border != nil && c.Components[i].Style.Border.Color != "":
+			return c.Theme.Resolve(c.Components[i].Style.Border.Color)
+		}
+		return palette[i%len(palette)]
+	}
+
+	var sb strings.Builder
+	for y := 0; y < height; y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		for x := 0; x < width; x++ {
+			sb.WriteString(halfBlock(pixel(x, 2*y), pixel(x, 2*y+1)))
+		}
+	}
+	return compositor.Parse(sb.String())
+}
+
+// halfBlock draws a cell as two pixels, top and bottom, either of which
+// may be empty
+func halfBlock(top, bottom lipgloss.Color) string {
+	switch {
+	case top == "" && bottom == "":
+		return " "
+	case top == bottom:
+		return lipgloss.NewStyle().Foreground(top).Render("█")
+	case top == "":
+		return lipgloss.NewStyle().Foreground(bottom).Render("▄")
+	case bottom == "":
+		return lipgloss.NewStyle().Foreground(top).Render("▀")
+	}
+	return lipgloss.NewStyle().Foreground(top).Background(bottom).Render("▀")
+}
+
+// drawFrame draws a thin frame just inside the rectangle x, y, width,
+// height
+func drawFrame(buf *compositor.Buffer, x, y, width, height int, style lipgloss.Style) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if width == 1 || height == 1 {
+		line := strings.Repeat("▪", width)
+		for row := y; row < y+height; row++ {
+			buf.Draw(x, row, style.Render(line))
+		}
+		return
+	}
+	inner := strings.Repeat("─", width-2)
+	buf.Draw(x, y, style.Render("┌"+inner+"┐"))
+	buf.Draw(x, y+height-1, style.Render("└"+inner+"┘"))
+	for row := y + 1; row < y+height-1; row++ {
+		buf.Draw(x, row, style.Render("│"))
+		buf.Draw(x+width-1, row, style.Render("│"))
+	}
+}
+
+// viewString describes the view for the mode line: the scale in fit
+// mode, or the part of a larger design in view
+func (c *Canvas) viewString() string {
+	switch {
+	case c.Fit:
+		return fmt.Sprintf("Fit %d×%d ÷%.1f", c.Width, c.Height, c.FitScale())
+	case c.Overflows():
+		return fmt.Sprintf("%d×%d at %d,%d", c.Width, c.Height, c.ScrollX, c.ScrollY)
+	}
+	return fmt.Sprintf("%d×%d", c.Width, c.Height)
+}
@@ -28,28 +28,39 @@ func GetBorderStyle(style string) lipgloss.Border {
 	}
 }
 
+// sized makes style fill size with its border and margin included, so
+// that a component takes up the cells its schema size says. A zero
+// width or height is left to the content.
+func sized(style lipgloss.Style, size schema.Size) lipgloss.Style {
+	if size.Width > 0 {
+		style = style.Width(max(size.Width-style.GetHorizontalBorderSize()-style.GetHorizontalMargins(), 0))
+	}
+	if size.Height > 0 {
+		style = style.Height(max(size.Height-style.GetVerticalBorderSize()-style.GetVerticalMargins(), 0))
+	}
+	return style
+}
+
 // RenderBox renders a box component
 func RenderBox(c schema.Component, theme styles.Theme) string {
 	style := lipgloss.NewStyle().
-		Width(c.Size.Width).
-		Height(c.Size.Height).
 		Padding(c.Style.Padding.Top, c.Style.Padding.Right, c.Style.Padding.Bottom, c.Style.Padding.Left).
 		Margin(c.Style.Margin.Top, c.Style.Margin.Right, c.Style.Margin.Bottom, c.Style.Margin.Left)
 
 	if c.Style.Foreground != "" {
-		style = style.Foreground(lipgloss.Color(c.Style.Foreground))
+		style = style.Foreground(theme.Resolve(c.Style.Foreground))
 	} else {
 		style = style.Foreground(theme.TextPrimary)
 	}
 
 	if c.Style.Background != "" {
-		style = style.Background(lipgloss.Color(c.Style.Background))
+		style = style.Background(theme.Resolve(c.Style.Background))
 	}
 
 	if c.Style.Border != nil {
 		style = style.BorderStyle(GetBorderStyle(c.Style.Border.Style))
 		if c.Style.Border.Color != "" {
-			style = style.BorderForeground(lipgloss.Color(c.Style.Border.Color))
+			style = style.BorderForeground(theme.Resolve(c.Style.Border.Color))
 		} else if c.Selected {
 			style = style.BorderForeground(theme.Primary)
 		} else {
@@ -64,7 +75,7 @@ func RenderBox(c schema.Component, theme styles.Theme) string {
 		style = style.Italic(true)
 	}
 
-	return style.Render(c.Text)
+	return sized(style, c.Size).Render(c.Text)
 }
 
 // RenderText renders a text component
@@ -72,7 +83,7 @@ func RenderText(c schema.Component, theme styles.Theme) string {
 	style := lipgloss.NewStyle()
 
 	if c.Style.Foreground != "" {
-		style = style.Foreground(lipgloss.Color(c.Style.Foreground))
+		style = style.Foreground(theme.Resolve(c.Style.Foreground))
 	} else {
 		style = style.Foreground(theme.TextPrimary)
 	}
@@ -128,7 +139,7 @@ func RenderButton(c schema.Component, theme styles.Theme) string {
 		style = style.Foreground(theme.TextMuted)
 	}
 
-	return style.Render(c.Text)
+	return sized(style.Align(lipgloss.Center), c.Size).Render(c.Text)
 }
 
 // RenderProgress renders a progress bar
@@ -163,3 +174,11 @@ func RenderProgress(c schema.Component, theme styles.Theme) string {
 	return "[" + style.Render(progress) + "]"
 }
 
+// RenderSpinner renders a spinner at its first frame, followed by its text
+func RenderSpinner(c schema.Component, theme styles.Theme) string {
+	spinner := lipgloss.NewStyle().Foreground(theme.Primary).Render("⠋")
+	if c.Text == "" {
+		return spinner
+	}
+	return spinner + " " + RenderText(schema.Component{Text: c.Text, Style: c.Style}, theme)
+}
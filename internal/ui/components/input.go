@@ -0,0 +1,49 @@
+// Package components - Text input component implementation
+package components
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// RenderInput renders a text input holding value. When the input is
+// focused the cursor is shown at rune position cursor; when it is empty
+// the placeholder is shown instead.
+func RenderInput(c schema.Component, theme styles.Theme, value string, cursor int) string {
+	textStyle := lipgloss.NewStyle().Foreground(theme.TextPrimary)
+	cursorStyle := lipgloss.NewStyle().Foreground(theme.Background).Background(theme.Accent)
+
+	runes := []rune(value)
+	cursor = min(max(cursor, 0), len(runes))
+	var content string
+	switch {
+	case value == "" && c.Focused:
+		content = cursorStyle.Render(" ") + lipgloss.NewStyle().Foreground(theme.TextMuted).Render(c.Placeholder)
+	case value == "":
+		content = lipgloss.NewStyle().Foreground(theme.TextMuted).Render(c.Placeholder)
+	case c.Focused:
+		under := " "
+		rest := ""
+		if cursor < len(runes) {
+			under, rest = string(runes[cursor]), string(runes[cursor+1:])
+		}
+		content = textStyle.Render(string(runes[:cursor])) + cursorStyle.Render(under) + textStyle.Render(rest)
+	default:
+		content = textStyle.Render(value)
+	}
+
+	style := lipgloss.NewStyle()
+	if c.Style.Border != nil {
+		style = style.BorderStyle(GetBorderStyle(c.Style.Border.Style))
+		switch {
+		case c.Style.Border.Color != "":
+			style = style.BorderForeground(theme.Resolve(c.Style.Border.Color))
+		case c.Focused:
+			style = style.BorderForeground(theme.Primary)
+		default:
+			style = style.BorderForeground(theme.Border)
+		}
+	}
+	return sized(style, c.Size).Render(content)
+}
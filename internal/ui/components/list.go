@@ -46,14 +46,12 @@ func RenderList(c schema.Component, theme styles.Theme, selectedIndex int) strin
 	content := strings.Join(lines, "\n")
 
 	// Apply container styling
-	containerStyle := lipgloss.NewStyle().
-		Width(c.Size.Width).
-		Height(c.Size.Height)
+	containerStyle := lipgloss.NewStyle()
 
 	if c.Style.Border != nil {
 		containerStyle = containerStyle.
 			BorderStyle(GetBorderStyle(c.Style.Border.Style)).
-			BorderForeground(lipgloss.Color(c.Style.Border.Color))
+			BorderForeground(theme.Resolve(c.Style.Border.Color))
 
 		if c.Style.Border.Color == "" {
 			if c.Focused {
@@ -64,7 +62,7 @@ func RenderList(c schema.Component, theme styles.Theme, selectedIndex int) strin
 		}
 	}
 
-	return containerStyle.Render(content)
+	return sized(containerStyle, c.Size).Render(content)
 }
 
 // RenderTabs renders a tab component
@@ -136,6 +134,6 @@ func RenderTable(c schema.Component, theme styles.Theme) string {
 			BorderForeground(theme.Border)
 	}
 
-	return containerStyle.Render(content)
+	return sized(containerStyle, c.Size).Render(content)
 }
 
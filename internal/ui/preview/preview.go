@@ -0,0 +1,319 @@
+// Package preview runs a design as if it were the app built from it
+package preview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/render"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Preview is a running design: inputs take typing, lists scroll, tabs
+// switch and buttons press, with tab moving the focus between them. It
+// works on its own copy of the components, so the design never changes.
+type Preview struct {
+	Width   int
+	Height  int
+	Theme   styles.Theme
+	Message string // what the last key did, such as pressing a button
+
+	comps     []schema.Component // visible components in drawing order, in canvas coordinates
+	focusable []int              // indices into comps, in tab order
+	focus     int                // index into focusable, -1 for none
+
+	values  map[string][]rune // input text
+	cursors map[string]int    // input cursor
+	active  map[string]int    // selected list item or active tab
+	offsets map[string]int    // first list item shown
+}
+
+// New starts a preview of comps
+func New(comps []schema.Component, width, height int, theme styles.Theme) *Preview {
+	p := &Preview{
+		Width:   width,
+		Height:  height,
+		Theme:   theme,
+		focus:   -1,
+		values:  map[string][]rune{},
+		cursors: map[string]int{},
+		active:  map[string]int{},
+		offsets: map[string]int{},
+	}
+	p.flatten(comps, 0, 0)
+
+	for i, comp := range p.comps {
+		if takesFocus(comp) {
+			p.focusable = append(p.focusable, i)
+		}
+		if comp.Type == schema.TypeInput {
+			if v, ok := comp.Value.(string); ok {
+				p.values[comp.ID] = []rune(v)
+				p.cursors[comp.ID] = len(p.values[comp.ID])
+			}
+		}
+	}
+
+	// Tab order is reading order: top to bottom, then left to right
+	sort.SliceStable(p.focusable, func(i, j int) bool {
+		a, b := p.comps[p.focusable[i]].Position, p.comps[p.focusable[j]].Position
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	if len(p.focusable) > 0 {
+		p.focus = 0
+	}
+	return p
+}
+
+// flatten adds the visible components, stacked, with each container's
+// children after it and placed in canvas coordinates
+func (p *Preview) flatten(comps []schema.Component, dx, dy int) {
+	for _, comp := range schema.Stack(comps) {
+		if comp.Hidden {
+			continue
+		}
+		comp = comp.Clone()
+		comp.Position.X += dx
+		comp.Position.Y += dy
+		comp.Selected = false
+		comp.Focused = false
+		p.comps = append(p.comps, comp)
+		p.flatten(comp.Children, comp.Position.X, comp.Position.Y)
+	}
+}
+
+// takesFocus reports whether a component can have the focus
+func takesFocus(comp schema.Component) bool {
+	if comp.Disabled {
+		return false
+	}
+	switch comp.Type {
+	case schema.TypeInput, schema.TypeButton, schema.TypeList, schema.TypeTabs:
+		return true
+	}
+	return false
+}
+
+// Focused returns the focused component, or nil
+func (p *Preview) Focused() *schema.Component {
+	if p.focus < 0 {
+		return nil
+	}
+	return &p.comps[p.focusable[p.focus]]
+}
+
+// Value returns the text typed into an input
+func (p *Preview) Value(id string) string {
+	return string(p.values[id])
+}
+
+// Active returns the selected item of a list or the active tab
+func (p *Preview) Active(id string) int {
+	return p.active[id]
+}
+
+// Update handles a key
+func (p *Preview) Update(msg tea.KeyMsg) {
+	p.Message = ""
+	switch msg.String() {
+	case "tab":
+		p.moveFocus(1)
+		return
+	case "shift+tab":
+		p.moveFocus(-1)
+		return
+	}
+
+	comp := p.Focused()
+	if comp == nil {
+		return
+	}
+	switch comp.Type {
+	case schema.TypeInput:
+		p.updateInput(comp, msg)
+	case schema.TypeList:
+		p.updateList(comp, msg)
+	case schema.TypeTabs:
+		p.updateTabs(comp, msg)
+	case schema.TypeButton:
+		if s := msg.String(); s == "enter" || s == " " {
+			p.Message = "Pressed " + label(comp)
+		}
+	}
+}
+
+// moveFocus moves the focus to the next or the previous component
+func (p *Preview) moveFocus(dir int) {
+	if len(p.focusable) == 0 {
+		return
+	}
+	p.focus = (p.focus + dir + len(p.focusable)) % len(p.focusable)
+}
+
+// Click focuses the topmost focusable component at x, y, as given in
+// canvas coordinates
+func (p *Preview) Click(x, y int) {
+	for i := len(p.comps) - 1; i >= 0; i-- {
+		comp := p.comps[i]
+		w, h := lipgloss.Size(p.render(comp))
+		if x < comp.Position.X || y < comp.Position.Y || x >= comp.Position.X+w || y >= comp.Position.Y+h {
+			continue
+		}
+		for k, j := range p.focusable {
+			if j == i {
+				p.focus = k
+				if comp.Type == schema.TypeButton {
+					p.Message = "Pressed " + label(&comp)
+				}
+			}
+		}
+		return
+	}
+}
+
+func (p *Preview) updateInput(comp *schema.Component, msg tea.KeyMsg) {
+	value, cursor := p.values[comp.ID], p.cursors[comp.ID]
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		typed := msg.Runes
+		if msg.Type == tea.KeySpace {
+			typed = []rune{' '}
+		}
+		value = append(value[:cursor:cursor], append(typed, value[cursor:]...)...)
+		cursor += len(typed)
+	case tea.KeyBackspace:
+		if cursor > 0 {
+			value = append(value[:cursor-1:cursor-1], value[cursor:]...)
+			cursor--
+		}
+	case tea.KeyDelete:
+		if cursor < len(value) {
+			value = append(value[:cursor:cursor], value[cursor+1:]...)
+		}
+	case tea.KeyLeft:
+		cursor = max(cursor-1, 0)
+	case tea.KeyRight:
+		cursor = min(cursor+1, len(value))
+	case tea.KeyHome, tea.KeyCtrlA:
+		cursor = 0
+	case tea.KeyEnd, tea.KeyCtrlE:
+		cursor = len(value)
+	case tea.KeyEnter:
+		p.Message = fmt.Sprintf("%s: %q", label(comp), string(value))
+	}
+	p.values[comp.ID], p.cursors[comp.ID] = value, cursor
+}
+
+func (p *Preview) updateList(comp *schema.Component, msg tea.KeyMsg) {
+	n := len(comp.Items)
+	if n == 0 {
+		return
+	}
+	selected := p.active[comp.ID]
+	page := max(render.ListRows(*comp), 1)
+	switch msg.String() {
+	case "up", "k":
+		selected--
+	case "down", "j":
+		selected++
+	case "pgup":
+		selected -= page
+	case "pgdown":
+		selected += page
+	case "home", "g":
+		selected = 0
+	case "end", "G":
+		selected = n - 1
+	case "enter":
+		p.Message = fmt.Sprintf("%s: %s", label(comp), comp.Items[selected])
+	}
+	selected = min(max(selected, 0), n-1)
+	p.active[comp.ID] = selected
+
+	// Scroll to keep the selected item in view
+	offset := p.offsets[comp.ID]
+	offset = min(offset, selected)
+	offset = max(offset, selected-page+1)
+	p.offsets[comp.ID] = offset
+}
+
+func (p *Preview) updateTabs(comp *schema.Component, msg tea.KeyMsg) {
+	n := len(comp.Items)
+	if n == 0 {
+		return
+	}
+	active := p.active[comp.ID]
+	switch msg.String() {
+	case "left", "h":
+		active = (active + n - 1) % n
+	case "right", "l":
+		active = (active + 1) % n
+	default:
+		return
+	}
+	p.active[comp.ID] = active
+	p.Message = fmt.Sprintf("%s: %s", label(comp), comp.Items[active])
+}
+
+// label names a component in messages
+func label(comp *schema.Component) string {
+	if comp.Type == schema.TypeButton && comp.Text != "" {
+		return comp.Text
+	}
+	return comp.Name
+}
+
+// View renders the running design
+func (p *Preview) View() string {
+	return p.buffer().String()
+}
+
+// buffer draws the running design
+func (p *Preview) buffer() *compositor.Buffer {
+	buf := compositor.NewBuffer(p.Width, p.Height)
+	for _, comp := range p.comps {
+		buf.Draw(comp.Position.X, comp.Position.Y, p.render(comp))
+	}
+	return buf
+}
+
+// render draws a component in its current state
+func (p *Preview) render(comp schema.Component) string {
+	if comp.Type == schema.TypeInput {
+		comp.Value = string(p.values[comp.ID])
+	}
+	focused := p.Focused()
+	return render.Component(comp, p.Theme, render.State{
+		Focused: focused != nil && comp.ID == focused.ID,
+		Cursor:  p.cursors[comp.ID],
+		Active:  p.active[comp.ID],
+		Offset:  p.offsets[comp.ID],
+	})
+}
+
+// Hint describes the keys of the focused component
+func (p *Preview) Hint() string {
+	hint := "tab next"
+	if comp := p.Focused(); comp != nil {
+		switch comp.Type {
+		case schema.TypeInput:
+			hint = "type to edit · " + hint
+		case schema.TypeList:
+			hint = "↑↓ scroll · " + hint
+		case schema.TypeTabs:
+			hint = "←→ switch · " + hint
+		case schema.TypeButton:
+			hint = "enter press · " + hint
+		}
+		hint = label(comp) + " · " + hint
+	}
+	return strings.TrimSpace(hint) + " · esc edit"
+}
@@ -0,0 +1,71 @@
+package preview
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/render"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestFocusCycle(t *testing.T) {
+	p := New([]schema.Component{
+		{ID: "ok", Type: schema.TypeButton, Text: "OK", Position: schema.Position{X: 20, Y: 6}, Size: schema.Size{Width: 8, Height: 3}},
+		{ID: "name", Type: schema.TypeInput, Position: schema.Position{X: 2, Y: 2}, Size: schema.Size{Width: 20, Height: 3}},
+		{ID: "off", Type: schema.TypeButton, Text: "Off", Disabled: true, Position: schema.Position{X: 2, Y: 6}, Size: schema.Size{Width: 8, Height: 3}},
+		{ID: "gone", Type: schema.TypeInput, Hidden: true, Position: schema.Position{X: 2, Y: 4}, Size: schema.Size{Width: 20, Height: 3}},
+		{ID: "list", Type: schema.TypeList, Items: []string{"a", "b"}, Position: schema.Position{X: 30, Y: 2}, Size: schema.Size{Width: 10, Height: 2}},
+	}, 80, 24, styles.Ultraviolet)
+
+	// Reading order, skipping what is disabled or hidden, and round again
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	for _, want := range []string{"name", "list", "ok", "name"} {
+		if got := p.Focused(); got == nil || got.ID != want {
+			t.Fatalf("focused %v, want %s", got, want)
+		}
+		p.Update(tab)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	p.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if got := p.Focused(); got.ID != "ok" {
+		t.Errorf("shift+tab twice from list focused %s, want ok", got.ID)
+	}
+
+	// Keys go to the focused component only
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.Message != "Pressed OK" {
+		t.Errorf("enter on OK: message %q", p.Message)
+	}
+	p.Update(tab)
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi")})
+	if got := p.Value("name"); got != "hi" {
+		t.Errorf("typed %q into name, want hi", got)
+	}
+}
+
+func TestNoFocus(t *testing.T) {
+	p := New([]schema.Component{
+		{ID: "box", Type: schema.TypeBox, Size: schema.Size{Width: 10, Height: 4}},
+	}, 80, 24, styles.Ultraviolet)
+	p.Update(tea.KeyMsg{Type: tea.KeyTab})
+	p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.Focused() != nil {
+		t.Error("focused a box")
+	}
+}
+
+func TestDrawnAsRendered(t *testing.T) {
+	// Nothing here takes the focus, so the running design looks as it
+	// does when its app starts
+	design := &schema.Canvas{Components: []schema.Component{
+		{ID: "panel", Type: schema.TypeBox, Text: "Panel", Size: schema.Size{Width: 30, Height: 8}, Style: schema.Style{Border: &schema.Border{Style: "rounded"}}},
+		{ID: "spin", Type: schema.TypeSpinner, Text: "Loading", Position: schema.Position{X: 2, Y: 1}, Size: schema.Size{Width: 12, Height: 1}},
+		{ID: "table", Type: schema.TypeTable, Items: []string{"Name", "Ada"}, Position: schema.Position{X: 2, Y: 2}, Size: schema.Size{Width: 16, Height: 4}},
+		{ID: "done", Type: schema.TypeProgress, Value: 0.5, Position: schema.Position{X: 2, Y: 6}, Size: schema.Size{Width: 20, Height: 1}},
+	}}
+	p := New(design.Components, 40, 10, styles.Ultraviolet)
+	if got, want := p.View(), render.Grid(design, schema.Size{Width: 40, Height: 10}, styles.Ultraviolet).String(); got != want {
+		t.Errorf("preview draws\n%s\nwant\n%s", got, want)
+	}
+}
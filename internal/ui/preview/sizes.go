@@ -0,0 +1,180 @@
+// Package preview - Responsive snapshots
+package preview
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// DefaultSizes are the terminal sizes a design is checked at
+var DefaultSizes = []schema.Size{
+	{Width: 80, Height: 24},
+	{Width: 120, Height: 40},
+	{Width: 60, Height: 20},
+}
+
+// ParseSizes parses a comma-separated list of sizes such as "80x24,120x40"
+func ParseSizes(s string) ([]schema.Size, error) {
+	var sizes []schema.Size
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		w, h, ok := strings.Cut(strings.ReplaceAll(field, "×", "x"), "x")
+		width, werr := strconv.Atoi(w)
+		height, herr := strconv.Atoi(h)
+		if !ok || werr != nil || herr != nil || width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("invalid size %q, want WIDTHxHEIGHT", field)
+		}
+		sizes = append(sizes, schema.Size{Width: width, Height: height})
+	}
+	return sizes, nil
+}
+
+// Snapshot is a design as it looks in a terminal of one size
+type Snapshot struct {
+	Size schema.Size
+
+	// View is the design in a frame the size of the terminal. Problem
+	// components are highlighted, and arrows on the frame mark where
+	// components are cut off.
+	View string
+
+	// Issues are the components clipped by the terminal, with text
+	// overflowing them, or overlapping each other
+	Issues []lint.Diagnostic
+}
+
+// Title names the size, with a count of the issues at it
+func (s Snapshot) Title() string {
+	title := fmt.Sprintf("%d×%d", s.Size.Width, s.Size.Height)
+	if errs := lint.Count(s.Issues, lint.SeverityError); errs > 0 {
+		title += fmt.Sprintf(" ✗ %d", errs)
+	}
+	if warns := lint.Count(s.Issues, lint.SeverityWarning); warns > 0 {
+		title += fmt.Sprintf(" ⚠ %d", warns)
+	}
+	return title
+}
+
+// Snapshots renders the design at each of sizes
+func Snapshots(design *schema.Canvas, sizes []schema.Size, theme styles.Theme) []Snapshot {
+	snaps := make([]Snapshot, len(sizes))
+	for i, size := range sizes {
+		snaps[i] = Render(design, size, theme)
+	}
+	return snaps
+}
+
+// sizeRules are the lint rules for what goes wrong at a terminal size
+var sizeRules = []string{lint.RuleOutOfBounds, lint.RuleTextOverflow, lint.RuleOverlap}
+
+// Render renders the design, as nothing has the focus yet, in a
+// terminal of the given size
+func Render(design *schema.Canvas, size schema.Size, theme styles.Theme) Snapshot {
+	sized := schema.Canvas{Width: size.Width, Height: size.Height, Components: visible(design.Components)}
+	cfg := lint.DefaultConfig()
+	for _, rule := range lint.BuiltinRules() {
+		if !slices.Contains(sizeRules, rule.Name) {
+			cfg.Rules[rule.Name] = lint.RuleConfig{Disabled: true}
+		}
+	}
+	snap := Snapshot{Size: size, Issues: lint.New(cfg).Run(&sized)}
+
+	p := New(sized.Components, size.Width, size.Height, theme)
+	p.focus = -1
+	buf := p.buffer()
+
+	framed := compositor.NewBuffer(size.Width+2, size.Height+2)
+	framed.Draw(0, 0, lipgloss.NewStyle().
+		Width(size.Width).
+		Height(size.Height).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(theme.Border).
+		Render(""))
+	framed.Draw(2, 0, lipgloss.NewStyle().Foreground(theme.TextSecondary).Render(" "+snap.Title()+" "))
+
+	for _, d := range snap.Issues {
+		comp := p.find(d.ComponentID)
+		if comp == nil {
+			continue
+		}
+		r := lint.Bounds(*comp)
+		color := theme.Warning
+		if d.Severity == lint.SeverityError {
+			color = theme.Error
+		}
+		buf.Tint(r.X, r.Y, r.W, r.H, sgr(lipgloss.NewStyle().Background(color)))
+		if d.Rule == lint.RuleOutOfBounds {
+			markClipped(framed, r, size, lipgloss.NewStyle().Foreground(theme.Error).Bold(true))
+		}
+	}
+
+	framed.DrawBuffer(1, 1, buf)
+	snap.View = framed.String()
+	return snap
+}
+
+// Still renders the design at its own size as it looks when it starts,
+// before anything has the focus
+func Still(design *schema.Canvas, theme styles.Theme) *compositor.Buffer {
+	p := New(visible(design.Components), design.Width, design.Height, theme)
+	p.focus = -1
+	return p.buffer()
+}
+
+// markClipped puts arrows on the frame beside each row and column of r
+// cut off by the edges of the terminal
+func markClipped(framed *compositor.Buffer, r lint.Rect, size schema.Size, style lipgloss.Style) {
+	for y := max(r.Y, 0); y < min(r.Bottom(), size.Height); y++ {
+		if r.X < 0 {
+			framed.Draw(0, y+1, style.Render("◂"))
+		}
+		if r.Right() > size.Width {
+			framed.Draw(size.Width+1, y+1, style.Render("▸"))
+		}
+	}
+	for x := max(r.X, 0); x < min(r.Right(), size.Width); x++ {
+		if r.Y < 0 {
+			framed.Draw(x+1, 0, style.Render("▴"))
+		}
+		if r.Bottom() > size.Height {
+			framed.Draw(x+1, size.Height+1, style.Render("▾"))
+		}
+	}
+}
+
+// sgr returns the escape sequence that turns on style, or "" when the
+// terminal has no colors
+func sgr(style lipgloss.Style) string {
+	return compositor.Parse(style.Render(" ")).Cell(0, 0).Style
+}
+
+// visible returns comps without the hidden components
+func visible(comps []schema.Component) []schema.Component {
+	var shown []schema.Component
+	for _, comp := range comps {
+		if comp.Hidden {
+			continue
+		}
+		comp.Children = visible(comp.Children)
+		shown = append(shown, comp)
+	}
+	return shown
+}
+
+// find returns the component with the given ID, in canvas coordinates
+func (p *Preview) find(id string) *schema.Component {
+	for i := range p.comps {
+		if p.comps[i].ID == id {
+			return &p.comps[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package preview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestRenderFlagsClipped(t *testing.T) {
+	design := &schema.Canvas{Components: []schema.Component{
+		{ID: "a", Type: schema.TypeBox, Name: "Wide", Size: schema.Size{Width: 70, Height: 2}},
+		{ID: "b", Type: schema.TypeBox, Name: "Gone", Size: schema.Size{Width: 90, Height: 2}, Hidden: true},
+	}}
+	sizes, err := ParseSizes("80x24, 60×20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snaps := Snapshots(design, sizes, styles.Ultraviolet)
+	if len(snaps[0].Issues) != 0 {
+		t.Errorf("80x24: %v", snaps[0].Issues)
+	}
+	issues := snaps[1].Issues
+	if len(issues) != 1 || issues[0].Rule != lint.RuleOutOfBounds || issues[0].ComponentID != "a" {
+		t.Fatalf("60x20: %v", issues)
+	}
+	if lines := strings.Split(snaps[1].View, "\n"); len(lines) != 22 || !strings.HasSuffix(lines[1], "▸") {
+		t.Errorf("60x20 view:\n%s", snaps[1].View)
+	}
+
+	if _, err := ParseSizes("80"); err == nil {
+		t.Error("want an error for a size without a height")
+	}
+}
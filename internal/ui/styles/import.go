@@ -0,0 +1,290 @@
+// Package styles - Terminal color scheme importers
+package styles
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// TerminalScheme is a terminal color scheme: a foreground, a background
+// and the 16 ANSI colors (black, red, green, yellow, blue, magenta, cyan,
+// white, then their bright variants)
+type TerminalScheme struct {
+	Name       string
+	Foreground string
+	Background string
+	ANSI       [16]string
+}
+
+// ANSI color indexes used when mapping a scheme to a theme
+const (
+	ansiRed         = 1
+	ansiGreen       = 2
+	ansiYellow      = 3
+	ansiBlue        = 4
+	ansiMagenta     = 5
+	ansiCyan        = 6
+	ansiBrightBlack = 8
+)
+
+// FromScheme maps a terminal color scheme onto theme slots. Surfaces are
+// the background blended toward the foreground.
+func FromScheme(s TerminalScheme) (Theme, error) {
+	var colors [16]lipgloss.Color
+	for i, value := range s.ANSI {
+		c, err := normalizeColor(value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("ansi %d: %w", i, err)
+		}
+		colors[i] = c
+	}
+	fg, err := normalizeColor(s.Foreground)
+	if err != nil {
+		return Theme{}, fmt.Errorf("foreground: %w", err)
+	}
+	bg, err := normalizeColor(s.Background)
+	if err != nil {
+		return Theme{}, fmt.Errorf("background: %w", err)
+	}
+
+	return Theme{
+		Name:          s.Name,
+		Primary:       colors[ansiBlue],
+		Secondary:     colors[ansiMagenta],
+		Accent:        colors[ansiCyan],
+		Background:    bg,
+		Surface:       blend(bg, fg, 0.06),
+		SurfaceLight:  blend(bg, fg, 0.12),
+		TextPrimary:   fg,
+		TextSecondary: blend(fg, bg, 0.25),
+		TextMuted:     colors[ansiBrightBlack],
+		Success:       colors[ansiGreen],
+		Warning:       colors[ansiYellow],
+		Error:         colors[ansiRed],
+		Info:          colors[ansiCyan],
+		Border:        colors[ansiBrightBlack],
+		BorderActive:  colors[ansiBlue],
+	}, nil
+}
+
+// blend mixes a toward b by t in Lab space
+func blend(a, b lipgloss.Color, t float64) lipgloss.Color {
+	ca, err := colorful.Hex(string(a))
+	if err != nil {
+		return a
+	}
+	cb, err := colorful.Hex(string(b))
+	if err != nil {
+		return a
+	}
+	return lipgloss.Color(strings.ToUpper(ca.BlendLab(cb, t).Clamped().Hex()))
+}
+
+// FromBase16 maps a base16 scheme (base00..base0F) onto theme slots
+func FromBase16(m map[string]string, fallbackName string) (Theme, error) {
+	get := func(key string) (lipgloss.Color, error) {
+		value, ok := m[key]
+		if !ok {
+			value, ok = m[strings.ToLower(key)]
+		}
+		if !ok {
+			return "", fmt.Errorf("missing %s", key)
+		}
+		c, err := normalizeColor(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+		return c, nil
+	}
+
+	keys := []string{
+		"base00", "base01", "base02", "base03", "base04", "base05",
+		"base08", "base09", "base0A", "base0B", "base0C", "base0D", "base0E",
+	}
+	c := map[string]lipgloss.Color{}
+	for _, key := range keys {
+		color, err := get(key)
+		if err != nil {
+			return Theme{}, err
+		}
+		c[key] = color
+	}
+
+	name := fallbackName
+	if n := m["scheme"]; n != "" {
+		name = n
+	} else if n := m["name"]; n != "" {
+		name = n
+	}
+
+	return Theme{
+		Name:          name,
+		Primary:       c["base0D"],
+		Secondary:     c["base0E"],
+		Accent:        c["base09"],
+		Background:    c["base00"],
+		Surface:       c["base01"],
+		SurfaceLight:  c["base02"],
+		TextPrimary:   c["base05"],
+		TextSecondary: c["base04"],
+		TextMuted:     c["base03"],
+		Success:       c["base0B"],
+		Warning:       c["base0A"],
+		Error:         c["base08"],
+		Info:          c["base0C"],
+		Border:        c["base03"],
+		BorderActive:  c["base0D"],
+	}, nil
+}
+
+// windowsTerminalKeys are the Windows Terminal scheme keys in ANSI order
+var windowsTerminalKeys = [16]string{
+	"black", "red", "green", "yellow", "blue", "purple", "cyan", "white",
+	"brightBlack", "brightRed", "brightGreen", "brightYellow",
+	"brightBlue", "brightPurple", "brightCyan", "brightWhite",
+}
+
+// windowsTerminalThemes converts Windows Terminal "schemes" entries
+func windowsTerminalThemes(schemes []map[string]any, fallbackName string) ([]Theme, error) {
+	if len(schemes) == 0 {
+		return nil, fmt.Errorf("no color schemes found")
+	}
+	str := func(m map[string]any, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	var result []Theme
+	for i, m := range schemes {
+		s := TerminalScheme{
+			Name:       str(m, "name"),
+			Foreground: str(m, "foreground"),
+			Background: str(m, "background"),
+		}
+		for j, key := range windowsTerminalKeys {
+			s.ANSI[j] = str(m, key)
+		}
+		if s.Name == "" {
+			s.Name = fallbackName
+			if len(schemes) > 1 {
+				s.Name = fmt.Sprintf("%s-%d", fallbackName, i+1)
+			}
+		}
+		t, err := FromScheme(s)
+		if err != nil {
+			return nil, fmt.Errorf("scheme %q: %w", s.Name, err)
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// plist is the subset of an Apple property list used by .itermcolors
+type plist struct {
+	Dict plistDict `xml:"dict"`
+}
+
+// plistDict keeps keys and values in document order; iTerm2 presets
+// alternate <key> with either a nested <dict> or a <real>/<string>
+type plistDict struct {
+	Entries []plistEntry
+}
+
+type plistEntry struct {
+	Key   string
+	Dict  *plistDict
+	Value string
+}
+
+func (d *plistDict) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var key string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "key":
+				if err := dec.DecodeElement(&key, &t); err != nil {
+					return err
+				}
+			case "dict":
+				var child plistDict
+				if err := dec.DecodeElement(&child, &t); err != nil {
+					return err
+				}
+				d.Entries = append(d.Entries, plistEntry{Key: key, Dict: &child})
+			default:
+				var value string
+				if err := dec.DecodeElement(&value, &t); err != nil {
+					return err
+				}
+				d.Entries = append(d.Entries, plistEntry{Key: key, Value: value})
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func (d *plistDict) get(key string) (plistEntry, bool) {
+	for _, e := range d.Entries {
+		if e.Key == key {
+			return e, true
+		}
+	}
+	return plistEntry{}, false
+}
+
+// parseITerm reads an iTerm2 .itermcolors preset
+func parseITerm(data []byte, name string) (Theme, error) {
+	var p plist
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	if err := dec.Decode(&p); err != nil {
+		return Theme{}, err
+	}
+
+	color := func(key string) (string, error) {
+		e, ok := p.Dict.get(key)
+		if !ok || e.Dict == nil {
+			return "", fmt.Errorf("missing %q", key)
+		}
+		var rgb [3]float64
+		for i, comp := range []string{"Red Component", "Green Component", "Blue Component"} {
+			v, ok := e.Dict.get(comp)
+			if !ok {
+				return "", fmt.Errorf("%s: missing %q", key, comp)
+			}
+			f, err := strconv.ParseFloat(strings.TrimSpace(v.Value), 64)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", key, err)
+			}
+			rgb[i] = f
+		}
+		return colorful.Color{R: rgb[0], G: rgb[1], B: rgb[2]}.Clamped().Hex(), nil
+	}
+
+	s := TerminalScheme{Name: name}
+	var err error
+	if s.Foreground, err = color("Foreground Color"); err != nil {
+		return Theme{}, err
+	}
+	if s.Background, err = color("Background Color"); err != nil {
+		return Theme{}, err
+	}
+	for i := range s.ANSI {
+		if s.ANSI[i], err = color(fmt.Sprintf("Ansi %d Color", i)); err != nil {
+			return Theme{}, err
+		}
+	}
+	return FromScheme(s)
+}
@@ -0,0 +1,134 @@
+// Package styles - Theme registry
+package styles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/makeatui/makeatui/internal/config"
+)
+
+var (
+	registry   = map[string]Theme{}
+	registryMu sync.RWMutex
+	builtins   = []string{"ultraviolet", "neon"}
+)
+
+func init() {
+	Register(Ultraviolet)
+	Register(Neon)
+}
+
+func registryKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Register adds a theme, replacing any theme with the same name
+func Register(t Theme) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[registryKey(t.Name)] = t
+}
+
+// Get returns the registered theme with the given name (case-insensitive)
+func Get(name string) (Theme, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	t, ok := registry[registryKey(name)]
+	return t, ok
+}
+
+// Lookup returns the registered theme with the given name,
+// falling back to Ultraviolet for unknown or empty names
+func Lookup(name string) Theme {
+	if t, ok := Get(name); ok {
+		return t
+	}
+	return Ultraviolet
+}
+
+// Names returns the registered theme names, built-ins first
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var result []string
+	for _, key := range builtins {
+		if t, ok := registry[key]; ok {
+			result = append(result, t.Name)
+		}
+	}
+	var others []string
+	for key, t := range registry {
+		if !isBuiltin(key) {
+			others = append(others, t.Name)
+		}
+	}
+	sort.Strings(others)
+	return append(result, others...)
+}
+
+// All returns the registered themes in Names order
+func All() []Theme {
+	names := Names()
+	result := make([]Theme, len(names))
+	for i, name := range names {
+		result[i] = Lookup(name)
+	}
+	return result
+}
+
+func isBuiltin(key string) bool {
+	for _, b := range builtins {
+		if b == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ThemeDir returns the directory user themes are loaded from
+func ThemeDir() string {
+	return config.Path("themes")
+}
+
+// LoadUserThemes registers every theme file in ThemeDir. A missing
+// directory is not an error; files that fail to load are reported
+// together after the rest have been registered.
+func LoadUserThemes() error {
+	return LoadDir(ThemeDir())
+}
+
+// LoadDir registers every theme file in dir
+func LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, e := range entries {
+		if e.IsDir() || !IsThemeFile(e.Name()) {
+			continue
+		}
+		themes, err := LoadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		for _, t := range themes {
+			Register(t)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("loading themes: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
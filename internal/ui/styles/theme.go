@@ -7,30 +7,30 @@ import (
 
 // Theme defines the color palette and styles for the TUI
 type Theme struct {
-	Name string
+	Name string `json:"name"`
 
 	// Primary colors - Ultraviolet palette
-	Primary      lipgloss.Color
-	Secondary    lipgloss.Color
-	Accent       lipgloss.Color
-	Background   lipgloss.Color
-	Surface      lipgloss.Color
-	SurfaceLight lipgloss.Color
+	Primary      lipgloss.Color `json:"primary"`
+	Secondary    lipgloss.Color `json:"secondary"`
+	Accent       lipgloss.Color `json:"accent"`
+	Background   lipgloss.Color `json:"background"`
+	Surface      lipgloss.Color `json:"surface"`
+	SurfaceLight lipgloss.Color `json:"surface_light"`
 
 	// Text colors
-	TextPrimary   lipgloss.Color
-	TextSecondary lipgloss.Color
-	TextMuted     lipgloss.Color
+	TextPrimary   lipgloss.Color `json:"text_primary"`
+	TextSecondary lipgloss.Color `json:"text_secondary"`
+	TextMuted     lipgloss.Color `json:"text_muted"`
 
 	// Semantic colors
-	Success lipgloss.Color
-	Warning lipgloss.Color
-	Error   lipgloss.Color
-	Info    lipgloss.Color
+	Success lipgloss.Color `json:"success"`
+	Warning lipgloss.Color `json:"warning"`
+	Error   lipgloss.Color `json:"error"`
+	Info    lipgloss.Color `json:"info"`
 
 	// Border colors
-	Border       lipgloss.Color
-	BorderActive lipgloss.Color
+	Border       lipgloss.Color `json:"border"`
+	BorderActive lipgloss.Color `json:"border_active"`
 }
 
 // Ultraviolet is the default glamorous theme inspired by Charm
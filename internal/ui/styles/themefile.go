@@ -0,0 +1,256 @@
+// Package styles - Theme files
+package styles
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme file formats recognised by LoadFile
+var themeExts = map[string]bool{
+	".json":        true, // MakeaTUI theme or Windows Terminal scheme(s)
+	".toml":        true, // MakeaTUI theme
+	".yaml":        true, // base16 scheme or MakeaTUI theme
+	".yml":         true,
+	".itermcolors": true, // iTerm2 color preset
+}
+
+// IsThemeFile reports whether the file extension is a known theme format
+func IsThemeFile(name string) bool {
+	return themeExts[strings.ToLower(filepath.Ext(name))]
+}
+
+// fields maps theme file keys to the theme's color slots
+func (t *Theme) fields() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"primary":        &t.Primary,
+		"secondary":      &t.Secondary,
+		"accent":         &t.Accent,
+		"background":     &t.Background,
+		"surface":        &t.Surface,
+		"surface_light":  &t.SurfaceLight,
+		"text_primary":   &t.TextPrimary,
+		"text_secondary": &t.TextSecondary,
+		"text_muted":     &t.TextMuted,
+		"success":        &t.Success,
+		"warning":        &t.Warning,
+		"error":          &t.Error,
+		"info":           &t.Info,
+		"border":         &t.Border,
+		"border_active":  &t.BorderActive,
+	}
+}
+
+// LoadFile reads one or more themes from a file. The format is chosen
+// by extension and, for JSON and YAML, by content.
+func LoadFile(path string) ([]Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var themes []Theme
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		themes, err = parseJSONThemes(data, name)
+	case ".toml":
+		var t Theme
+		t, err = themeFromMap(parseFlat(data, '='), name)
+		themes = []Theme{t}
+	case ".yaml", ".yml":
+		m := parseFlat(data, ':')
+		var t Theme
+		if _, ok := m["base00"]; ok {
+			t, err = FromBase16(m, name)
+		} else {
+			t, err = themeFromMap(m, name)
+		}
+		themes = []Theme{t}
+	case ".itermcolors":
+		var t Theme
+		t, err = parseITerm(data, name)
+		themes = []Theme{t}
+	default:
+		err = fmt.Errorf("unknown theme format")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return themes, nil
+}
+
+// SaveFile writes a theme as JSON or TOML, chosen by extension
+func SaveFile(t Theme, path string) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var err error
+		if data, err = json.MarshalIndent(t, "", "  "); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	case ".toml":
+		data = marshalTOML(t)
+	default:
+		return fmt.Errorf("%s: themes can be saved as .json or .toml", path)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// SaveUserTheme writes a theme into ThemeDir as <name>.json and registers it
+func SaveUserTheme(t Theme) (string, error) {
+	if err := os.MkdirAll(ThemeDir(), 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(ThemeDir(), fileName(t.Name)+".json")
+	if err := SaveFile(t, path); err != nil {
+		return "", err
+	}
+	Register(t)
+	return path, nil
+}
+
+func fileName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '/' || r == '\\' {
+			return '-'
+		}
+		return r
+	}, name)
+}
+
+func marshalTOML(t Theme) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "name = %q\n\n", t.Name)
+	fields := t.fields()
+	for _, key := range fieldOrder {
+		fmt.Fprintf(&buf, "%s = %q\n", key, string(*fields[key]))
+	}
+	return buf.Bytes()
+}
+
+// fieldOrder is the order slots are written in theme files
+var fieldOrder = []string{
+	"primary", "secondary", "accent",
+	"background", "surface", "surface_light",
+	"text_primary", "text_secondary", "text_muted",
+	"success", "warning", "error", "info",
+	"border", "border_active",
+}
+
+// themeFromMap builds a theme from file keys. Slots that are missing
+// are taken from the theme named by "extends" (Ultraviolet by default).
+func themeFromMap(m map[string]string, fallbackName string) (Theme, error) {
+	t := Lookup(m["extends"])
+	t.Name = fallbackName
+	if name := m["name"]; name != "" {
+		t.Name = name
+	}
+
+	fields := t.fields()
+	for key, value := range m {
+		slot, ok := fields[strings.ReplaceAll(strings.ToLower(key), "-", "_")]
+		if !ok {
+			continue
+		}
+		color, err := normalizeColor(value)
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", key, err)
+		}
+		*slot = color
+	}
+	return t, nil
+}
+
+// normalizeColor accepts #RGB, #RRGGBB, bare RRGGBB and ANSI numbers
+func normalizeColor(value string) (lipgloss.Color, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 3 && len(hex) != 6 {
+		return "", fmt.Errorf("invalid color %q", value)
+	}
+	if _, err := strconv.ParseUint(hex, 16, 32); err != nil {
+		return "", fmt.Errorf("invalid color %q", value)
+	}
+	return lipgloss.Color("#" + strings.ToUpper(hex)), nil
+}
+
+// parseJSONThemes reads a MakeaTUI theme, a Windows Terminal scheme, an
+// array of schemes or a Windows Terminal settings file with "schemes"
+func parseJSONThemes(data []byte, name string) ([]Theme, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var schemes []map[string]any
+		if err := json.Unmarshal(data, &schemes); err != nil {
+			return nil, err
+		}
+		return windowsTerminalThemes(schemes, name)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	if raw, ok := m["schemes"].([]any); ok {
+		var schemes []map[string]any
+		for _, s := range raw {
+			if sm, ok := s.(map[string]any); ok {
+				schemes = append(schemes, sm)
+			}
+		}
+		return windowsTerminalThemes(schemes, name)
+	}
+	if _, ok := m["brightBlack"]; ok {
+		return windowsTerminalThemes([]map[string]any{m}, name)
+	}
+
+	flat := map[string]string{}
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			flat[k] = s
+		}
+	}
+	t, err := themeFromMap(flat, name)
+	if err != nil {
+		return nil, err
+	}
+	return []Theme{t}, nil
+}
+
+// parseFlat reads "key = value" (TOML) or "key: value" (YAML) lines,
+// ignoring comments, sections and nesting
+func parseFlat(data []byte, sep byte) map[string]string {
+	m := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '[' || line == "---" {
+			continue
+		}
+		i := strings.IndexByte(line, sep)
+		if i < 0 {
+			continue
+		}
+		key := strings.Trim(strings.TrimSpace(line[:i]), `"'`)
+		value := strings.TrimSpace(line[i+1:])
+		if j := strings.Index(value, " #"); j >= 0 {
+			value = strings.TrimSpace(value[:j])
+		}
+		m[key] = strings.Trim(value, `"'`)
+	}
+	return m
+}
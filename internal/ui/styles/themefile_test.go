@@ -0,0 +1,168 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	base16 := writeFile(t, dir, "tn.yaml", `scheme: "Tomorrow Night"
+base00: "1d1f21"
+base01: "282a2e"
+base02: "373b41"
+base03: "969896"
+base04: "b4b7b4"
+base05: "c5c8c6"
+base08: "cc6666"
+base09: "de935f"
+base0A: "f0c674"
+base0B: "b5bd68"
+base0C: "8abeb7"
+base0D: "81a2be"
+base0E: "b294bb"
+`)
+	themes, err := LoadFile(base16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := themes[0]; got.Name != "Tomorrow Night" || got.Background != "#1D1F21" || got.Primary != "#81A2BE" {
+		t.Errorf("base16 mapped to %+v", got)
+	}
+
+	wt := writeFile(t, dir, "wt.json", `{"name": "Campbell", "foreground": "#CCCCCC", "background": "#0C0C0C",
+		"black": "#0C0C0C", "red": "#C50F1F", "green": "#13A10E", "yellow": "#C19C00",
+		"blue": "#0037DA", "purple": "#881798", "cyan": "#3A96DD", "white": "#CCCCCC",
+		"brightBlack": "#767676", "brightRed": "#E74856", "brightGreen": "#16C60C", "brightYellow": "#F9F1A5",
+		"brightBlue": "#3B78FF", "brightPurple": "#B4009E", "brightCyan": "#61D6D6", "brightWhite": "#F2F2F2"}`)
+	themes, err = LoadFile(wt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := themes[0]; got.Error != "#C50F1F" || got.TextMuted != "#767676" || got.Surface == got.Background {
+		t.Errorf("Windows Terminal scheme mapped to %+v", got)
+	}
+
+	// Partial themes inherit missing slots
+	partial := writeFile(t, dir, "partial.toml", "name = \"Partial\"\nextends = \"neon\"\nprimary = \"#123\"\n")
+	themes, err = LoadFile(partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := themes[0]; got.Primary != "#123" || got.Error != Neon.Error {
+		t.Errorf("partial theme = %+v", got)
+	}
+
+	if _, err := LoadFile(writeFile(t, dir, "bad.toml", "primary = \"blue-ish\"\n")); err == nil {
+		t.Error("expected an error for an invalid color")
+	}
+}
+
+func TestSaveFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"uv.json", "uv.toml"} {
+		path := filepath.Join(dir, name)
+		if err := SaveFile(Ultraviolet, path); err != nil {
+			t.Fatal(err)
+		}
+		themes, err := LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if themes[0] != Ultraviolet {
+			t.Errorf("%s: round trip gave %+v", name, themes[0])
+		}
+	}
+}
+
+// itermColors returns an .itermcolors preset with ANSI color i set to
+// a grey of i/15, and foreground and background as given, each a red,
+// green and blue component from 0 to 1
+func itermColors(fg, bg [3]float64, skip string) string {
+	entry := func(key string, rgb [3]float64) string {
+		if key == skip {
+			return ""
+		}
+		return fmt.Sprintf(`	<key>%s</key>
+	<dict>
+		<key>Alpha Component</key>
+		<real>1</real>
+		<key>Blue Component</key>
+		<real>%g</real>
+		<key>Color Space</key>
+		<string>sRGB</string>
+		<key>Green Component</key>
+		<real>%g</real>
+		<key>Red Component</key>
+		<real>%g</real>
+	</dict>
+`, key, rgb[2], rgb[1], rgb[0])
+	}
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	for i := range 16 {
+		v := float64(i) / 15
+		b.WriteString(entry(fmt.Sprintf("Ansi %d Color", i), [3]float64{v, v, v}))
+	}
+	b.WriteString(entry("Foreground Color", fg))
+	b.WriteString(entry("Background Color", bg))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func TestLoadITermColors(t *testing.T) {
+	dir := t.TempDir()
+	preset := writeFile(t, dir, "Greys.itermcolors", itermColors([3]float64{1, 1, 1}, [3]float64{0, 0, 0.2}, ""))
+	themes, err := LoadFile(preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := themes[0]
+	for slot, c := range map[string][2]lipgloss.Color{
+		"name":       {lipgloss.Color(got.Name), "Greys"},
+		"primary":    {got.Primary, "#444444"},   // ANSI 4, blue
+		"secondary":  {got.Secondary, "#555555"}, // ANSI 5, magenta
+		"accent":     {got.Accent, "#666666"},    // ANSI 6, cyan
+		"error":      {got.Error, "#111111"},     // ANSI 1, red
+		"success":    {got.Success, "#222222"},   // ANSI 2, green
+		"warning":    {got.Warning, "#333333"},   // ANSI 3, yellow
+		"muted":      {got.TextMuted, "#888888"}, // ANSI 8, bright black
+		"text":       {got.TextPrimary, "#FFFFFF"},
+		"background": {got.Background, "#000033"},
+	} {
+		if c[0] != c[1] {
+			t.Errorf("%s = %s, want %s", slot, c[0], c[1])
+		}
+	}
+
+	for name, data := range map[string]string{
+		"truncated":    itermColors([3]float64{1, 1, 1}, [3]float64{}, "")[:300],
+		"no ansi 7":    itermColors([3]float64{1, 1, 1}, [3]float64{}, "Ansi 7 Color"),
+		"no bg":        itermColors([3]float64{1, 1, 1}, [3]float64{}, "Background Color"),
+		"not a number": strings.Replace(itermColors([3]float64{1, 1, 1}, [3]float64{}, ""), "<real>0</real>", "<real>none</real>", 1),
+	} {
+		path := writeFile(t, dir, strings.ReplaceAll(name, " ", "-")+".itermcolors", data)
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package styles - Design tokens
+package styles
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TokenPrefix marks a style color as a reference to a theme slot,
+// e.g. "$primary" or "$text-muted"
+const TokenPrefix = "$"
+
+// Token names a theme color slot that styles can reference
+type Token struct {
+	Name  string
+	Color func(Theme) lipgloss.Color
+}
+
+// Tokens lists every design token in theme order
+var Tokens = []Token{
+	{"primary", func(t Theme) lipgloss.Color { return t.Primary }},
+	{"secondary", func(t Theme) lipgloss.Color { return t.Secondary }},
+	{"accent", func(t Theme) lipgloss.Color { return t.Accent }},
+	{"background", func(t Theme) lipgloss.Color { return t.Background }},
+	{"surface", func(t Theme) lipgloss.Color { return t.Surface }},
+	{"surface-light", func(t Theme) lipgloss.Color { return t.SurfaceLight }},
+	{"text-primary", func(t Theme) lipgloss.Color { return t.TextPrimary }},
+	{"text-secondary", func(t Theme) lipgloss.Color { return t.TextSecondary }},
+	{"text-muted", func(t Theme) lipgloss.Color { return t.TextMuted }},
+	{"success", func(t Theme) lipgloss.Color { return t.Success }},
+	{"warning", func(t Theme) lipgloss.Color { return t.Warning }},
+	{"error", func(t Theme) lipgloss.Color { return t.Error }},
+	{"info", func(t Theme) lipgloss.Color { return t.Info }},
+	{"border", func(t Theme) lipgloss.Color { return t.Border }},
+	{"border-active", func(t Theme) lipgloss.Color { return t.BorderActive }},
+}
+
+// tokenAliases maps shorthand spellings to canonical token names
+var tokenAliases = map[string]string{
+	"bg":   "background",
+	"text": "text-primary",
+	"fg":   "text-primary",
+}
+
+// IsToken reports whether s is written as a token reference
+func IsToken(s string) bool {
+	return strings.HasPrefix(s, TokenPrefix)
+}
+
+// LookupToken finds a token by name, with or without the "$" prefix.
+// Matching ignores case, dashes and underscores.
+func LookupToken(name string) (Token, bool) {
+	key := normalizeToken(name)
+	if alias, ok := tokenAliases[key]; ok {
+		key = normalizeToken(alias)
+	}
+	for _, tok := range Tokens {
+		if normalizeToken(tok.Name) == key {
+			return tok, true
+		}
+	}
+	return Token{}, false
+}
+
+func normalizeToken(name string) string {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), TokenPrefix)
+	return strings.NewReplacer("-", "", "_", "").Replace(name)
+}
+
+// Resolve turns a style color into a concrete color. Token references
+// resolve against the theme (unknown tokens resolve to no color); any
+// other value is passed through unchanged.
+func (t Theme) Resolve(color string) lipgloss.Color {
+	if !IsToken(color) {
+		return lipgloss.Color(color)
+	}
+	tok, ok := LookupToken(color)
+	if !ok {
+		return lipgloss.Color("")
+	}
+	return tok.Color(t)
+}
@@ -8,11 +8,20 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/makeatui/makeatui/internal/app"
+	"github.com/makeatui/makeatui/internal/ui/styles"
 )
 
 const version = "0.1.0"
 
 func main() {
+	if err := styles.LoadUserThemes(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	// makeatui keys reports mistakes in the keys file itself
+	if err := app.LoadKeyMap(); err != nil && !(len(os.Args) > 1 && os.Args[1] == "keys") {
+		fmt.Fprintf(os.Stderr, "Warning: %v (using the default keys)\n", err)
+	}
+
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "version", "-v", "--version":
@@ -21,7 +30,30 @@ func main() {
 		case "help", "-h", "--help":
 			printHelp()
 			os.Exit(0)
+		case "lint":
+			os.Exit(runLint(os.Args[2:]))
+		case "contrast":
+			os.Exit(runContrast(os.Args[2:]))
+		case "preview":
+			os.Exit(runPreview(os.Args[2:]))
+		case "render":
+			os.Exit(runRender(os.Args[2:]))
+		case "theme":
+			os.Exit(runTheme(os.Args[2:]))
+		case "keys":
+			os.Exit(runKeys(os.Args[2:]))
+		}
+	}
+
+	// Anything else names a design to open
+	model := app.New()
+	if len(os.Args) > 1 {
+		m, err := app.Open(os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		model = m
 	}
 
 	// Print glamorous banner
@@ -29,7 +61,7 @@ func main() {
 
 	// Start the TUI application
 	p := tea.NewProgram(
-		app.New(),
+		model,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -62,22 +94,28 @@ func printHelp() {
 	help := `
 USAGE:
     makeatui [command]
+    makeatui [file.json]
 
 COMMANDS:
     (none)       Start the interactive TUI designer
+    FILE.json    Open a design in the designer (created on first save)
+    lint FILE    Check a design (JSON) for layout problems
+    contrast     Check theme and design colors for WCAG contrast
+    preview FILE Render a design at several terminal sizes (--sizes 80x24,...)
+    render FILE  Render a design as ANSI, plain text or HTML (--format, --size)
+    theme        List, import and export themes
+    keys         Show and check the key bindings (--preset default|vim|emacs)
     version      Show version information  
     help         Show this help message
 
-KEYBOARD SHORTCUTS:
-    Tab          Switch focus (sidebar → canvas → properties)
-    ↑/k, ↓/j     Navigate up/down
-    ←/h, →/l     Navigate left/right
-    Enter/Space  Add selected component to canvas
-    d/Delete     Delete selected component
-    m            Toggle move mode
-    e            Export design to Go code
-    ?            Toggle help overlay
-    q/Ctrl+C     Quit
+KEYBOARD SHORTCUTS (see makeatui keys to change them):
+` + app.KeyHelp("    ") + `
+
+MOUSE:
+    Click to select, shift-click to add to the selection, drag empty
+    canvas to box select, drag to move, drag a ■ handle to resize,
+    right-click for a menu of actions.
+    Click a sidebar item, then drag on the canvas to draw that component
 
 DESCRIPTION:
     MakeaTUI is a visual TUI designer for AI agents.
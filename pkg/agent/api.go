@@ -3,6 +3,7 @@ package agent
 
 import (
 	"encoding/json"
+	"fmt"
 	
 	"github.com/makeatui/makeatui/pkg/schema"
 )
@@ -86,7 +87,7 @@ func (a *API) AddButton(name, label string, x, y int) string {
 
 // AddList adds a list component
 func (a *API) AddList(name string, items []string, x, y, width, height int) string {
-	comp := schema.NewComponent(schema.TypeList, name)
+	comp := a.session.Canvas.NewComponent(schema.TypeList, name)
 	comp.Position = schema.Position{X: x, Y: y}
 	comp.Size = schema.Size{Width: width, Height: height}
 	comp.Items = items
@@ -96,7 +97,7 @@ func (a *API) AddList(name string, items []string, x, y, width, height int) stri
 
 // AddProgress adds a progress bar
 func (a *API) AddProgress(name string, value float64, x, y, width int) string {
-	comp := schema.NewComponent(schema.TypeProgress, name)
+	comp := a.session.Canvas.NewComponent(schema.TypeProgress, name)
 	comp.Position = schema.Position{X: x, Y: y}
 	comp.Size = schema.Size{Width: width, Height: 1}
 	comp.Value = value
@@ -128,6 +129,15 @@ func (a *API) SetText(id, text string) error {
 	return a.session.Execute(cmd)
 }
 
+// SetStyle replaces the style of a component. Colors may be hex values,
+// ANSI numbers or theme tokens such as "$primary" or "$error".
+func (a *API) SetStyle(id string, style schema.Style) error {
+	params := StyleComponentParams{ID: id, Style: style}
+	data, _ := json.Marshal(params)
+	cmd := Command{Type: string(CmdStyleComponent), Params: data}
+	return a.session.Execute(cmd)
+}
+
 // Delete removes a component
 func (a *API) Delete(id string) error {
 	params := struct{ ID string `json:"id"` }{ID: id}
@@ -136,6 +146,72 @@ func (a *API) Delete(id string) error {
 	return a.session.Execute(cmd)
 }
 
+// Duplicate copies a component, offset by dx, dy, and returns the
+// ID of the copy
+func (a *API) Duplicate(id string, dx, dy int) (string, error) {
+	comp := a.session.GetComponent(id)
+	if comp == nil {
+		return "", fmt.Errorf("component not found: %s", id)
+	}
+	params := PasteComponentsParams{Components: []schema.Component{*comp}, DX: dx, DY: dy}
+	data, _ := json.Marshal(params)
+	cmd := Command{Type: string(CmdPasteComponents), Params: data}
+	if err := a.session.Execute(cmd); err != nil {
+		return "", err
+	}
+
+	comps := a.session.ListComponents()
+	return comps[len(comps)-1].ID, nil
+}
+
+// Group moves components into a new container and returns its ID
+func (a *API) Group(name string, ids ...string) (string, error) {
+	params := GroupComponentsParams{IDs: ids, Name: name}
+	data, _ := json.Marshal(params)
+	cmd := Command{Type: string(CmdGroupComponents), Params: data}
+	if err := a.session.Execute(cmd); err != nil {
+		return "", err
+	}
+
+	for _, comp := range a.session.ListComponents() {
+		if len(comp.Children) > 0 && comp.Children[0].ID == ids[0] {
+			return comp.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// Ungroup replaces a container with its children
+func (a *API) Ungroup(id string) error {
+	params := UngroupParams{ID: id}
+	data, _ := json.Marshal(params)
+	cmd := Command{Type: string(CmdUngroup), Params: data}
+	return a.session.Execute(cmd)
+}
+
+// BringForward moves a component steps places up the stack of its
+// siblings
+func (a *API) BringForward(id string, steps int) error {
+	params := ReorderParams{ID: id, Steps: steps}
+	data, _ := json.Marshal(params)
+	cmd := Command{Type: string(CmdReorder), Params: data}
+	return a.session.Execute(cmd)
+}
+
+// SendBackward moves a component steps places down the stack of its
+// siblings
+func (a *API) SendBackward(id string, steps int) error {
+	return a.BringForward(id, -steps)
+}
+
+// ResizeCanvas changes the size of the design
+func (a *API) ResizeCanvas(width, height int) error {
+	params := ResizeCanvasParams{Width: width, Height: height}
+	data, _ := json.Marshal(params)
+	cmd := Command{Type: string(CmdResizeCanvas), Params: data}
+	return a.session.Execute(cmd)
+}
+
 // Undo reverts the last action
 func (a *API) Undo() bool {
 	return a.session.Undo()
@@ -166,6 +242,11 @@ func (a *API) SetTheme(theme string) {
 	a.session.SetTheme(theme)
 }
 
+// SetIDStrategy sets how IDs are generated for components added from now on
+func (a *API) SetIDStrategy(strategy schema.IDStrategy, seed int64) {
+	a.session.SetIDStrategy(strategy, seed)
+}
+
 // GetCanvas returns the current canvas
 func (a *API) GetCanvas() *schema.Canvas {
 	return &a.session.Canvas
@@ -24,6 +24,12 @@ const (
 	CmdResizeComponent CommandType = "resize_component"
 	CmdStyleComponent  CommandType = "style_component"
 	CmdSetText         CommandType = "set_text"
+	CmdUpdateComponent CommandType = "update_component"
+	CmdPasteComponents CommandType = "paste_components"
+	CmdGroupComponents CommandType = "group_components"
+	CmdUngroup         CommandType = "ungroup_component"
+	CmdReorder         CommandType = "reorder_component"
+	CmdResizeCanvas    CommandType = "resize_canvas"
 	CmdExport          CommandType = "export"
 	CmdSave            CommandType = "save"
 	CmdLoad            CommandType = "load"
@@ -69,6 +75,46 @@ type SetTextParams struct {
 	Text string `json:"text"`
 }
 
+// UpdateComponentParams parameters for replacing a component. The
+// component with the same ID is replaced as a whole.
+type UpdateComponentParams struct {
+	Component schema.Component `json:"component"`
+}
+
+// PasteComponentsParams parameters for pasting copies of components.
+// Each copy gets new IDs and is offset by DX, DY.
+type PasteComponentsParams struct {
+	Components []schema.Component `json:"components"`
+	DX         int                `json:"dx"`
+	DY         int                `json:"dy"`
+}
+
+// GroupComponentsParams parameters for grouping components into a new
+// container. The container is placed where the topmost of them was.
+type GroupComponentsParams struct {
+	IDs  []string `json:"ids"`
+	Name string   `json:"name"`
+}
+
+// UngroupParams parameters for replacing a container with its children
+type UngroupParams struct {
+	ID string `json:"id"`
+}
+
+// ReorderParams parameters for moving a component up or down the stack
+// of its siblings. Positive steps bring it forward, negative ones send
+// it backward.
+type ReorderParams struct {
+	ID    string `json:"id"`
+	Steps int    `json:"steps"`
+}
+
+// ResizeCanvasParams parameters for changing the size of the design
+type ResizeCanvasParams struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
 // Session represents an AI agent's design session
 type Session struct {
 	Canvas     schema.Canvas
@@ -93,11 +139,43 @@ func NewSession(name string, width, height int) *Session {
 	}
 }
 
-// Execute executes a command on the session
+// Execute executes a command on the session as a new undo step.
+// A command that fails leaves the canvas and history untouched.
 func (s *Session) Execute(cmd Command) error {
+	undo, redo := s.UndoStack, s.RedoStack
+
 	// Save state for undo
 	s.saveState()
 
+	if err := s.apply(cmd); err != nil {
+		s.Canvas = s.UndoStack[len(s.UndoStack)-1]
+		s.UndoStack, s.RedoStack = undo, redo
+		return err
+	}
+	s.History = append(s.History, cmd)
+	return nil
+}
+
+// Amend executes a command as part of the last undo step, so that a
+// gesture made of many small edits, like nudging a component across
+// the canvas, is undone at once. With no step to amend it behaves
+// like Execute.
+func (s *Session) Amend(cmd Command) error {
+	if len(s.UndoStack) == 0 {
+		return s.Execute(cmd)
+	}
+
+	before := s.Canvas.Clone()
+	if err := s.apply(cmd); err != nil {
+		s.Canvas = before
+		return err
+	}
+	s.History = append(s.History, cmd)
+	s.RedoStack = nil
+	return nil
+}
+
+func (s *Session) apply(cmd Command) error {
 	switch CommandType(cmd.Type) {
 	case CmdAddComponent:
 		return s.addComponent(cmd.Params)
@@ -111,6 +189,18 @@ func (s *Session) Execute(cmd Command) error {
 		return s.styleComponent(cmd.Params)
 	case CmdSetText:
 		return s.setText(cmd.Params)
+	case CmdUpdateComponent:
+		return s.updateComponent(cmd.Params)
+	case CmdPasteComponents:
+		return s.pasteComponents(cmd.Params)
+	case CmdGroupComponents:
+		return s.groupComponents(cmd.Params)
+	case CmdUngroup:
+		return s.ungroupComponent(cmd.Params)
+	case CmdReorder:
+		return s.reorderComponent(cmd.Params)
+	case CmdResizeCanvas:
+		return s.resizeCanvas(cmd.Params)
 	default:
 		return fmt.Errorf("unknown command type: %s", cmd.Type)
 	}
@@ -118,10 +208,7 @@ func (s *Session) Execute(cmd Command) error {
 
 func (s *Session) saveState() {
 	// Deep copy canvas for undo
-	stateCopy := s.Canvas
-	stateCopy.Components = make([]schema.Component, len(s.Canvas.Components))
-	copy(stateCopy.Components, s.Canvas.Components)
-	s.UndoStack = append(s.UndoStack, stateCopy)
+	s.UndoStack = append(s.UndoStack, s.Canvas.Clone())
 	s.RedoStack = nil // Clear redo stack on new action
 }
 
@@ -131,7 +218,7 @@ func (s *Session) addComponent(params json.RawMessage) error {
 		return err
 	}
 
-	comp := schema.NewComponent(p.Type, p.Name)
+	comp := s.Canvas.NewComponent(p.Type, p.Name)
 	comp.Position = schema.Position{X: p.X, Y: p.Y}
 	if p.Width > 0 {
 		comp.Size.Width = p.Width
@@ -55,6 +55,167 @@ func (s *Session) setText(params json.RawMessage) error {
 	return fmt.Errorf("component not found: %s", p.ID)
 }
 
+func (s *Session) updateComponent(params json.RawMessage) error {
+	var p UpdateComponentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+
+	// Components inside containers can be updated too, as the layers
+	// panel does to rename, hide or lock them
+	if comp := s.Canvas.Find(p.Component.ID); comp != nil {
+		*comp = p.Component
+		return nil
+	}
+	return fmt.Errorf("component not found: %s", p.Component.ID)
+}
+
+func (s *Session) pasteComponents(params json.RawMessage) error {
+	var p PasteComponentsParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	if len(p.Components) == 0 {
+		return fmt.Errorf("nothing to paste")
+	}
+
+	for _, comp := range p.Components {
+		comp = comp.Clone()
+		comp.Position.X += p.DX
+		comp.Position.Y += p.DY
+		clearIDs(&comp)
+
+		// Add first, so that new IDs never collide with each other
+		s.Canvas.Components = append(s.Canvas.Components, comp)
+		s.renewIDs(&s.Canvas.Components[len(s.Canvas.Components)-1])
+	}
+	return nil
+}
+
+// groupComponents moves components into a new box sized to their
+// bounds. Children are positioned relative to the box, in the order
+// they were stacked.
+func (s *Session) groupComponents(params json.RawMessage) error {
+	var p GroupComponentsParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	if len(p.IDs) == 0 {
+		return fmt.Errorf("nothing to group")
+	}
+	ids := map[string]bool{}
+	for _, id := range p.IDs {
+		ids[id] = true
+	}
+
+	var children, rest []schema.Component
+	at := 0 // index of the group among the remaining components
+	for _, comp := range s.Canvas.Components {
+		if ids[comp.ID] {
+			children = append(children, comp)
+			at = len(rest)
+			delete(ids, comp.ID)
+		} else {
+			rest = append(rest, comp)
+		}
+	}
+	for id := range ids {
+		return fmt.Errorf("component not found: %s", id)
+	}
+
+	x0, y0 := children[0].Position.X, children[0].Position.Y
+	x1, y1 := x0, y0
+	for _, c := range children {
+		x0, y0 = min(x0, c.Position.X), min(y0, c.Position.Y)
+		x1 = max(x1, c.Position.X+c.Size.Width)
+		y1 = max(y1, c.Position.Y+c.Size.Height)
+	}
+	for i := range children {
+		children[i].Position.X -= x0
+		children[i].Position.Y -= y0
+	}
+
+	name := p.Name
+	if name == "" {
+		name = "Group"
+	}
+	group := s.Canvas.NewComponent(schema.TypeBox, name)
+	group.Position = schema.Position{X: x0, Y: y0}
+	group.Size = schema.Size{Width: x1 - x0, Height: y1 - y0}
+	group.Style.Border = nil // a plain group draws only its children
+	group.Children = children
+
+	s.Canvas.Components = append(rest[:at], append([]schema.Component{group}, rest[at:]...)...)
+	return nil
+}
+
+// ungroupComponent replaces a container with its children, back in
+// canvas coordinates
+func (s *Session) ungroupComponent(params json.RawMessage) error {
+	var p UngroupParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+
+	for i, comp := range s.Canvas.Components {
+		if comp.ID != p.ID {
+			continue
+		}
+		if len(comp.Children) == 0 {
+			return fmt.Errorf("component has no children: %s", p.ID)
+		}
+		children := make([]schema.Component, len(comp.Children))
+		for j, child := range comp.Children {
+			child.Position.X += comp.Position.X
+			child.Position.Y += comp.Position.Y
+			children[j] = child
+		}
+		rest := append([]schema.Component{}, s.Canvas.Components[i+1:]...)
+		s.Canvas.Components = append(append(s.Canvas.Components[:i], children...), rest...)
+		return nil
+	}
+	return fmt.Errorf("component not found: %s", p.ID)
+}
+
+// reorderComponent brings a component forward or sends it backward
+// among its siblings
+func (s *Session) reorderComponent(params json.RawMessage) error {
+	var p ReorderParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	if !s.Canvas.Restack(p.ID, p.Steps) {
+		return fmt.Errorf("component not found: %s", p.ID)
+	}
+	return nil
+}
+
+func (s *Session) resizeCanvas(params json.RawMessage) error {
+	var p ResizeCanvasParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return err
+	}
+	if p.Width <= 0 || p.Height <= 0 {
+		return fmt.Errorf("invalid canvas size %dx%d", p.Width, p.Height)
+	}
+	s.Canvas.Width, s.Canvas.Height = p.Width, p.Height
+	return nil
+}
+
+func clearIDs(comp *schema.Component) {
+	comp.ID = ""
+	for i := range comp.Children {
+		clearIDs(&comp.Children[i])
+	}
+}
+
+func (s *Session) renewIDs(comp *schema.Component) {
+	comp.ID = s.Canvas.NewID(comp.Type, comp.Name)
+	for i := range comp.Children {
+		s.renewIDs(&comp.Children[i])
+	}
+}
+
 // Undo reverts the last action
 func (s *Session) Undo() bool {
 	if len(s.UndoStack) == 0 {
@@ -136,6 +297,14 @@ func (s *Session) SetTheme(theme string) {
 	s.Canvas.Theme = theme
 }
 
+// SetIDStrategy changes how new component IDs are generated.
+// The seed only matters for schema.IDStrategySeeded.
+func (s *Session) SetIDStrategy(strategy schema.IDStrategy, seed int64) {
+	s.Canvas.IDStrategy = strategy
+	s.Canvas.IDSeed = seed
+	s.Canvas.IDSeq = 0
+}
+
 // Resize changes the canvas dimensions
 func (s *Session) Resize(width, height int) {
 	s.Canvas.Width = width
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func command(t *testing.T, ct CommandType, params any) Command {
+	t.Helper()
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Command{Type: string(ct), Params: data}
+}
+
+func TestFailedCommandLeavesHistory(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	if err := s.Execute(command(t, CmdAddComponent, AddComponentParams{Type: schema.TypeBox, Name: "Box"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Execute(command(t, CmdMoveComponent, MoveComponentParams{ID: "missing"})); err == nil {
+		t.Fatal("moving a missing component succeeded")
+	}
+	if len(s.UndoStack) != 1 || len(s.History) != 1 {
+		t.Errorf("undo stack %d, history %d; want 1, 1", len(s.UndoStack), len(s.History))
+	}
+}
+
+func TestAmendUndoesAtOnce(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	_ = s.Execute(command(t, CmdAddComponent, AddComponentParams{Type: schema.TypeBox, Name: "Box", X: 1, Y: 1}))
+	id := s.Canvas.Components[0].ID
+
+	_ = s.Execute(command(t, CmdMoveComponent, MoveComponentParams{ID: id, X: 2, Y: 1}))
+	for x := 3; x <= 5; x++ {
+		if err := s.Amend(command(t, CmdMoveComponent, MoveComponentParams{ID: id, X: x, Y: 1})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := s.Canvas.Components[0].Position.X; got != 5 {
+		t.Fatalf("X = %d, want 5", got)
+	}
+
+	s.Undo()
+	if got := s.Canvas.Components[0].Position.X; got != 1 {
+		t.Errorf("after undo X = %d, want 1", got)
+	}
+}
+
+func TestUndoKeepsNestedState(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	_ = s.Execute(command(t, CmdAddComponent, AddComponentParams{Type: schema.TypeBox, Name: "Box"}))
+
+	// Editing the live canvas in place must not reach the saved state
+	comp := s.Canvas.Components[0].Clone()
+	comp.Style.Border.Style = "double"
+	_ = s.Execute(command(t, CmdUpdateComponent, UpdateComponentParams{Component: comp}))
+	s.Canvas.Components[0].Style.Border.Style = "thick"
+
+	s.Undo()
+	if got := s.Canvas.Components[0].Style.Border.Style; got != "rounded" {
+		t.Errorf("after undo border = %q, want rounded", got)
+	}
+}
+
+func TestPasteAssignsNewIDs(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	s.SetIDStrategy(schema.IDStrategySlug, 0)
+	_ = s.Execute(command(t, CmdAddComponent, AddComponentParams{Type: schema.TypeBox, Name: "Panel", X: 4, Y: 2}))
+	orig := s.Canvas.Components[0]
+	orig.Children = []schema.Component{
+		{ID: "item", Type: schema.TypeText, Name: "Item"},
+		{ID: "item-2", Type: schema.TypeText, Name: "Item"},
+	}
+	s.Canvas.Components[0] = orig
+
+	if err := s.Execute(command(t, CmdPasteComponents, PasteComponentsParams{Components: []schema.Component{orig}, DX: 2, DY: 1})); err != nil {
+		t.Fatal(err)
+	}
+
+	pasted := s.Canvas.Components[1]
+	if pasted.Position != (schema.Position{X: 6, Y: 3}) {
+		t.Errorf("position = %+v, want {6 3}", pasted.Position)
+	}
+	seen := map[string]bool{}
+	for _, c := range s.Canvas.Components {
+		for _, id := range append([]string{c.ID}, c.Children[0].ID, c.Children[1].ID) {
+			if seen[id] {
+				t.Errorf("duplicate ID %q", id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestGroupAndUngroup(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	for _, p := range []AddComponentParams{
+		{Type: schema.TypeBox, Name: "A", X: 4, Y: 2, Width: 10, Height: 3},
+		{Type: schema.TypeText, Name: "B", X: 1, Y: 8, Width: 5, Height: 1},
+		{Type: schema.TypeBox, Name: "C", X: 20, Y: 4, Width: 6, Height: 6},
+	} {
+		_ = s.Execute(command(t, CmdAddComponent, p))
+	}
+	before := s.Canvas.Clone().Components
+	a, c := before[0].ID, before[2].ID
+
+	if err := s.Execute(command(t, CmdGroupComponents, GroupComponentsParams{IDs: []string{c, a}})); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Canvas.Components) != 2 {
+		t.Fatalf("%d components after grouping, want 2", len(s.Canvas.Components))
+	}
+	group := s.Canvas.Components[1]
+	if group.Position != (schema.Position{X: 4, Y: 2}) || group.Size != (schema.Size{Width: 22, Height: 8}) {
+		t.Errorf("group bounds = %+v %+v, want {4 2} {22 8}", group.Position, group.Size)
+	}
+	if len(group.Children) != 2 || group.Children[0].ID != a || group.Children[1].Position != (schema.Position{X: 16, Y: 2}) {
+		t.Errorf("children = %+v", group.Children)
+	}
+
+	if err := s.Execute(command(t, CmdUngroup, UngroupParams{ID: group.ID})); err != nil {
+		t.Fatal(err)
+	}
+	got := s.Canvas.Components
+	if len(got) != 3 || got[0].ID != before[1].ID || got[1].Position != before[0].Position || got[2].Position != before[2].Position {
+		t.Errorf("after ungroup = %+v", got)
+	}
+}
+
+func TestReorder(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	for _, name := range []string{"A", "B", "C"} {
+		_ = s.Execute(command(t, CmdAddComponent, AddComponentParams{Type: schema.TypeBox, Name: name}))
+	}
+	order := func() string {
+		var names []string
+		for _, comp := range schema.Stack(s.Canvas.Components) {
+			names = append(names, comp.Name)
+		}
+		return strings.Join(names, "")
+	}
+	a := s.Canvas.Components[0].ID
+
+	if err := s.Execute(command(t, CmdReorder, ReorderParams{ID: a, Steps: 1})); err != nil {
+		t.Fatal(err)
+	}
+	if got := order(); got != "BAC" {
+		t.Errorf("after bring forward = %s, want BAC", got)
+	}
+	_ = s.Execute(command(t, CmdReorder, ReorderParams{ID: a, Steps: 10}))
+	if got := order(); got != "BCA" {
+		t.Errorf("after bring to front = %s, want BCA", got)
+	}
+
+	// A component above the rest by ZIndex is passed by taking its ZIndex
+	s.Canvas.Components[0].ZIndex = 5 // B
+	if got := order(); got != "CAB" {
+		t.Fatalf("with B raised = %s, want CAB", got)
+	}
+	_ = s.Execute(command(t, CmdReorder, ReorderParams{ID: a, Steps: 1}))
+	if got := order(); got != "CBA" || s.Canvas.Find(a).ZIndex != 5 {
+		t.Errorf("after bring forward = %s with z %d, want CBA with z 5", got, s.Canvas.Find(a).ZIndex)
+	}
+}
+
+func TestResizeCanvasUndoes(t *testing.T) {
+	s := NewSession("test", 80, 24)
+	if err := s.Execute(command(t, CmdResizeCanvas, ResizeCanvasParams{Width: 200, Height: 60})); err != nil {
+		t.Fatal(err)
+	}
+	if s.Canvas.Width != 200 || s.Canvas.Height != 60 {
+		t.Fatalf("size %dx%d, want 200x60", s.Canvas.Width, s.Canvas.Height)
+	}
+	if err := s.Execute(command(t, CmdResizeCanvas, ResizeCanvasParams{Width: 0, Height: 10})); err == nil {
+		t.Error("resizing to zero width succeeded")
+	}
+	s.Undo()
+	if s.Canvas.Width != 80 || s.Canvas.Height != 24 {
+		t.Errorf("after undo size %dx%d, want 80x24", s.Canvas.Width, s.Canvas.Height)
+	}
+}
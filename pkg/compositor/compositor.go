@@ -0,0 +1,304 @@
+// Package compositor overlays styled terminal strings on a cell buffer.
+//
+// Strings are split into cells (one grapheme cluster each, wide
+// characters taking two), keeping the SGR styling active for every
+// cell, so a lipgloss-rendered box can be placed over another one at
+// any position without breaking escape sequences or wide runes.
+package compositor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+const reset = "\x1b[0m"
+
+// Cell is a single terminal cell
+type Cell struct {
+	// Content is the grapheme cluster drawn in the cell. It is empty
+	// for the second half of a wide character.
+	Content string
+
+	// Width is 1 or 2, or 0 for the second half of a wide character
+	Width int
+
+	// Style is the SGR sequence active for the cell ("" for none)
+	Style string
+}
+
+var blank = Cell{Content: " ", Width: 1}
+
+// Buffer is a fixed-size grid of cells
+type Buffer struct {
+	width  int
+	height int
+	cells  []Cell
+}
+
+// NewBuffer creates a buffer of blank cells
+func NewBuffer(width, height int) *Buffer {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	b := &Buffer{width: width, height: height, cells: make([]Cell, width*height)}
+	for i := range b.cells {
+		b.cells[i] = blank
+	}
+	return b
+}
+
+// Parse creates a buffer just large enough to hold s
+func Parse(s string) *Buffer {
+	lines := strings.Split(s, "\n")
+	width := 0
+	style := ""
+	for _, line := range lines {
+		w := 0
+		parseLine(line, &style, func(c Cell) { w += c.Width })
+		if w > width {
+			width = w
+		}
+	}
+	b := NewBuffer(width, len(lines))
+	b.Draw(0, 0, s)
+	return b
+}
+
+// Width returns the buffer width in cells
+func (b *Buffer) Width() int { return b.width }
+
+// Height returns the buffer height in cells
+func (b *Buffer) Height() int { return b.height }
+
+// Cell returns the cell at x, y (a blank cell when out of bounds)
+func (b *Buffer) Cell(x, y int) Cell {
+	if x < 0 || y < 0 || x >= b.width || y >= b.height {
+		return blank
+	}
+	return b.cells[y*b.width+x]
+}
+
+// Draw overlays s with its top-left corner at x, y. Every cell of s
+// is opaque, including spaces; anything outside the buffer is clipped.
+func (b *Buffer) Draw(x, y int, s string) {
+	style := ""
+	for dy, line := range strings.Split(s, "\n") {
+		col := x
+		parseLine(line, &style, func(c Cell) {
+			b.put(col, y+dy, c)
+			col += c.Width
+		})
+	}
+}
+
+// DrawBuffer overlays another buffer with its top-left corner at x, y
+func (b *Buffer) DrawBuffer(x, y int, o *Buffer) {
+	for oy := 0; oy < o.height; oy++ {
+		for ox := 0; ox < o.width; ox++ {
+			if c := o.cells[oy*o.width+ox]; c.Width > 0 {
+				b.put(x+ox, y+oy, c)
+			}
+		}
+	}
+}
+
+// Fill sets every cell in the rectangle to c
+func (b *Buffer) Fill(x, y, width, height int, c Cell) {
+	for row := y; row < y+height; row++ {
+		for col := x; col < x+width; col++ {
+			b.put(col, row, c)
+		}
+	}
+}
+
+// Tint adds the SGR sequence sgr to the style of every cell in the
+// rectangle, such as a background color to highlight it
+func (b *Buffer) Tint(x, y, width, height int, sgr string) {
+	for row := max(y, 0); row < min(y+height, b.height); row++ {
+		for col := max(x, 0); col < min(x+width, b.width); col++ {
+			b.cells[row*b.width+col].Style += sgr
+		}
+	}
+}
+
+// put writes a cell, repairing any wide character it partly covers
+func (b *Buffer) put(x, y int, c Cell) {
+	if y < 0 || y >= b.height {
+		return
+	}
+	if x < 0 {
+		// Only the right half of a wide character is visible
+		if c.Width == 2 && x == -1 {
+			b.put(0, y, Cell{Content: " ", Width: 1, Style: c.Style})
+		}
+		return
+	}
+	if x >= b.width {
+		return
+	}
+	if c.Width == 2 && x+1 >= b.width {
+		c = Cell{Content: " ", Width: 1, Style: c.Style}
+	}
+
+	b.split(x, y)
+	if c.Width == 2 {
+		b.split(x+1, y)
+	}
+	b.cells[y*b.width+x] = c
+	if c.Width == 2 {
+		b.cells[y*b.width+x+1] = Cell{Style: c.Style}
+	}
+}
+
+// split blanks the other half of a wide character at x, y so that
+// overwriting one half never leaves a broken character behind
+func (b *Buffer) split(x, y int) {
+	i := y*b.width + x
+	switch b.cells[i].Width {
+	case 0:
+		if x > 0 {
+			lead := &b.cells[i-1]
+			*lead = Cell{Content: " ", Width: 1, Style: lead.Style}
+		}
+	case 2:
+		if x+1 < b.width {
+			b.cells[i+1] = Cell{Content: " ", Width: 1, Style: b.cells[i].Style}
+		}
+	}
+}
+
+// String renders the buffer, emitting SGR sequences only where the
+// style changes and resetting at the end of every styled line
+func (b *Buffer) String() string {
+	var sb strings.Builder
+	for y := 0; y < b.height; y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		current := ""
+		for x := 0; x < b.width; x++ {
+			c := b.cells[y*b.width+x]
+			if c.Width == 0 {
+				continue
+			}
+			if c.Style != current {
+				if current != "" {
+					sb.WriteString(reset)
+				}
+				sb.WriteString(c.Style)
+				current = c.Style
+			}
+			sb.WriteString(c.Content)
+		}
+		if current != "" {
+			sb.WriteString(reset)
+		}
+	}
+	return sb.String()
+}
+
+// Layer is a string placed at a position with a z-index. Higher Z is
+// drawn on top; layers with equal Z keep their order.
+type Layer struct {
+	Content string
+	X, Y    int
+	Z       int
+}
+
+// Compose draws the layers onto the buffer in z-order
+func (b *Buffer) Compose(layers ...Layer) *Buffer {
+	sorted := make([]Layer, len(layers))
+	copy(sorted, layers)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Z < sorted[j].Z })
+	for _, l := range sorted {
+		b.Draw(l.X, l.Y, l.Content)
+	}
+	return b
+}
+
+// Overlay places overlay on top of base at x, y. The result keeps the
+// size of base; whatever falls outside it is clipped.
+func Overlay(base, overlay string, x, y int) string {
+	b := Parse(base)
+	b.Draw(x, y, overlay)
+	return b.String()
+}
+
+// parseLine splits one line into cells, tracking the SGR style across
+// calls. Non-SGR escape sequences and control characters are dropped.
+func parseLine(line string, style *string, emit func(Cell)) {
+	for i := 0; i < len(line); {
+		ch := line[i]
+		if ch == 0x1b {
+			i += escape(line[i:], style)
+			continue
+		}
+		if ch == '\t' {
+			emit(Cell{Content: " ", Width: 1, Style: *style})
+			i++
+			continue
+		}
+		if ch < 0x20 || ch == 0x7f {
+			i++
+			continue
+		}
+
+		cluster, _, width, _ := uniseg.FirstGraphemeClusterInString(line[i:], -1)
+		i += len(cluster)
+		if width == 0 {
+			continue
+		}
+		if width > 2 {
+			width = 2
+		}
+		emit(Cell{Content: cluster, Width: width, Style: *style})
+	}
+}
+
+// escape consumes the escape sequence at the start of s, applies it to
+// style if it is SGR, and returns its length
+func escape(s string, style *string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[': // CSI: parameters, then a final byte in 0x40-0x7E
+		for j := 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				if s[j] == 'm' {
+					applySGR(s[:j+1], style)
+				}
+				return j + 1
+			}
+		}
+		return len(s)
+	case ']': // OSC, ended by BEL or ESC \
+		for j := 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+func applySGR(seq string, style *string) {
+	params := seq[2 : len(seq)-1]
+	switch {
+	case params == "" || params == "0":
+		*style = ""
+	case strings.HasPrefix(params, "0;"):
+		*style = "\x1b[" + params[2:] + "m"
+	default:
+		*style += seq
+	}
+}
@@ -0,0 +1,67 @@
+package compositor
+
+import (
+	"testing"
+)
+
+func TestOverlayPlain(t *testing.T) {
+	base := "......\n......\n......"
+	got := Overlay(base, "ab\ncd", 4, 1)
+	want := "......\n....ab\n....cd"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Clipped on every side
+	got = Overlay(base, "xyz\nxyz", -1, 2)
+	if want := "......\n......\nyz...."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOverlayKeepsStyles(t *testing.T) {
+	base := "\x1b[31m" + "rrrr" + "\x1b[0m"
+	got := Overlay(base, "\x1b[1mB\x1b[0m", 1, 0)
+	want := "\x1b[31mr\x1b[0m\x1b[1mB\x1b[0m\x1b[31mrr\x1b[0m"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOverlayWideRunes(t *testing.T) {
+	// Covering half of a wide character blanks the other half
+	got := Overlay("世界", "x", 1, 0)
+	if want := " x界"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A wide character that does not fit becomes a space
+	got = Overlay("...", "世", 2, 0)
+	if want := ".. "; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	b := Parse("a世b")
+	if b.Width() != 4 || b.Cell(2, 0).Width != 0 {
+		t.Errorf("width %d, cell %+v", b.Width(), b.Cell(2, 0))
+	}
+}
+
+func TestComposeZOrder(t *testing.T) {
+	b := NewBuffer(3, 1).Compose(
+		Layer{Content: "top", Z: 2},
+		Layer{Content: "bot", Z: 1},
+		Layer{Content: "X", X: 1, Z: 2},
+	)
+	if got := b.String(); got != "tXp" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestTint(t *testing.T) {
+	b := Parse("abc\ndef")
+	b.Tint(1, 1, 5, 5, "\x1b[41m")
+	if got, want := b.String(), "abc\nd\x1b[41mef\x1b[0m"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
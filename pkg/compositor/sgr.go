@@ -0,0 +1,128 @@
+// Package compositor - Cell styles as colors and attributes
+package compositor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ansi16 are the 16 basic terminal colors, as xterm has them
+var ansi16 = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// Attrs is the styling of a cell read from its SGR sequences. FG and BG
+// are "#rrggbb" colors, or "" for the terminal's own.
+type Attrs struct {
+	FG, BG                                          string
+	Bold, Faint, Italic, Underline, Reverse, Strike bool
+}
+
+// Attrs reads the styling of the cell
+func (c Cell) Attrs() Attrs {
+	return ParseSGR(c.Style)
+}
+
+// ParseSGR reads a run of SGR sequences, such as the Style of a cell
+func ParseSGR(seqs string) Attrs {
+	var a Attrs
+	for _, seq := range strings.Split(seqs, "\x1b[") {
+		params := strings.Split(strings.TrimSuffix(seq, "m"), ";")
+		for i := 0; i < len(params); i++ {
+			n, err := strconv.Atoi(params[i])
+			if err != nil {
+				continue
+			}
+			switch {
+			case n == 0:
+				a = Attrs{}
+			case n == 1:
+				a.Bold = true
+			case n == 2:
+				a.Faint = true
+			case n == 3:
+				a.Italic = true
+			case n == 4:
+				a.Underline = true
+			case n == 7:
+				a.Reverse = true
+			case n == 9:
+				a.Strike = true
+			case n == 22:
+				a.Bold, a.Faint = false, false
+			case n == 23:
+				a.Italic = false
+			case n == 24:
+				a.Underline = false
+			case n == 27:
+				a.Reverse = false
+			case n == 29:
+				a.Strike = false
+			case n >= 30 && n <= 37:
+				a.FG = ansi16[n-30]
+			case n >= 90 && n <= 97:
+				a.FG = ansi16[n-90+8]
+			case n >= 40 && n <= 47:
+				a.BG = ansi16[n-40]
+			case n >= 100 && n <= 107:
+				a.BG = ansi16[n-100+8]
+			case n == 39:
+				a.FG = ""
+			case n == 49:
+				a.BG = ""
+			case n == 38 || n == 48:
+				color, used := extendedColor(params[i+1:])
+				i += used
+				if n == 38 {
+					a.FG = color
+				} else {
+					a.BG = color
+				}
+			}
+		}
+	}
+	return a
+}
+
+// extendedColor reads a 256-color (5;n) or true color (2;r;g;b)
+// parameter list, returning the color and how many parameters it took
+func extendedColor(params []string) (string, int) {
+	num := func(i int) int {
+		if i >= len(params) {
+			return 0
+		}
+		n, _ := strconv.Atoi(params[i])
+		return min(max(n, 0), 255)
+	}
+	if len(params) == 0 {
+		return "", 0
+	}
+	switch params[0] {
+	case "2":
+		return fmt.Sprintf("#%02x%02x%02x", num(1), num(2), num(3)), 4
+	case "5":
+		return color256(num(1)), 2
+	}
+	return "", 1
+}
+
+// color256 is the color of an xterm 256-color index
+func color256(n int) string {
+	switch {
+	case n < 16:
+		return ansi16[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	}
+	v := 8 + (n-232)*10
+	return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+}
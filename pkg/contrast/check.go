@@ -0,0 +1,357 @@
+// Package contrast - Canvas and theme checks
+package contrast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Level is a WCAG conformance level
+type Level string
+
+const (
+	LevelAA  Level = "AA"
+	LevelAAA Level = "AAA"
+)
+
+// ParseLevel reads a WCAG level, AA or AAA in either case
+func ParseLevel(s string) (Level, error) {
+	switch l := Level(strings.ToUpper(strings.TrimSpace(s))); l {
+	case LevelAA, LevelAAA:
+		return l, nil
+	}
+	return "", fmt.Errorf("unknown WCAG level %q (use AA or AAA)", s)
+}
+
+// Kind distinguishes text from graphical elements such as borders,
+// which WCAG holds to a lower contrast requirement
+type Kind string
+
+const (
+	KindText    Kind = "text"
+	KindNonText Kind = "non-text"
+)
+
+// Required returns the minimum contrast ratio for the kind at this level
+func (l Level) Required(kind Kind) float64 {
+	if kind == KindNonText {
+		return 3.0
+	}
+	if l == LevelAAA {
+		return 7.0
+	}
+	return 4.5
+}
+
+// Pair is a resolved foreground/background combination
+type Pair struct {
+	ComponentID string  `json:"component_id,omitempty"`
+	Component   string  `json:"component,omitempty"`
+	Role        string  `json:"role"`
+	Kind        Kind    `json:"kind"`
+	Foreground  string  `json:"foreground"`
+	Background  string  `json:"background"`
+	Ratio       float64 `json:"ratio"`
+	Required    float64 `json:"required"`
+	Pass        bool    `json:"pass"`
+	Suggestion  string  `json:"suggestion,omitempty"`
+
+	// Inherited is true when both colors come from the theme rather
+	// than from the component's own style
+	Inherited bool `json:"inherited,omitempty"`
+
+	// Ratios after the terminal downsamples both colors
+	Ratio256 float64 `json:"ratio_256"`
+	Ratio16  float64 `json:"ratio_16"`
+}
+
+// Collapse lists distinct colors that become one color on a
+// terminal with fewer colors
+type Collapse struct {
+	Profile Profile  `json:"profile"`
+	Index   int      `json:"index"`
+	Into    string   `json:"into"`
+	Colors  []string `json:"colors"`
+}
+
+// Report is the result of a contrast check
+type Report struct {
+	Level     Level      `json:"level"`
+	Theme     string     `json:"theme"`
+	Pairs     []Pair     `json:"pairs"`
+	Collapses []Collapse `json:"collapses"`
+}
+
+// Failures returns the pairs that fail at full color depth
+func (r Report) Failures() []Pair {
+	var result []Pair
+	for _, p := range r.Pairs {
+		if !p.Pass {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// DownsampleFailures returns the pairs that pass at full color depth
+// but fail once the terminal reduces colors to the given profile
+func (r Report) DownsampleFailures(profile Profile) []Pair {
+	var result []Pair
+	for _, p := range r.Pairs {
+		ratio := p.Ratio256
+		if profile == Profile16 {
+			ratio = p.Ratio16
+		}
+		if p.Pass && ratio < p.Required {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Options configures a check
+type Options struct {
+	Level        Level
+	IncludeTheme bool
+}
+
+// Check resolves every fg/bg pair the canvas renders with, using the
+// theme for defaults, and checks each against the level
+func Check(canvas *schema.Canvas, theme styles.Theme, opts Options) Report {
+	if opts.Level == "" {
+		opts.Level = LevelAA
+	}
+	report := Report{Level: opts.Level, Theme: theme.Name}
+	if opts.IncludeTheme {
+		report.Pairs = append(report.Pairs, CheckTheme(theme, opts.Level)...)
+	}
+	if canvas != nil {
+		walk(canvas.Components, func(c schema.Component) {
+			report.Pairs = append(report.Pairs, componentPairs(c, theme, opts.Level)...)
+		})
+	}
+	report.Collapses = collapses(report.Pairs)
+	return report
+}
+
+// CheckTheme checks the theme's own text, semantic and border colors
+// against its background and surfaces
+func CheckTheme(theme styles.Theme, level Level) []Pair {
+	var result []Pair
+	surfaces := []struct {
+		name  string
+		color lipgloss.Color
+	}{
+		{"Background", theme.Background},
+		{"Surface", theme.Surface},
+		{"SurfaceLight", theme.SurfaceLight},
+	}
+	texts := []struct {
+		name  string
+		color lipgloss.Color
+	}{
+		{"TextPrimary", theme.TextPrimary},
+		{"TextSecondary", theme.TextSecondary},
+		{"TextMuted", theme.TextMuted},
+	}
+	for _, t := range texts {
+		for _, s := range surfaces {
+			result = addPair(result, level, t.name+" on "+s.name, KindText, string(t.color), string(s.color))
+		}
+	}
+
+	semantic := []struct {
+		name  string
+		color lipgloss.Color
+	}{
+		{"Primary", theme.Primary},
+		{"Accent", theme.Accent},
+		{"Success", theme.Success},
+		{"Warning", theme.Warning},
+		{"Error", theme.Error},
+		{"Info", theme.Info},
+	}
+	for _, s := range semantic {
+		result = addPair(result, level, s.name+" on Background", KindText, string(s.color), string(theme.Background))
+	}
+
+	result = addPair(result, level, "Border on Background", KindNonText, string(theme.Border), string(theme.Background))
+	result = addPair(result, level, "BorderActive on Background", KindNonText, string(theme.BorderActive), string(theme.Background))
+	return result
+}
+
+// componentPairs mirrors the colors chosen by internal/ui/components
+func componentPairs(c schema.Component, theme styles.Theme, level Level) []Pair {
+	base := string(theme.Background)
+	fg := or(string(theme.Resolve(c.Style.Foreground)), string(theme.TextPrimary))
+	bg := or(string(theme.Resolve(c.Style.Background)), base)
+
+	var pairs []Pair
+	own := func(inherited bool) {
+		if n := len(pairs); n > 0 {
+			pairs[n-1].Inherited = inherited
+		}
+	}
+	textInherited := c.Style.Foreground == "" && c.Style.Background == ""
+	border := func(color string) {
+		if c.Style.Border != nil && c.Style.Border.Style != "none" {
+			pairs = addPair(pairs, level, "border", KindNonText, or(string(theme.Resolve(c.Style.Border.Color)), color), base)
+			own(c.Style.Border.Color == "")
+		}
+	}
+
+	switch c.Type {
+	case schema.TypeText:
+		pairs = addPair(pairs, level, "text", KindText, fg, bg)
+		own(textInherited)
+	case schema.TypeButton:
+		// Buttons, progress bars and lists are drawn in theme colors
+		if c.Disabled {
+			pairs = addPair(pairs, level, "disabled label", KindText, string(theme.TextMuted), string(theme.Surface))
+		} else {
+			pairs = addPair(pairs, level, "label", KindText, string(theme.TextPrimary), string(theme.Surface))
+		}
+		pairs = addPair(pairs, level, "focused label", KindText, string(theme.TextPrimary), string(theme.Primary))
+		pairs = addPair(pairs, level, "border", KindNonText, string(theme.Border), base)
+		pairs = addPair(pairs, level, "focused border", KindNonText, string(theme.Accent), base)
+	case schema.TypeProgress:
+		pairs = addPair(pairs, level, "bar", KindNonText, string(theme.Primary), base)
+	case schema.TypeList:
+		pairs = addPair(pairs, level, "item", KindText, string(theme.TextSecondary), base)
+		pairs = addPair(pairs, level, "selected item", KindText, string(theme.TextPrimary), string(theme.SurfaceLight))
+		border(string(theme.Border))
+	default:
+		if c.Text != "" {
+			pairs = addPair(pairs, level, "text", KindText, fg, bg)
+			own(textInherited)
+		}
+		border(string(theme.Border))
+	}
+
+	themed := c.Type == schema.TypeButton || c.Type == schema.TypeProgress
+	for i := range pairs {
+		pairs[i].ComponentID = c.ID
+		pairs[i].Component = c.Name
+		if themed || (c.Type == schema.TypeList && pairs[i].Role != "border") {
+			pairs[i].Inherited = true
+		}
+	}
+	return pairs
+}
+
+func or(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+// newPair evaluates a pair; ok is false if either color is unparseable
+func newPair(role string, kind Kind, fgStr, bgStr string, level Level) (Pair, bool) {
+	fg, err := Parse(fgStr)
+	if err != nil {
+		return Pair{}, false
+	}
+	bg, err := Parse(bgStr)
+	if err != nil {
+		return Pair{}, false
+	}
+
+	p := Pair{
+		Role:       role,
+		Kind:       kind,
+		Foreground: fg.Hex(),
+		Background: bg.Hex(),
+		Ratio:      round2(Ratio(fg, bg)),
+		Required:   level.Required(kind),
+	}
+	p.Pass = Ratio(fg, bg) >= p.Required
+	if !p.Pass {
+		if s, ok := Suggest(fg, bg, p.Required); ok {
+			p.Suggestion = s.Hex()
+		}
+	}
+
+	fg256, _ := Downsample(fg, Profile256)
+	bg256, _ := Downsample(bg, Profile256)
+	fg16, _ := Downsample(fg, Profile16)
+	bg16, _ := Downsample(bg, Profile16)
+	p.Ratio256 = round2(Ratio(fg256, bg256))
+	p.Ratio16 = round2(Ratio(fg16, bg16))
+	return p, true
+}
+
+// addPair appends the evaluated pair, skipping unparseable colors
+func addPair(pairs []Pair, level Level, role string, kind Kind, fg, bg string) []Pair {
+	p, ok := newPair(role, kind, fg, bg, level)
+	if !ok {
+		return pairs
+	}
+	return append(pairs, p)
+}
+
+func round2(v float64) float64 {
+	return float64(int(v*100+0.5)) / 100
+}
+
+// collapses groups the distinct colors used by the pairs by the palette
+// entry they map to on 256- and 16-color terminals
+func collapses(pairs []Pair) []Collapse {
+	var colors []string
+	seen := map[string]bool{}
+	for _, p := range pairs {
+		for _, c := range []string{p.Foreground, p.Background} {
+			if !seen[c] {
+				seen[c] = true
+				colors = append(colors, c)
+			}
+		}
+	}
+	sort.Strings(colors)
+
+	var result []Collapse
+	for _, profile := range []Profile{Profile256, Profile16} {
+		groups := map[int][]string{}
+		into := map[int]colorful.Color{}
+		var order []int
+		for _, hex := range colors {
+			c, _ := colorful.Hex(hex)
+			ds, idx := Downsample(c, profile)
+			if _, ok := groups[idx]; !ok {
+				order = append(order, idx)
+			}
+			groups[idx] = append(groups[idx], hex)
+			into[idx] = ds
+		}
+		for _, idx := range order {
+			if len(groups[idx]) > 1 {
+				result = append(result, Collapse{
+					Profile: profile,
+					Index:   idx,
+					Into:    into[idx].Hex(),
+					Colors:  groups[idx],
+				})
+			}
+		}
+	}
+	return result
+}
+
+// walk visits every component, including children
+func walk(comps []schema.Component, fn func(schema.Component)) {
+	for _, c := range comps {
+		fn(c)
+		walk(c.Children, fn)
+	}
+}
+
+// String summarises a collapse on one line
+func (c Collapse) String() string {
+	return string(c.Profile) + "-color: " + strings.Join(c.Colors, ", ") + " → " + c.Into
+}
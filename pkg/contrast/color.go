@@ -0,0 +1,115 @@
+// Package contrast checks color pairs against WCAG contrast guidelines
+package contrast
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+)
+
+// Parse converts a lipgloss color string (#RGB, #RRGGBB or an ANSI
+// color number 0-255) to an RGB color
+func Parse(s string) (colorful.Color, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "#") {
+		return colorful.Hex(s)
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 255 {
+		return colorful.Color{}, fmt.Errorf("invalid color %q", s)
+	}
+	if n < 16 {
+		return termenv.ConvertToRGB(termenv.ANSIColor(n)), nil
+	}
+	return termenv.ConvertToRGB(termenv.ANSI256Color(n)), nil
+}
+
+// Luminance returns the WCAG relative luminance of c
+func Luminance(c colorful.Color) float64 {
+	r, g, b := c.LinearRgb()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// Ratio returns the WCAG contrast ratio between two colors (1 to 21)
+func Ratio(a, b colorful.Color) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Suggest returns the color closest to fg (by Lab lightness, keeping its
+// hue) that reaches the required contrast against bg. The second result
+// is false if no lightness reaches the target.
+func Suggest(fg, bg colorful.Color, required float64) (colorful.Color, bool) {
+	if Ratio(fg, bg) >= required {
+		return fg, true
+	}
+
+	l, a, b := fg.Lab()
+	var best colorful.Color
+	bestDelta := math.Inf(1)
+
+	for _, step := range []float64{0.005, -0.005} {
+		for nl := l + step; nl >= 0 && nl <= 1; nl += step {
+			c := colorful.Lab(nl, a, b).Clamped()
+			if Ratio(c, bg) >= required {
+				if d := math.Abs(nl - l); d < bestDelta {
+					best, bestDelta = c, d
+				}
+				break
+			}
+		}
+	}
+
+	if math.IsInf(bestDelta, 1) {
+		// Even with adjusted lightness the hue can't get there
+		black, white := colorful.Color{}, colorful.Color{R: 1, G: 1, B: 1}
+		if Ratio(white, bg) >= Ratio(black, bg) {
+			return white, Ratio(white, bg) >= required
+		}
+		return black, Ratio(black, bg) >= required
+	}
+	return best, true
+}
+
+// Profile is a terminal color depth
+type Profile string
+
+const (
+	ProfileTrueColor Profile = "truecolor"
+	Profile256       Profile = "256"
+	Profile16        Profile = "16"
+)
+
+// Downsample returns the color a terminal with the given profile would
+// show for c, along with its palette index (-1 for true color)
+func Downsample(c colorful.Color, profile Profile) (colorful.Color, int) {
+	rgb := termenv.RGBColor(c.Clamped().Hex())
+	switch profile {
+	case Profile256:
+		conv := termenv.ANSI256.Convert(rgb)
+		return termenv.ConvertToRGB(conv), paletteIndex(conv)
+	case Profile16:
+		conv := termenv.ANSI.Convert(rgb)
+		return termenv.ConvertToRGB(conv), paletteIndex(conv)
+	default:
+		return c, -1
+	}
+}
+
+func paletteIndex(c termenv.Color) int {
+	switch v := c.(type) {
+	case termenv.ANSIColor:
+		return int(v)
+	case termenv.ANSI256Color:
+		return int(v)
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,66 @@
+package contrast
+
+import (
+	"testing"
+
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestRatio(t *testing.T) {
+	black, _ := Parse("#000")
+	white, _ := Parse("#FFFFFF")
+	if r := Ratio(black, white); r < 20.99 || r > 21.01 {
+		t.Errorf("black/white ratio should be 21, got %.2f", r)
+	}
+	if r := Ratio(white, white); r != 1 {
+		t.Errorf("identical colors should have ratio 1, got %.2f", r)
+	}
+
+	ansi, err := Parse("15")
+	if err != nil || Ratio(ansi, white) > 1.01 {
+		t.Errorf("ANSI 15 should parse as white, got %v (%v)", ansi.Hex(), err)
+	}
+}
+
+func TestSuggestReachesTarget(t *testing.T) {
+	fg, _ := Parse("#6B6B8D")
+	bg, _ := Parse("#0D0221")
+	s, ok := Suggest(fg, bg, 4.5)
+	if !ok || Ratio(s, bg) < 4.5 {
+		t.Fatalf("suggestion %s should reach 4.5:1, got %.2f", s.Hex(), Ratio(s, bg))
+	}
+	if s.DistanceLab(fg) > 0.2 {
+		t.Errorf("suggestion %s strays too far from %s", s.Hex(), fg.Hex())
+	}
+}
+
+func TestCheckCanvas(t *testing.T) {
+	low := schema.NewComponent(schema.TypeText, "low")
+	low.Text = "hard to read"
+	low.Style.Foreground = "#222222"
+
+	canvas := &schema.Canvas{Components: []schema.Component{low}}
+	report := Check(canvas, styles.Ultraviolet, Options{})
+
+	fails := report.Failures()
+	if len(fails) != 1 || fails[0].ComponentID != low.ID || fails[0].Inherited {
+		t.Fatalf("expected one failure owned by the component, got %+v", fails)
+	}
+	if fails[0].Suggestion == "" {
+		t.Error("failure should carry a suggestion")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]Level{"AA": LevelAA, "aaa": LevelAAA, " aa ": LevelAA} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "A", "AAAA"} {
+		if _, err := ParseLevel(in); err == nil {
+			t.Errorf("ParseLevel(%q) accepted it", in)
+		}
+	}
+}
@@ -129,8 +129,16 @@ func (f *Freezer) IsAvailable() bool {
 	return err == nil
 }
 
-// CaptureFile captures a file to an image
+// CaptureFile captures a file to an image. Without freeze it is drawn
+// natively, highlighted when the config names a language.
 func (f *Freezer) CaptureFile(inputPath, outputPath string) error {
+	if !f.IsAvailable() {
+		data, err := os.ReadFile(inputPath)
+		if err != nil {
+			return err
+		}
+		return f.renderNative(f.highlight(string(data)), outputPath)
+	}
 	args := f.buildArgs(outputPath)
 	args = append(args, inputPath)
 	return f.run(args...)
@@ -138,6 +146,10 @@ func (f *Freezer) CaptureFile(inputPath, outputPath string) error {
 
 // CaptureString captures string content to an image
 func (f *Freezer) CaptureString(content, outputPath string) error {
+	if !f.IsAvailable() {
+		return f.renderNative(content, outputPath)
+	}
+
 	// Write to temp file
 	tmpFile, err := os.CreateTemp("", "freeze-*.txt")
 	if err != nil {
@@ -155,6 +167,13 @@ func (f *Freezer) CaptureString(content, outputPath string) error {
 
 // CaptureCommand captures command output to an image
 func (f *Freezer) CaptureCommand(command, outputPath string) error {
+	if !f.IsAvailable() {
+		out, err := exec.Command("sh", "-c", command).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("command failed: %s: %w", out, err)
+		}
+		return f.renderNative(string(out), outputPath)
+	}
 	args := f.buildArgs(outputPath)
 	args = append(args, "--execute", command)
 	return f.run(args...)
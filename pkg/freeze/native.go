@@ -0,0 +1,337 @@
+// Package freeze - Screenshots rendered in Go, without the freeze binary
+package freeze
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/makeatui/makeatui/pkg/compositor"
+)
+
+// Colors the native renderer uses where the content and the config give
+// none
+const (
+	defaultForeground = "#e0def4"
+	lineNumberColor   = "#6e6a86"
+	defaultBorder     = "#515151"
+)
+
+// macOS window controls: close, minimize, zoom
+var macControls = [3]string{"#ff5f56", "#ffbd2e", "#27c93f"}
+
+// scene is a screenshot laid out in pixels, the same for SVG and PNG
+type scene struct {
+	cfg  *Config
+	buf  *compositor.Buffer
+	font float64 // font size
+	cell float64 // cell width
+	line float64 // line height
+
+	gutter int     // columns of line numbers, 0 for none
+	bar    float64 // height of the title bar, 0 for none
+
+	width, height float64 // the whole image
+	win           rect    // the window, inside the margin and room for its shadow
+	textX, textY  float64 // top left corner of the first cell
+}
+
+type rect struct{ x, y, w, h float64 }
+
+// newScene lays out content, text with SGR escape sequences such as a
+// View(), in a window as cfg describes
+func newScene(content string, cfg *Config) *scene {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	content = strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	content = selectLines(content, cfg.Lines)
+
+	s := &scene{cfg: cfg, buf: compositor.Parse(content), font: cfg.Font.Size}
+	if s.font <= 0 {
+		s.font = 14
+	}
+	s.cell, s.line = s.font*0.6, s.font*1.2
+	if cfg.ShowLines {
+		s.gutter = len(strconv.Itoa(s.buf.Height())) + 2
+	}
+	if cfg.Window.Show {
+		s.bar = s.font * 2.4
+	}
+
+	pad, margin := cfg.Padding, cfg.Margin
+	s.win.w = float64(pad[3]+pad[1]) + float64(s.gutter+s.buf.Width())*s.cell
+	s.win.h = s.bar + float64(pad[0]+pad[2]) + float64(s.buf.Height())*s.line
+
+	// Room for the shadow on each side: its blur, less its offset
+	var left, top, right, bottom float64
+	if sh := cfg.Shadow; sh.Show {
+		blur := float64(sh.Blur)
+		left, right = max(blur-float64(sh.X), 0), max(blur+float64(sh.X), 0)
+		top, bottom = max(blur-float64(sh.Y), 0), max(blur+float64(sh.Y), 0)
+	}
+	s.win.x = float64(margin[3]) + left
+	s.win.y = float64(margin[0]) + top
+	s.width = s.win.x + s.win.w + right + float64(margin[1])
+	s.height = s.win.y + s.win.h + bottom + float64(margin[2])
+	s.textX = s.win.x + float64(pad[3]) + float64(s.gutter)*s.cell
+	s.textY = s.win.y + s.bar + float64(pad[0])
+	return s
+}
+
+// selectLines keeps the lines of content that lines names, such as
+// "1-10" or "5,10,15", counting from 1; empty keeps them all
+func selectLines(content, lines string) string {
+	if strings.TrimSpace(lines) == "" {
+		return content
+	}
+	all := strings.Split(content, "\n")
+	var kept []string
+	for _, part := range strings.Split(lines, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			continue
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(to); err != nil {
+				last = len(all)
+			}
+		}
+		for n := max(first, 1); n <= min(last, len(all)); n++ {
+			kept = append(kept, all[n-1])
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// span is a run of cells on one row styled alike
+type span struct {
+	x, cells int // first column and width in cells
+	text     string
+	attrs    compositor.Attrs
+}
+
+// spans splits row y of the buffer into runs of cells styled alike
+func (s *scene) spans(y int) []span {
+	var spans []span
+	for x := 0; x < s.buf.Width(); x++ {
+		c := s.buf.Cell(x, y)
+		if c.Width == 0 {
+			continue
+		}
+		if n := len(spans); n > 0 && spans[n-1].attrs == c.Attrs() {
+			spans[n-1].text += c.Content
+			spans[n-1].cells += c.Width
+			continue
+		}
+		spans = append(spans, span{x: x, cells: c.Width, text: c.Content, attrs: c.Attrs()})
+	}
+	return spans
+}
+
+// colors resolves the foreground and background of a run of cells,
+// swapped when reversed, with "" for the window background
+func (s *scene) colors(a compositor.Attrs) (fg, bg string) {
+	fg, bg = a.FG, a.BG
+	if fg == "" {
+		fg = defaultForeground
+	}
+	if a.Reverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = s.cfg.Background
+		}
+	}
+	return fg, bg
+}
+
+// RenderSVG draws content, text with SGR escape sequences, as an SVG
+// screenshot with the font, window, padding, border and shadow of cfg
+func RenderSVG(content string, cfg *Config) []byte {
+	s := newScene(content, cfg)
+	cfg = s.cfg
+	var sb strings.Builder
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		num(s.width), num(s.height), num(s.width), num(s.height))
+
+	window := ""
+	if sh := cfg.Shadow; sh.Show {
+		c := parseColor(sh.Color, color.NRGBA{A: 128})
+		fmt.Fprintf(&sb, `<defs><filter id="shadow" x="-50%%" y="-50%%" width="200%%" height="200%%">`+
+			`<feDropShadow dx="%d" dy="%d" stdDeviation="%s" flood-color="#%02x%02x%02x" flood-opacity="%s"/></filter></defs>`+"\n",
+			sh.X, sh.Y, num(float64(sh.Blur)/2), c.R, c.G, c.B, num(float64(c.A)/255))
+		window = ` filter="url(#shadow)"`
+	}
+	if b := cfg.Border; b.Width > 0 {
+		window += fmt.Sprintf(` stroke="%s" stroke-width="%d"`, html.EscapeString(orDefault(b.Color, defaultBorder)), b.Width)
+	}
+	fmt.Fprintf(&sb, `<rect x="%s" y="%s" width="%s" height="%s" rx="%d" fill="%s"%s/>`+"\n",
+		num(s.win.x), num(s.win.y), num(s.win.w), num(s.win.h), cfg.Border.Radius, html.EscapeString(cfg.Background), window)
+
+	family := html.EscapeString(orDefault(cfg.Font.Family, "monospace"))
+	fmt.Fprintf(&sb, `<g font-family="%s, monospace" font-size="%s" xml:space="preserve">`+"\n", family, num(s.font))
+
+	if cfg.Window.Show {
+		cy := s.win.y + s.bar/2
+		if cfg.Window.Controls {
+			for i, c := range s.controls() {
+				if cfg.Window.Style == "macos" || cfg.Window.Style == "" {
+					fmt.Fprintf(&sb, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(c), num(cy), num(s.font*0.4), macControls[i])
+				} else {
+					fmt.Fprintf(&sb, `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+						num(c), num(cy), lineNumberColor, [3]string{"─", "□", "✕"}[i])
+				}
+			}
+		}
+		if cfg.Window.Title != "" {
+			fmt.Fprintf(&sb, `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="central" fill="%s">%s</text>`+"\n",
+				num(s.win.x+s.win.w/2), num(cy), lineNumberColor, html.EscapeString(cfg.Window.Title))
+		}
+	}
+
+	for y := 0; y < s.buf.Height(); y++ {
+		top := s.textY + float64(y)*s.line
+		baseline := top + s.line/2
+		if s.gutter > 0 {
+			fmt.Fprintf(&sb, `<text x="%s" y="%s" text-anchor="end" dominant-baseline="central" fill="%s">%d</text>`+"\n",
+				num(s.textX-2*s.cell), num(baseline), lineNumberColor, y+1)
+		}
+		for _, sp := range s.spans(y) {
+			fg, bg := s.colors(sp.attrs)
+			x, w := s.textX+float64(sp.x)*s.cell, float64(sp.cells)*s.cell
+			if bg != "" {
+				fmt.Fprintf(&sb, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n", num(x), num(top), num(w), num(s.line), bg)
+			}
+			if strings.TrimSpace(sp.text) == "" && !sp.attrs.Underline && !sp.attrs.Strike {
+				continue
+			}
+			style := ""
+			if sp.attrs.Bold {
+				style += ` font-weight="bold"`
+			}
+			if sp.attrs.Italic {
+				style += ` font-style="italic"`
+			}
+			if sp.attrs.Faint {
+				style += ` fill-opacity="0.6"`
+			}
+			var deco []string
+			if sp.attrs.Underline {
+				deco = append(deco, "underline")
+			}
+			if sp.attrs.Strike {
+				deco = append(deco, "line-through")
+			}
+			if len(deco) > 0 {
+				style += ` text-decoration="` + strings.Join(deco, " ") + `"`
+			}
+			// textLength keeps every run on the grid whatever the font's widths
+			fmt.Fprintf(&sb, `<text x="%s" y="%s" dominant-baseline="central" textLength="%s" lengthAdjust="spacingAndGlyphs" fill="%s"%s>%s</text>`+"\n",
+				num(x), num(baseline), num(w), fg, style, html.EscapeString(sp.text))
+		}
+	}
+	sb.WriteString("</g>\n</svg>\n")
+	return []byte(sb.String())
+}
+
+// controls returns the centers of the three window controls: on the
+// left for macOS, on the right otherwise
+func (s *scene) controls() [3]float64 {
+	gap := s.font * 1.4
+	if s.cfg.Window.Style == "macos" || s.cfg.Window.Style == "" {
+		first := s.win.x + s.font*1.2
+		return [3]float64{first, first + gap, first + 2*gap}
+	}
+	last := s.win.x + s.win.w - s.font*1.2
+	return [3]float64{last - 2*gap, last - gap, last}
+}
+
+// parseColor reads "#rgb", "#rrggbb", "rgb(r,g,b)" or "rgba(r,g,b,a)",
+// or returns def
+func parseColor(s string, def color.NRGBA) color.NRGBA {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+		}
+		return def
+	}
+	start, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if start < 0 || end < start {
+		return def
+	}
+	parts := strings.Split(s[start+1:end], ",")
+	if len(parts) < 3 {
+		return def
+	}
+	c := color.NRGBA{A: 255}
+	for i, p := range parts[:min(len(parts), 4)] {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return def
+		}
+		if i == 3 {
+			c.A = uint8(min(max(v, 0), 1) * 255)
+			continue
+		}
+		channel := uint8(min(max(v, 0), 255))
+		switch i {
+		case 0:
+			c.R = channel
+		case 1:
+			c.G = channel
+		case 2:
+			c.B = channel
+		}
+	}
+	return c
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// renderNative writes content to output as SVG or PNG, by its extension
+func (f *Freezer) renderNative(content, output string) error {
+	var data []byte
+	switch ext := strings.ToLower(filepath.Ext(output)); ext {
+	case ".svg":
+		data = RenderSVG(content, f.config)
+	case ".png":
+		var err error
+		if data, err = RenderPNG(content, f.config); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("freeze is not installed, and without it only .svg and .png screenshots can be made, not %s", ext)
+	}
+	return os.WriteFile(output, data, 0644)
+}
+
+// highlight colors source code in the config's language and theme, as
+// freeze would, leaving it plain when either is unknown
+func (f *Freezer) highlight(code string) string {
+	if f.config.Language == "" {
+		return code
+	}
+	var sb strings.Builder
+	if err := quick.Highlight(&sb, code, f.config.Language, "terminal16m", f.config.Theme); err != nil {
+		return code
+	}
+	return sb.String()
+}
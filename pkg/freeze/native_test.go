@@ -0,0 +1,41 @@
+package freeze
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRenderNative(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Shadow.Show = false
+	cfg.ShowLines = false
+	content := "\x1b[38;2;255;0;0;48;2;0;0;255mHi\x1b[0m there\n┌─┐"
+
+	svg := string(RenderSVG(content, cfg))
+	for _, want := range []string{`fill="#ff0000"`, `fill="#0000ff"`, `fill="#ff5f56"`, ">Hi</text>", ">MakeaTUI</text>"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %s", want)
+		}
+	}
+
+	data, err := RenderPNG(content, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newScene(content, cfg)
+	if b := img.Bounds(); b.Dx() != px(s.width) || b.Dy() != px(s.height) {
+		t.Errorf("PNG is %v, want %d×%d", b, px(s.width), px(s.height))
+	}
+	// The top left corner of the first cell is its blue background
+	x, y := px(s.textX)+1, px(s.textY)+1
+	if c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA); c != (color.NRGBA{B: 255, A: 255}) {
+		t.Errorf("cell background = %v, want blue", c)
+	}
+}
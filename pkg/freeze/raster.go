@@ -0,0 +1,377 @@
+// Package freeze - PNG screenshots rasterized in Go
+package freeze
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// pngScale is the pixels a PNG has for each unit of the SVG, so text is
+// sharp on high density screens
+const pngScale = 2
+
+// goMono is the Go Mono family, regular, bold, italic and bold italic:
+// PNGs have no system fonts to pick Config.Font.Family from
+var goMono = sync.OnceValues(func() ([4]*opentype.Font, error) {
+	var fonts [4]*opentype.Font
+	for i, ttf := range [][]byte{gomono.TTF, gomonobold.TTF, gomonoitalic.TTF, gomonobolditalic.TTF} {
+		f, err := opentype.Parse(ttf)
+		if err != nil {
+			return fonts, err
+		}
+		fonts[i] = f
+	}
+	return fonts, nil
+})
+
+// raster draws a scene at pngScale
+type raster struct {
+	*scene
+	img   *image.NRGBA
+	faces [4]font.Face // regular, bold, italic, bold italic
+}
+
+// RenderPNG draws content, text with SGR escape sequences, as a PNG
+// screenshot laid out as RenderSVG lays it out, in Go Mono
+func RenderPNG(content string, cfg *Config) ([]byte, error) {
+	s := newScene(content, cfg)
+	fonts, err := goMono()
+	if err != nil {
+		return nil, err
+	}
+	r := &raster{scene: s, img: image.NewNRGBA(image.Rect(0, 0, px(s.width), px(s.height)))}
+	for i, f := range fonts {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: s.font * pngScale, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, err
+		}
+		defer face.Close()
+		r.faces[i] = face
+	}
+
+	r.window()
+	if s.cfg.Window.Show {
+		r.titleBar()
+	}
+	for y := 0; y < s.buf.Height(); y++ {
+		r.row(y)
+	}
+
+	var out bytes.Buffer
+	if err := png.Encode(&out, r.img); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// px converts a length of the scene to pixels
+func px(v float64) int {
+	return int(math.Round(v * pngScale))
+}
+
+// window draws the shadow, the border and the background of the window
+func (r *raster) window() {
+	cfg := r.cfg
+	win := r.win
+	radius := float64(cfg.Border.Radius)
+
+	if sh := cfg.Shadow; sh.Show {
+		mask := image.NewAlpha(r.img.Bounds())
+		shadow := win
+		shadow.x += float64(sh.X)
+		shadow.y += float64(sh.Y)
+		fill(mask, image.Opaque, func(z *vector.Rasterizer) { roundRect(z, shadow, radius) })
+		blur(mask, px(float64(sh.Blur)/2))
+		c := parseColor(sh.Color, color.NRGBA{A: 128})
+		draw.DrawMask(r.img, r.img.Bounds(), image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	bg := parseColor(cfg.Background, color.NRGBA{R: 0x1e, G: 0x1e, B: 0x2e, A: 255})
+	if b := cfg.Border; b.Width > 0 {
+		fill(r.img, parseColor(orDefault(b.Color, defaultBorder), color.NRGBA{A: 255}), func(z *vector.Rasterizer) { roundRect(z, win, radius) })
+		w := float64(b.Width)
+		win = rect{win.x + w, win.y + w, win.w - 2*w, win.h - 2*w}
+		radius = max(radius-w, 0)
+	}
+	fill(r.img, bg, func(z *vector.Rasterizer) { roundRect(z, win, radius) })
+}
+
+// titleBar draws the window controls and the title
+func (r *raster) titleBar() {
+	cfg := r.cfg
+	cy := r.win.y + r.bar/2
+	muted := parseColor(lineNumberColor, color.NRGBA{A: 255})
+	if cfg.Window.Controls {
+		size := r.font * 0.4
+		for i, cx := range r.controls() {
+			if cfg.Window.Style == "macos" || cfg.Window.Style == "" {
+				dot := rect{cx - size, cy - size, 2 * size, 2 * size}
+				fill(r.img, parseColor(macControls[i], muted), func(z *vector.Rasterizer) { roundRect(z, dot, size) })
+				continue
+			}
+			// Minimize, maximize and close, drawn as the glyphs of the SVG
+			half, stroke := size*0.8, max(r.font/14, 1)
+			fill(r.img, muted, func(z *vector.Rasterizer) {
+				switch i {
+				case 0:
+					line(z, cx-half, cy, cx+half, cy, stroke)
+				case 1:
+					line(z, cx-half, cy-half, cx+half, cy-half, stroke)
+					line(z, cx+half, cy-half, cx+half, cy+half, stroke)
+					line(z, cx+half, cy+half, cx-half, cy+half, stroke)
+					line(z, cx-half, cy+half, cx-half, cy-half, stroke)
+				case 2:
+					line(z, cx-half, cy-half, cx+half, cy+half, stroke)
+					line(z, cx-half, cy+half, cx+half, cy-half, stroke)
+				}
+			})
+		}
+	}
+	if cfg.Window.Title != "" {
+		face := r.faces[0]
+		width := font.MeasureString(face, cfg.Window.Title).Round()
+		r.text(cfg.Window.Title, px(r.win.x+r.win.w/2)-width/2, r.baseline(cy-r.line/2), face, muted)
+	}
+}
+
+// row draws line y of the content: its number, the backgrounds of its
+// cells and their text
+func (r *raster) row(y int) {
+	top := r.textY + float64(y)*r.line
+	y0, y1 := px(top), px(top+r.line)
+	baseline := r.baseline(top)
+
+	if r.gutter > 0 {
+		label := strconv.Itoa(y + 1)
+		width := font.MeasureString(r.faces[0], label).Round()
+		r.text(label, px(r.textX-2*r.cell)-width, baseline, r.faces[0], parseColor(lineNumberColor, color.NRGBA{A: 255}))
+	}
+
+	for _, sp := range r.spans(y) {
+		fg, bg := r.colors(sp.attrs)
+		x0, x1 := r.column(sp.x), r.column(sp.x+sp.cells)
+		if bg != "" {
+			draw.Draw(r.img, image.Rect(x0, y0, x1, y1), image.NewUniform(parseColor(bg, color.NRGBA{})), image.Point{}, draw.Over)
+		}
+		c := parseColor(fg, color.NRGBA{R: 0xe0, G: 0xde, B: 0xf4, A: 255})
+		if sp.attrs.Faint {
+			c.A = uint8(float64(c.A) * 0.6)
+		}
+		face := r.faces[0]
+		switch {
+		case sp.attrs.Bold && sp.attrs.Italic:
+			face = r.faces[3]
+		case sp.attrs.Bold:
+			face = r.faces[1]
+		case sp.attrs.Italic:
+			face = r.faces[2]
+		}
+
+		for x := sp.x; x < sp.x+sp.cells; {
+			cell := r.buf.Cell(x, y)
+			width := max(cell.Width, 1)
+			cx0, cx1 := r.column(x), r.column(x+width)
+			if ch := []rune(cell.Content); len(ch) > 0 && ch[0] != ' ' &&
+				!boxChar(r.img, ch[0], image.Rect(cx0, y0, cx1, y1), c, px(r.font/14)) {
+				r.text(cell.Content, cx0, baseline, face, c)
+			}
+			x += width
+		}
+
+		stroke := max(px(r.font/14), 1)
+		if sp.attrs.Underline {
+			draw.Draw(r.img, image.Rect(x0, baseline+stroke, x1, baseline+2*stroke), image.NewUniform(c), image.Point{}, draw.Over)
+		}
+		if sp.attrs.Strike {
+			mid := (y0 + y1) / 2
+			draw.Draw(r.img, image.Rect(x0, mid, x1, mid+stroke), image.NewUniform(c), image.Point{}, draw.Over)
+		}
+	}
+}
+
+// column is the left edge of column x in pixels. Every edge is rounded
+// alike, so neighbouring cells meet without gaps.
+func (r *raster) column(x int) int {
+	return px(r.textX + float64(x)*r.cell)
+}
+
+// baseline is where text sits in the line starting at top
+func (r *raster) baseline(top float64) int {
+	m := r.faces[0].Metrics()
+	return px(top) + (px(r.line)+m.Ascent.Round()-m.Descent.Round())/2
+}
+
+func (r *raster) text(s string, x, baseline int, face font.Face, c color.Color) {
+	d := font.Drawer{Dst: r.img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, baseline)}
+	d.DrawString(s)
+}
+
+// fill draws the path traced by path in c
+func fill(dst draw.Image, c color.Color, path func(z *vector.Rasterizer)) {
+	b := dst.Bounds()
+	z := vector.NewRasterizer(b.Dx(), b.Dy())
+	path(z)
+	z.Draw(dst, b, image.NewUniform(c), image.Point{})
+}
+
+// roundRect traces r, scaled to pixels, with corners of the radius
+func roundRect(z *vector.Rasterizer, r rect, radius float64) {
+	x0, y0 := float32(r.x*pngScale), float32(r.y*pngScale)
+	x1, y1 := float32((r.x+r.w)*pngScale), float32((r.y+r.h)*pngScale)
+	rad := float32(min(radius, r.w/2, r.h/2) * pngScale)
+	k := rad * 0.4477 // 1 - the control point distance of a quarter circle
+	z.MoveTo(x0+rad, y0)
+	z.LineTo(x1-rad, y0)
+	z.CubeTo(x1-k, y0, x1, y0+k, x1, y0+rad)
+	z.LineTo(x1, y1-rad)
+	z.CubeTo(x1, y1-k, x1-k, y1, x1-rad, y1)
+	z.LineTo(x0+rad, y1)
+	z.CubeTo(x0+k, y1, x0, y1-k, x0, y1-rad)
+	z.LineTo(x0, y0+rad)
+	z.CubeTo(x0, y0+k, x0+k, y0, x0+rad, y0)
+	z.ClosePath()
+}
+
+// line traces a line of the given width, in units of the scene
+func line(z *vector.Rasterizer, x0, y0, x1, y1, width float64) {
+	dx, dy := x1-x0, y1-y0
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	pt := func(x, y float64) (float32, float32) { return float32(x * pngScale), float32(y * pngScale) }
+	z.MoveTo(pt(x0+nx, y0+ny))
+	z.LineTo(pt(x1+nx, y1+ny))
+	z.LineTo(pt(x1-nx, y1-ny))
+	z.LineTo(pt(x0-nx, y0-ny))
+	z.ClosePath()
+}
+
+// blur softens mask with three passes of a box blur of the radius each
+// way, close to a gaussian blur
+func blur(mask *image.Alpha, radius int) {
+	if radius <= 0 {
+		return
+	}
+	w, h := mask.Rect.Dx(), mask.Rect.Dy()
+	tmp := make([]uint8, max(w, h))
+	pass := func(n int, at func(i int) *uint8) {
+		sum := 0
+		for i := -radius; i < radius; i++ {
+			if i >= 0 && i < n {
+				sum += int(*at(i))
+			}
+		}
+		for i := 0; i < n; i++ {
+			if j := i + radius; j < n {
+				sum += int(*at(j))
+			}
+			tmp[i] = uint8(sum / (2*radius + 1))
+			if j := i - radius; j >= 0 {
+				sum -= int(*at(j))
+			}
+		}
+		for i := 0; i < n; i++ {
+			*at(i) = tmp[i]
+		}
+	}
+	for range 3 {
+		for y := 0; y < h; y++ {
+			pass(w, func(i int) *uint8 { return &mask.Pix[y*mask.Stride+i] })
+		}
+		for x := 0; x < w; x++ {
+			pass(h, func(i int) *uint8 { return &mask.Pix[i*mask.Stride+x] })
+		}
+	}
+}
+
+// boxLines are the box drawing characters drawn as lines rather than
+// from the font, so that they join across cells: the weight of the
+// line to the top, right, bottom and left, 1 light, 2 heavy, 3 double
+var boxLines = map[rune][4]uint8{
+	'─': {0, 1, 0, 1}, '│': {1, 0, 1, 0}, '┌': {0, 1, 1, 0}, '┐': {0, 0, 1, 1},
+	'└': {1, 1, 0, 0}, '┘': {1, 0, 0, 1}, '├': {1, 1, 1, 0}, '┤': {1, 0, 1, 1},
+	'┬': {0, 1, 1, 1}, '┴': {1, 1, 0, 1}, '┼': {1, 1, 1, 1},
+	'╭': {0, 1, 1, 0}, '╮': {0, 0, 1, 1}, '╯': {1, 0, 0, 1}, '╰': {1, 1, 0, 0},
+	'━': {0, 2, 0, 2}, '┃': {2, 0, 2, 0}, '┏': {0, 2, 2, 0}, '┓': {0, 0, 2, 2},
+	'┗': {2, 2, 0, 0}, '┛': {2, 0, 0, 2}, '┣': {2, 2, 2, 0}, '┫': {2, 0, 2, 2},
+	'┳': {0, 2, 2, 2}, '┻': {2, 2, 0, 2}, '╋': {2, 2, 2, 2},
+	'═': {0, 3, 0, 3}, '║': {3, 0, 3, 0}, '╔': {0, 3, 3, 0}, '╗': {0, 0, 3, 3},
+	'╚': {3, 3, 0, 0}, '╝': {3, 0, 0, 3}, '╠': {3, 3, 3, 0}, '╣': {3, 0, 3, 3},
+	'╦': {0, 3, 3, 3}, '╩': {3, 3, 0, 3}, '╬': {3, 3, 3, 3},
+}
+
+// boxChar draws a box drawing or block character filling the cell r,
+// with lines stroke pixels wide, and reports whether it was one
+func boxChar(dst draw.Image, ch rune, r image.Rectangle, c color.NRGBA, stroke int) bool {
+	src := image.NewUniform(c)
+	rectangle := func(x0, y0, x1, y1 int) {
+		draw.Draw(dst, image.Rect(x0, y0, x1, y1), src, image.Point{}, draw.Over)
+	}
+
+	if weights, ok := boxLines[ch]; ok {
+		t := max(stroke, 1)
+		cx, cy := (r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2
+		for side, weight := range weights {
+			if weight == 0 {
+				continue
+			}
+			width, offsets := t, []int{0}
+			switch weight {
+			case 2:
+				width = 2 * t
+			case 3:
+				offsets = []int{-t, t}
+			}
+			for _, d := range offsets {
+				lo, hi := d-width/2, d-width/2+width
+				switch side {
+				case 0:
+					rectangle(cx+lo, r.Min.Y, cx+hi, cy+hi)
+				case 1:
+					rectangle(cx+lo, cy+lo, r.Max.X, cy+hi)
+				case 2:
+					rectangle(cx+lo, cy+lo, cx+hi, r.Max.Y)
+				case 3:
+					rectangle(r.Min.X, cy+lo, cx+hi, cy+hi)
+				}
+			}
+		}
+		return true
+	}
+
+	w, h := r.Dx(), r.Dy()
+	switch {
+	case ch == '▀':
+		rectangle(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+h/2)
+	case ch == '▐':
+		rectangle(r.Min.X+w/2, r.Min.Y, r.Max.X, r.Max.Y)
+	case ch >= '▁' && ch <= '█': // lower eighths, to the full block
+		rectangle(r.Min.X, r.Max.Y-h*int(ch-'▁'+1)/8, r.Max.X, r.Max.Y)
+	case ch >= '▉' && ch <= '▏': // left eighths, seven down to one
+		rectangle(r.Min.X, r.Min.Y, r.Min.X+w*int('▏'-ch+1)/8, r.Max.Y)
+	case ch >= '░' && ch <= '▓': // light, medium and dark shade
+		shade := c
+		shade.A = uint8(int(c.A) * int(ch-'░'+1) / 4)
+		draw.Draw(dst, r, image.NewUniform(shade), image.Point{}, draw.Over)
+	default:
+		return false
+	}
+	return true
+}
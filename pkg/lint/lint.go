@@ -0,0 +1,211 @@
+// Package lint checks canvas designs for common layout mistakes
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/makeatui/makeatui/pkg/contrast"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Severity indicates how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Diagnostic is a single problem found by a rule
+type Diagnostic struct {
+	Rule        string   `json:"rule"`
+	Severity    Severity `json:"severity"`
+	ComponentID string   `json:"component_id,omitempty"`
+	Component   string   `json:"component,omitempty"`
+	Message     string   `json:"message"`
+	Fix         string   `json:"fix,omitempty"`
+}
+
+// String formats the diagnostic on a single line
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("%s [%s]", d.Severity, d.Rule)
+	if d.Component != "" {
+		s += " " + d.Component
+	}
+	s += ": " + d.Message
+	if d.Fix != "" {
+		s += " (fix: " + d.Fix + ")"
+	}
+	return s
+}
+
+// RuleFunc inspects a canvas and reports diagnostics.
+// Severity is filled in by the linter if left empty.
+type RuleFunc func(canvas *schema.Canvas, cfg Config) []Diagnostic
+
+// Rule is a named lint check
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       RuleFunc
+}
+
+// RuleConfig configures a single rule
+type RuleConfig struct {
+	Disabled bool     `json:"disabled,omitempty"`
+	Severity Severity `json:"severity,omitempty"`
+}
+
+// Config controls which rules run and how
+type Config struct {
+	Rules map[string]RuleConfig `json:"rules,omitempty"`
+
+	// AllowContainment permits a component to sit entirely inside
+	// another one (e.g. a progress bar inside a card) without being
+	// reported as an overlap.
+	AllowContainment bool `json:"allow_containment"`
+
+	// ContrastLevel is the WCAG level for the contrast rule (AA or AAA)
+	ContrastLevel contrast.Level `json:"contrast_level,omitempty"`
+
+	// DefaultWidth and DefaultHeight are used when the canvas has no size
+	DefaultWidth  int `json:"default_width,omitempty"`
+	DefaultHeight int `json:"default_height,omitempty"`
+}
+
+// DefaultConfig returns the default lint configuration
+func DefaultConfig() Config {
+	return Config{
+		Rules:            map[string]RuleConfig{},
+		AllowContainment: true,
+		ContrastLevel:    contrast.LevelAA,
+		DefaultWidth:     80,
+		DefaultHeight:    24,
+	}
+}
+
+// LoadConfig reads a JSON config file on top of the defaults
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DefaultConfig(), err
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig reads a JSON config over DefaultConfig, so fields it
+// leaves out keep their defaults
+func ParseConfig(data []byte) (Config, error) {
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.Rules == nil {
+		cfg.Rules = map[string]RuleConfig{}
+	}
+	level, err := contrast.ParseLevel(string(cfg.ContrastLevel))
+	if err != nil {
+		return cfg, fmt.Errorf("contrast_level: %w", err)
+	}
+	cfg.ContrastLevel = level
+	return cfg, nil
+}
+
+// Linter runs a set of rules over a canvas
+type Linter struct {
+	rules  []Rule
+	config Config
+}
+
+// New creates a linter with the built-in rules
+func New(cfg Config) *Linter {
+	l := &Linter{config: cfg}
+	for _, r := range BuiltinRules() {
+		l.Register(r)
+	}
+	return l
+}
+
+// Register adds a rule, replacing any rule with the same name
+func (l *Linter) Register(rule Rule) {
+	for i := range l.rules {
+		if l.rules[i].Name == rule.Name {
+			l.rules[i] = rule
+			return
+		}
+	}
+	l.rules = append(l.rules, rule)
+}
+
+// Rules returns the registered rules
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Config returns the linter configuration
+func (l *Linter) Config() Config {
+	return l.config
+}
+
+// Run lints the canvas. Diagnostics are sorted by severity, then rule.
+func (l *Linter) Run(canvas *schema.Canvas) []Diagnostic {
+	var result []Diagnostic
+	for _, rule := range l.rules {
+		rc := l.config.Rules[rule.Name]
+		if rc.Disabled {
+			continue
+		}
+		severity := rule.Severity
+		if rc.Severity != "" {
+			severity = rc.Severity
+		}
+
+		for _, d := range rule.Check(canvas, l.config) {
+			d.Rule = rule.Name
+			if rc.Severity != "" || d.Severity == "" {
+				d.Severity = severity
+			}
+			result = append(result, d)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Severity.rank() != result[j].Severity.rank() {
+			return result[i].Severity.rank() < result[j].Severity.rank()
+		}
+		return result[i].Rule < result[j].Rule
+	})
+	return result
+}
+
+// Lint runs the built-in rules with the default config
+func Lint(canvas *schema.Canvas) []Diagnostic {
+	return New(DefaultConfig()).Run(canvas)
+}
+
+// Count returns the number of diagnostics with the given severity
+func Count(diags []Diagnostic, severity Severity) int {
+	n := 0
+	for _, d := range diags {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
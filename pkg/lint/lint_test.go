@@ -0,0 +1,170 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/contrast"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func box(id, name string, x, y, w, h int) schema.Component {
+	c := schema.NewComponent(schema.TypeBox, name)
+	c.ID = id
+	c.Position = schema.Position{X: x, Y: y}
+	c.Size = schema.Size{Width: w, Height: h}
+	return c
+}
+
+func rules(diags []Diagnostic) map[string]int {
+	result := map[string]int{}
+	for _, d := range diags {
+		result[d.Rule]++
+	}
+	return result
+}
+
+func TestBuiltinRules(t *testing.T) {
+	long := box("c", "title", 0, 10, 10, 3)
+	long.Text = "much too long for this box"
+
+	bad := box("d", "a", 40, 10, 10, 3)
+	bad.Style.Foreground = "purple"
+
+	canvas := &schema.Canvas{
+		Width:  80,
+		Height: 24,
+		Components: []schema.Component{
+			box("a", "a", 0, 0, 20, 5),
+			box("b", "b", 10, 2, 20, 5),       // partial overlap with a
+			box("inner", "inner", 1, 1, 5, 2), // contained in a
+			long,
+			bad,                          // duplicate name "a"
+			box("e", "e", 70, 20, 20, 5), // out of bounds
+			box("f", "f", 50, 0, 0, 3),   // zero size
+		},
+	}
+
+	got := rules(Lint(canvas))
+	want := map[string]int{
+		RuleOverlap:       1,
+		RuleTextOverflow:  1,
+		RuleInvalidColor:  1,
+		RuleDuplicateName: 1,
+		RuleOutOfBounds:   1,
+		RuleZeroSize:      1,
+	}
+	for rule, n := range want {
+		if got[rule] != n {
+			t.Errorf("%s: expected %d diagnostics, got %d", rule, n, got[rule])
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	canvas := &schema.Canvas{
+		Components: []schema.Component{
+			box("a", "a", 0, 0, 20, 5),
+			box("b", "b", 10, 2, 20, 5),
+		},
+	}
+
+	cfg := DefaultConfig()
+	cfg.Rules[RuleOverlap] = RuleConfig{Severity: SeverityError}
+	diags := New(cfg).Run(canvas)
+	// The default theme's border fails contrast on its background, a
+	// warning that comes with any design left on that theme
+	if got := rules(diags); len(diags) != 2 || got[RuleOverlap] != 1 || got[RuleContrast] != 1 {
+		t.Fatalf("expected one overlap and one contrast diagnostic, got %v", diags)
+	}
+	for _, d := range diags {
+		if want := map[string]Severity{RuleOverlap: SeverityError, RuleContrast: SeverityWarning}[d.Rule]; d.Severity != want {
+			t.Errorf("%s: expected %s, got %s", d.Rule, want, d.Severity)
+		}
+		// The theme is named once, as what the warning is about
+		if d.Rule == RuleContrast && strings.Count(d.String(), "theme ") != 1 {
+			t.Errorf("theme named more than once: %s", d)
+		}
+	}
+
+	cfg.Rules[RuleOverlap] = RuleConfig{Disabled: true}
+	if diags := New(cfg).Run(canvas); len(diags) != 1 || diags[0].Rule != RuleContrast {
+		t.Errorf("expected only the contrast warning with overlap disabled, got %v", diags)
+	}
+}
+
+func TestContrastRule(t *testing.T) {
+	text := func(id, fg string, y int) schema.Component {
+		c := schema.NewComponent(schema.TypeText, id)
+		c.ID, c.Text = id, "label"
+		c.Position, c.Size = schema.Position{Y: y}, schema.Size{Width: 10, Height: 1}
+		c.Style.Foreground, c.Style.Background = fg, "#ffffff"
+		return c
+	}
+	grey := text("grey", "#767676", 0)   // 4.54:1
+	faint := text("faint", "#aaaaaa", 2) // 2.32:1
+	canvas := &schema.Canvas{Width: 80, Height: 24, Components: []schema.Component{grey, faint}}
+
+	failing := func(level contrast.Level) []string {
+		cfg := DefaultConfig()
+		cfg.ContrastLevel = level
+		var ids []string
+		for _, d := range New(cfg).Run(canvas) {
+			if d.Rule == RuleContrast && !strings.HasPrefix(d.Component, "theme ") {
+				if d.Fix == "" {
+					t.Errorf("%s: no fix suggested for %q", level, d.Message)
+				}
+				ids = append(ids, d.ComponentID)
+			}
+		}
+		return ids
+	}
+	if got := failing(contrast.LevelAA); !slices.Equal(got, []string{"faint"}) {
+		t.Errorf("AA: expected faint to fail, got %v", got)
+	}
+	if got := failing(contrast.LevelAAA); !slices.Equal(got, []string{"grey", "faint"}) {
+		t.Errorf("AAA: expected grey and faint to fail, got %v", got)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	panel := box("p", "panel", 40, 2, 20, 10)
+	panel.Children = []schema.Component{
+		box("x", "x", 1, 1, 8, 3),
+		box("y", "y", 5, 2, 8, 3),  // overlaps x
+		box("z", "z", 15, 8, 8, 3), // sticks out of the panel
+		box("w", "w", 1, 5, 30, 2), // wider than the panel
+	}
+	canvas := &schema.Canvas{Width: 80, Height: 24, Components: []schema.Component{
+		panel,
+		box("q", "q", 0, 0, 20, 5), // clear of the panel and its children
+	}}
+
+	var got []string
+	for _, d := range Lint(canvas) {
+		if d.Rule == RuleOverlap || d.Rule == RuleOutOfBounds {
+			got = append(got, d.Rule+" "+d.ComponentID+": "+d.Message)
+		}
+	}
+	want := []string{
+		RuleOverlap + ` y: overlaps "x"`,
+		RuleOutOfBounds + ` z: spans (15, 8)-(23, 11), outside the 20x10 container "panel"`,
+		RuleOutOfBounds + ` w: size 30x2 does not fit the 20x10 container "panel"`,
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"contrast_level": "aaa"}`))
+	if err != nil || cfg.ContrastLevel != contrast.LevelAAA || !cfg.AllowContainment {
+		t.Errorf("got %+v, %v; want AAA over the defaults", cfg, err)
+	}
+	if _, err := ParseConfig([]byte(`{"contrast_level": "A"}`)); err == nil {
+		t.Error("accepted contrast level A")
+	}
+}
@@ -0,0 +1,422 @@
+// Package lint - Built-in rules
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/contrast"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Rule names
+const (
+	RuleOverlap       = "overlap"
+	RuleOutOfBounds   = "out-of-bounds"
+	RuleTextOverflow  = "text-overflow"
+	RuleDuplicateName = "duplicate-name"
+	RuleDuplicateID   = "duplicate-id"
+	RuleZeroSize      = "zero-size"
+	RuleInvalidColor  = "invalid-color"
+	RuleContrast      = "contrast"
+)
+
+// BuiltinRules returns the default rule set
+func BuiltinRules() []Rule {
+	return []Rule{
+		{
+			Name:        RuleOverlap,
+			Description: "Components partially overlap each other",
+			Severity:    SeverityWarning,
+			Check:       checkOverlap,
+		},
+		{
+			Name:        RuleOutOfBounds,
+			Description: "Component extends beyond the canvas",
+			Severity:    SeverityError,
+			Check:       checkOutOfBounds,
+		},
+		{
+			Name:        RuleTextOverflow,
+			Description: "Text is wider or taller than the component's content area",
+			Severity:    SeverityWarning,
+			Check:       checkTextOverflow,
+		},
+		{
+			Name:        RuleDuplicateName,
+			Description: "Several components share the same name",
+			Severity:    SeverityWarning,
+			Check:       checkDuplicateNames,
+		},
+		{
+			Name:        RuleDuplicateID,
+			Description: "Several components share the same ID",
+			Severity:    SeverityError,
+			Check:       checkDuplicateIDs,
+		},
+		{
+			Name:        RuleZeroSize,
+			Description: "Component has no width or height",
+			Severity:    SeverityError,
+			Check:       checkZeroSize,
+		},
+		{
+			Name:        RuleInvalidColor,
+			Description: "Color is not a hex value, ANSI color number or theme token",
+			Severity:    SeverityError,
+			Check:       checkInvalidColors,
+		},
+		{
+			Name:        RuleContrast,
+			Description: "Foreground and background fail WCAG contrast",
+			Severity:    SeverityWarning,
+			Check:       checkContrast,
+		},
+	}
+}
+
+// Rect is a component's footprint on the canvas
+type Rect struct {
+	X, Y, W, H int
+}
+
+// Right returns the first column past the rectangle
+func (r Rect) Right() int { return r.X + r.W }
+
+// Bottom returns the first row past the rectangle
+func (r Rect) Bottom() int { return r.Y + r.H }
+
+// Intersects reports whether two rectangles share any cell
+func (r Rect) Intersects(o Rect) bool {
+	return r.X < o.Right() && o.X < r.Right() && r.Y < o.Bottom() && o.Y < r.Bottom()
+}
+
+// Contains reports whether o lies entirely inside r
+func (r Rect) Contains(o Rect) bool {
+	return o.X >= r.X && o.Y >= r.Y && o.Right() <= r.Right() && o.Bottom() <= r.Bottom()
+}
+
+// Bounds returns the area a component occupies. Text components are
+// as tall as their content; everything else uses the schema size.
+func Bounds(c schema.Component) Rect {
+	r := Rect{X: c.Position.X, Y: c.Position.Y, W: c.Size.Width, H: c.Size.Height}
+	if c.Type == schema.TypeText {
+		r.H = strings.Count(c.Text, "\n") + 1
+		if r.W <= 0 {
+			r.W = lipgloss.Width(c.Text)
+		}
+	}
+	return r
+}
+
+func canvasSize(canvas *schema.Canvas, cfg Config) (int, int) {
+	w, h := canvas.Width, canvas.Height
+	if w <= 0 {
+		w = cfg.DefaultWidth
+	}
+	if h <= 0 {
+		h = cfg.DefaultHeight
+	}
+	return w, h
+}
+
+func label(c schema.Component) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.ID
+}
+
+func diag(c schema.Component, msg, fix string) Diagnostic {
+	return Diagnostic{ComponentID: c.ID, Component: label(c), Message: msg, Fix: fix}
+}
+
+// walk visits every component, including children
+func walk(comps []schema.Component, fn func(schema.Component)) {
+	for _, c := range comps {
+		fn(c)
+		walk(c.Children, fn)
+	}
+}
+
+func checkOverlap(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	return overlaps(canvas.Components, cfg)
+}
+
+// overlaps reports the siblings that overlap, then does the same among
+// the children of each container. Children are placed relative to their
+// container, so siblings compare as they are; a child sticking out of
+// its container is left to the out-of-bounds rule.
+func overlaps(comps []schema.Component, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	for i := 0; i < len(comps); i++ {
+		a := Bounds(comps[i])
+		for j := i + 1; j < len(comps); j++ {
+			b := Bounds(comps[j])
+			if !a.Intersects(b) {
+				continue
+			}
+			if cfg.AllowContainment && (a.Contains(b) || b.Contains(a)) {
+				continue
+			}
+			result = append(result, diag(comps[j],
+				fmt.Sprintf("overlaps %q", label(comps[i])),
+				overlapFix(a, b)))
+		}
+	}
+	for _, c := range comps {
+		result = append(result, overlaps(c.Children, cfg)...)
+	}
+	return result
+}
+
+// overlapFix suggests the smallest move of b that clears a
+func overlapFix(a, b Rect) string {
+	right := a.Right() - b.X
+	down := a.Bottom() - b.Y
+	if right <= down {
+		return fmt.Sprintf("move to (%d, %d)", a.Right(), b.Y)
+	}
+	return fmt.Sprintf("move to (%d, %d)", b.X, a.Bottom())
+}
+
+func checkOutOfBounds(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	w, h := canvasSize(canvas, cfg)
+	return outOfBounds(canvas.Components, w, h, fmt.Sprintf("the %dx%d canvas", w, h))
+}
+
+// outOfBounds reports the components that do not fit in area, w×h, and
+// then the children that do not fit in their container
+func outOfBounds(comps []schema.Component, w, h int, area string) []Diagnostic {
+	var result []Diagnostic
+	for _, c := range comps {
+		r := Bounds(c)
+		if r.X >= 0 && r.Y >= 0 && r.Right() <= w && r.Bottom() <= h {
+			continue
+		}
+
+		if r.W > w || r.H > h {
+			result = append(result, diag(c,
+				fmt.Sprintf("size %dx%d does not fit %s", r.W, r.H, area),
+				fmt.Sprintf("resize to %dx%d at (0, 0)", min(r.W, w), min(r.H, h))))
+			continue
+		}
+
+		x := max(0, min(r.X, w-r.W))
+		y := max(0, min(r.Y, h-r.H))
+		result = append(result, diag(c,
+			fmt.Sprintf("spans (%d, %d)-(%d, %d), outside %s", r.X, r.Y, r.Right(), r.Bottom(), area),
+			fmt.Sprintf("move to (%d, %d)", x, y)))
+	}
+	for _, c := range comps {
+		result = append(result, outOfBounds(c.Children, c.Size.Width, c.Size.Height,
+			fmt.Sprintf("the %dx%d container %q", c.Size.Width, c.Size.Height, label(c)))...)
+	}
+	return result
+}
+
+// contentArea returns the inner width and height available for text
+func contentArea(c schema.Component) (int, int) {
+	w, h := c.Size.Width, c.Size.Height
+	if c.Type == schema.TypeText {
+		return w, 0
+	}
+	if c.Style.Border != nil && c.Style.Border.Style != "none" {
+		w -= 2
+		h -= 2
+	}
+	w -= c.Style.Padding.Left + c.Style.Padding.Right
+	h -= c.Style.Padding.Top + c.Style.Padding.Bottom
+	return w, h
+}
+
+func checkTextOverflow(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	walk(canvas.Components, func(c schema.Component) {
+		if c.Size.Width <= 0 {
+			return
+		}
+		lines := c.Items
+		if c.Text != "" {
+			lines = append(strings.Split(c.Text, "\n"), lines...)
+		}
+		if len(lines) == 0 {
+			return
+		}
+
+		innerW, innerH := contentArea(c)
+		longest := 0
+		for _, line := range lines {
+			longest = max(longest, lipgloss.Width(line))
+		}
+
+		if longest > innerW {
+			result = append(result, diag(c,
+				fmt.Sprintf("text is %d cells wide but only %d fit", longest, max(innerW, 0)),
+				fmt.Sprintf("resize width to %d", c.Size.Width+longest-innerW)))
+		}
+		if innerH > 0 && len(lines) > innerH && c.Type != schema.TypeList {
+			result = append(result, diag(c,
+				fmt.Sprintf("%d lines of text but only %d fit", len(lines), innerH),
+				fmt.Sprintf("resize height to %d", c.Size.Height+len(lines)-innerH)))
+		}
+	})
+	return result
+}
+
+func checkDuplicateNames(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	seen := map[string]bool{}
+	walk(canvas.Components, func(c schema.Component) {
+		if c.Name == "" {
+			return
+		}
+		if seen[c.Name] {
+			result = append(result, diag(c,
+				fmt.Sprintf("name %q is already used", c.Name),
+				fmt.Sprintf("rename to %q", uniqueName(canvas, c.Name))))
+		}
+		seen[c.Name] = true
+	})
+	return result
+}
+
+func uniqueName(canvas *schema.Canvas, base string) string {
+	used := map[string]bool{}
+	walk(canvas.Components, func(c schema.Component) { used[c.Name] = true })
+	for n := 2; ; n++ {
+		name := base + "-" + strconv.Itoa(n)
+		if !used[name] {
+			return name
+		}
+	}
+}
+
+func checkDuplicateIDs(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	seen := map[string]bool{}
+	walk(canvas.Components, func(c schema.Component) {
+		if c.ID == "" {
+			result = append(result, diag(c, "component has no ID", "assign a unique ID"))
+			return
+		}
+		if seen[c.ID] {
+			result = append(result, diag(c,
+				fmt.Sprintf("ID %q is already used", c.ID),
+				"assign a unique ID"))
+		}
+		seen[c.ID] = true
+	})
+	return result
+}
+
+func checkZeroSize(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	walk(canvas.Components, func(c schema.Component) {
+		if c.Type == schema.TypeText {
+			return // text sizes itself to its content
+		}
+		if c.Size.Width <= 0 || c.Size.Height <= 0 {
+			result = append(result, diag(c,
+				fmt.Sprintf("size is %dx%d", c.Size.Width, c.Size.Height),
+				fmt.Sprintf("resize to %dx%d", max(c.Size.Width, 1), max(c.Size.Height, 1))))
+		}
+	})
+	return result
+}
+
+func checkInvalidColors(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	walk(canvas.Components, func(c schema.Component) {
+		colors := map[string]string{
+			"foreground": c.Style.Foreground,
+			"background": c.Style.Background,
+		}
+		if c.Style.Border != nil {
+			colors["border color"] = c.Style.Border.Color
+		}
+		for _, field := range []string{"foreground", "background", "border color"} {
+			value := colors[field]
+			if styles.IsToken(value) {
+				if _, ok := styles.LookupToken(value); !ok {
+					result = append(result, diag(c,
+						fmt.Sprintf("%s %q is not a theme token", field, value),
+						"use one of "+tokenList()))
+				}
+				continue
+			}
+			if value == "" || ValidColor(value) {
+				continue
+			}
+			result = append(result, diag(c,
+				fmt.Sprintf("%s %q is not a valid color", field, value),
+				"use #RRGGBB, #RGB, an ANSI color number 0-255 or a theme token like $primary"))
+		}
+	})
+	return result
+}
+
+func tokenList() string {
+	names := make([]string, len(styles.Tokens))
+	for i, tok := range styles.Tokens {
+		names[i] = styles.TokenPrefix + tok.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// ValidColor reports whether s is a color lipgloss understands:
+// #RGB, #RRGGBB or an ANSI color number from 0 to 255.
+func ValidColor(s string) bool {
+	if strings.HasPrefix(s, "#") {
+		hex := s[1:]
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// checkContrast reports failing pairs on the components that chose the
+// colors. Failures that come purely from the theme are reported once.
+func checkContrast(canvas *schema.Canvas, cfg Config) []Diagnostic {
+	var result []Diagnostic
+	theme := styles.Lookup(canvas.Theme)
+	report := contrast.Check(canvas, theme, contrast.Options{Level: cfg.ContrastLevel})
+
+	themeUses := map[string]int{}
+	themeDiag := map[string]int{}
+	for _, p := range report.Failures() {
+		msg := fmt.Sprintf("%s %s on %s has contrast %.2f:1, needs %.1f:1",
+			p.Role, p.Foreground, p.Background, p.Ratio, p.Required)
+		fix := ""
+		if p.Suggestion != "" {
+			fix = fmt.Sprintf("use %s for the %s", p.Suggestion, p.Role)
+		}
+
+		if !p.Inherited {
+			result = append(result, Diagnostic{ComponentID: p.ComponentID, Component: p.Component, Message: msg, Fix: fix})
+			continue
+		}
+
+		key := p.Role + p.Foreground + p.Background
+		themeUses[key]++
+		if i, ok := themeDiag[key]; ok {
+			result[i].Message = fmt.Sprintf("%s (%d components)", msg, themeUses[key])
+			continue
+		}
+		themeDiag[key] = len(result)
+		result = append(result, Diagnostic{
+			ComponentID: p.ComponentID,
+			Component:   "theme " + theme.Name,
+			Message:     msg,
+			Fix:         fix,
+		})
+	}
+	return result
+}
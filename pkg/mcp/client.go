@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/makeatui/makeatui/pkg/lint"
 )
 
 // Client is an MCP client for MakeaTUI
@@ -149,6 +151,44 @@ func (c *Client) GetCanvas() (string, error) {
 	return string(body), err
 }
 
+// Lint checks the current design and returns its diagnostics
+func (c *Client) Lint() ([]lint.Diagnostic, error) {
+	resp, err := c.post("/tools/lint", map[string]any{
+		"session_id": c.sessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Diagnostics []lint.Diagnostic `json:"diagnostics"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+	return result.Diagnostics, nil
+}
+
+// Render previews the current design as plain text. opts.IncludePreview
+// is implied.
+func (c *Client) Render(opts PreviewOptions) (*Preview, error) {
+	resp, err := c.post("/tools/render", map[string]any{
+		"session_id": c.sessionID,
+		"diff":       opts.Diff,
+		"max_width":  opts.MaxWidth,
+		"max_height": opts.MaxHeight,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var p Preview
+	if err := json.Unmarshal(resp, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 // ListTemplates lists available templates
 func (c *Client) ListTemplates() ([]string, error) {
 	url := fmt.Sprintf("%s/templates", c.baseURL)
@@ -0,0 +1,190 @@
+// Package mcp - Text previews of a design for agents
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/render"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Default limits of a preview, small enough to keep in a model's context
+const (
+	DefaultPreviewWidth  = 120
+	DefaultPreviewHeight = 50
+)
+
+// PreviewOptions ask a tool call for a preview of the design after it.
+// Every mutating tool takes them; makeatui_render always previews.
+type PreviewOptions struct {
+	IncludePreview bool `json:"include_preview,omitempty"`
+
+	// Diff adds the rows that changed since the previous preview of the
+	// session
+	Diff bool `json:"diff,omitempty"`
+
+	// MaxWidth and MaxHeight crop the screen, DefaultPreviewWidth and
+	// DefaultPreviewHeight when zero
+	MaxWidth  int `json:"max_width,omitempty"`
+	MaxHeight int `json:"max_height,omitempty"`
+}
+
+// Preview is the design drawn as plain text, with rulers giving the
+// coordinates and a legend of where each component is
+type Preview struct {
+	Width     int           `json:"width"`
+	Height    int           `json:"height"`
+	Screen    string        `json:"screen"`
+	Legend    []LegendEntry `json:"legend"`
+	Truncated bool          `json:"truncated,omitempty"`
+
+	// Diff lists the screen rows that changed since the previous
+	// preview, "-" before and "+" after. It is empty when nothing did
+	// and absent for the first preview.
+	Diff *string `json:"diff,omitempty"`
+}
+
+// LegendEntry is a visible component and the cells it covers
+type LegendEntry struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// String gives the entry as a line of the legend
+func (e LegendEntry) String() string {
+	return fmt.Sprintf("%s %s %q at %d,%d %d×%d", e.ID, e.Type, e.Name, e.X, e.Y, e.Width, e.Height)
+}
+
+// NewPreview draws design as plain text at its own size, cropped to the
+// options' limits
+func NewPreview(design *schema.Canvas, opts PreviewOptions) Preview {
+	maxW, maxH := opts.MaxWidth, opts.MaxHeight
+	if maxW <= 0 {
+		maxW = DefaultPreviewWidth
+	}
+	if maxH <= 0 {
+		maxH = DefaultPreviewHeight
+	}
+	size := render.Size(design)
+	p := Preview{Width: size.Width, Height: size.Height}
+	if size.Width > maxW || size.Height > maxH {
+		p.Truncated = true
+		size = schema.Size{Width: min(size.Width, maxW), Height: min(size.Height, maxH)}
+	}
+
+	theme := styles.Lookup(design.Theme)
+	lines := strings.Split(render.Plain(render.Grid(design, size, theme)), "\n")
+	p.Screen = withRulers(lines, size.Width)
+	p.Legend = legend(design.Components, 0, 0, theme)
+	return p
+}
+
+// withRulers numbers the columns above the screen, the tens over the
+// units, and every row down its left side
+func withRulers(lines []string, width int) string {
+	tens := []byte(strings.Repeat(" ", width))
+	units := make([]byte, width)
+	for x := range units {
+		units[x] = byte('0' + x%10)
+		if label := fmt.Sprint(x); x%10 == 0 && x+len(label) <= width {
+			copy(tens[x:], label)
+		}
+	}
+
+	gutter := len(fmt.Sprint(len(lines) - 1))
+	pad := strings.Repeat(" ", gutter+1)
+	var sb strings.Builder
+	sb.WriteString(pad + strings.TrimRight(string(tens), " ") + "\n")
+	sb.WriteString(pad + string(units))
+	for y, line := range lines {
+		fmt.Fprintf(&sb, "\n%*d│%s", gutter, y, line)
+	}
+	return sb.String()
+}
+
+// legend lists the visible components in drawing order, placed in canvas
+// coordinates and sized by their schema as lint sees them, with a width
+// or height left to the content measured as drawn
+func legend(comps []schema.Component, dx, dy int, theme styles.Theme) []LegendEntry {
+	var entries []LegendEntry
+	for _, comp := range schema.Stack(comps) {
+		if comp.Hidden {
+			continue
+		}
+		x, y := comp.Position.X+dx, comp.Position.Y+dy
+		r := lint.Bounds(comp)
+		w, h := r.W, r.H
+		if w <= 0 || h <= 0 {
+			dw, dh := lipgloss.Size(render.Component(comp, theme, render.State{}))
+			if w <= 0 {
+				w = dw
+			}
+			if h <= 0 {
+				h = dh
+			}
+		}
+		entries = append(entries, LegendEntry{
+			ID: comp.ID, Type: string(comp.Type), Name: comp.Name,
+			X: x, Y: y, Width: w, Height: h,
+		})
+		entries = append(entries, legend(comp.Children, x, y, theme)...)
+	}
+	return entries
+}
+
+// diffScreens lists the rows of after that differ from before, the
+// screens compared row by row as they are the same grid
+func diffScreens(before, after string) string {
+	a, b := strings.Split(before, "\n"), strings.Split(after, "\n")
+	var sb strings.Builder
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var was, is string
+		if i < len(a) {
+			was = a[i]
+		}
+		if i < len(b) {
+			is = b[i]
+		}
+		if was == is {
+			continue
+		}
+		if i < len(a) {
+			sb.WriteString("-" + was + "\n")
+		}
+		if i < len(b) {
+			sb.WriteString("+" + is + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Text gives the preview as one block of text: the screen, the legend
+// and the diff when there is one
+func (p Preview) Text() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d×%d", p.Width, p.Height)
+	if p.Truncated {
+		sb.WriteString(", cropped")
+	}
+	sb.WriteString("\n" + p.Screen + "\n\nComponents:\n")
+	for _, e := range p.Legend {
+		sb.WriteString("  " + e.String() + "\n")
+	}
+	if p.Diff != nil {
+		if *p.Diff == "" {
+			sb.WriteString("\nNo change since the previous preview\n")
+		} else {
+			sb.WriteString("\nChanged rows:\n" + *p.Diff)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+func TestPreview(t *testing.T) {
+	s := NewServer(0)
+	session := s.CreateSession("demo")
+	session.API.GetCanvas().Width, session.API.GetCanvas().Height = 24, 4
+	id := session.API.AddText("Title", "Hello", 11, 1)
+
+	p := session.preview(PreviewOptions{Diff: true})
+	lines := strings.Split(p.Screen, "\n")
+	want := []string{
+		"  0         10        20",
+		"  012345678901234567890123",
+		"0│",
+		"1│           Hello",
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("line %d = %q, want %q", i, lines[i], line)
+		}
+	}
+	if p.Diff != nil {
+		t.Errorf("first preview has a diff: %q", *p.Diff)
+	}
+	if len(p.Legend) != 1 || p.Legend[0] != (LegendEntry{ID: id, Type: "text", Name: "Title", X: 11, Y: 1, Width: 20, Height: 1}) {
+		t.Errorf("legend = %+v", p.Legend)
+	}
+
+	_ = session.API.Move(id, 0, 2)
+	p = session.preview(PreviewOptions{Diff: true})
+	wantDiff := "-1│           Hello\n+1│\n-2│\n+2│Hello\n"
+	if p.Diff == nil || *p.Diff != wantDiff {
+		t.Errorf("diff = %v, want %q", p.Diff, wantDiff)
+	}
+
+	// A bordered box is listed at its size, which its border stays inside
+	box := session.API.AddBox("Panel", "", 12, 0, 10, 4)
+	p = session.preview(PreviewOptions{})
+	if len(p.Legend) != 2 || p.Legend[1] != (LegendEntry{ID: box, Type: "box", Name: "Panel", X: 12, Y: 0, Width: 10, Height: 4}) {
+		t.Errorf("legend = %+v", p.Legend)
+	}
+	lines = strings.Split(p.Screen, "\n")
+	if got := lines[5]; !strings.HasSuffix(got, "╯") || len([]rune(got)) != 2+12+10 {
+		t.Errorf("bottom of the box drawn as %q", got)
+	}
+
+	p = NewPreview(&schema.Canvas{Width: 200, Height: 10}, PreviewOptions{MaxWidth: 40})
+	if !p.Truncated || p.Width != 200 || !strings.HasSuffix(strings.Split(p.Screen, "\n")[1], "0123456789") {
+		t.Errorf("cropped preview: %+v", p)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/makeatui/makeatui/pkg/agent"
 	"github.com/makeatui/makeatui/pkg/ai"
+	"github.com/makeatui/makeatui/pkg/lint"
+	"github.com/makeatui/makeatui/pkg/schema"
 	"github.com/makeatui/makeatui/pkg/templates"
 )
 
@@ -26,6 +29,8 @@ type Session struct {
 	Name     string     `json:"name"`
 	API      *agent.API `json:"-"`
 	AIAgent  *ai.TUIAgent `json:"-"`
+
+	screen string // the previous preview, to diff the next against
 }
 
 // NewServer creates a new MCP server
@@ -52,6 +57,8 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/tools/set_text", s.handleSetText)
 	mux.HandleFunc("/tools/generate", s.handleGenerate)
 	mux.HandleFunc("/tools/export", s.handleExport)
+	mux.HandleFunc("/tools/lint", s.handleLint)
+	mux.HandleFunc("/tools/render", s.handleRender)
 
 	// Templates
 	mux.HandleFunc("/templates", s.handleTemplates)
@@ -107,13 +114,32 @@ func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
 
 	case http.MethodPost:
 		var req struct {
-			Name string `json:"name"`
+			Name       string            `json:"name"`
+			IDStrategy schema.IDStrategy `json:"id_strategy"`
+			IDSeed     *int64            `json:"id_seed"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			respondError(w, http.StatusBadRequest, "invalid request")
+			respondError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+			return
+		}
+		if req.IDStrategy != "" {
+			if err := schema.ValidateIDStrategy(req.IDStrategy); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if req.IDSeed != nil && req.IDStrategy != schema.IDStrategySeeded {
+			respondError(w, http.StatusBadRequest, "id_seed needs the seeded ID strategy")
 			return
 		}
 		session := s.CreateSession(req.Name)
+		if req.IDStrategy != "" {
+			var seed int64
+			if req.IDSeed != nil {
+				seed = *req.IDSeed
+			}
+			session.API.SetIDStrategy(req.IDStrategy, seed)
+		}
 		respondJSON(w, session)
 
 	default:
@@ -158,6 +184,7 @@ func (s *Server) handleAddComponent(w http.ResponseWriter, r *http.Request) {
 		Y         int    `json:"y"`
 		Width     int    `json:"width"`
 		Height    int    `json:"height"`
+		PreviewOptions
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -184,7 +211,7 @@ func (s *Server) handleAddComponent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, map[string]string{"id": id})
+	respondWithPreview(w, session, req.PreviewOptions, map[string]any{"id": id})
 }
 
 func (s *Server) handleMoveComponent(w http.ResponseWriter, r *http.Request) {
@@ -193,6 +220,7 @@ func (s *Server) handleMoveComponent(w http.ResponseWriter, r *http.Request) {
 		ComponentID string `json:"component_id"`
 		X           int    `json:"x"`
 		Y           int    `json:"y"`
+		PreviewOptions
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -207,13 +235,14 @@ func (s *Server) handleMoveComponent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_ = session.API.Move(req.ComponentID, req.X, req.Y)
-	respondJSON(w, map[string]string{"status": "moved"})
+	respondWithPreview(w, session, req.PreviewOptions, map[string]any{"status": "moved"})
 }
 
 func (s *Server) handleRemoveComponent(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		SessionID   string `json:"session_id"`
 		ComponentID string `json:"component_id"`
+		PreviewOptions
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -228,7 +257,7 @@ func (s *Server) handleRemoveComponent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_ = session.API.Delete(req.ComponentID)
-	respondJSON(w, map[string]string{"status": "removed"})
+	respondWithPreview(w, session, req.PreviewOptions, map[string]any{"status": "removed"})
 }
 
 func (s *Server) handleSetText(w http.ResponseWriter, r *http.Request) {
@@ -236,6 +265,7 @@ func (s *Server) handleSetText(w http.ResponseWriter, r *http.Request) {
 		SessionID   string `json:"session_id"`
 		ComponentID string `json:"component_id"`
 		Text        string `json:"text"`
+		PreviewOptions
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -250,13 +280,14 @@ func (s *Server) handleSetText(w http.ResponseWriter, r *http.Request) {
 	}
 
 	_ = session.API.SetText(req.ComponentID, req.Text)
-	respondJSON(w, map[string]string{"status": "updated"})
+	respondWithPreview(w, session, req.PreviewOptions, map[string]any{"status": "updated"})
 }
 
 func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		SessionID   string `json:"session_id"`
 		Description string `json:"description"`
+		PreviewOptions
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -277,7 +308,7 @@ func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session.API = api
-	respondJSON(w, map[string]string{"status": "generated"})
+	respondWithPreview(w, session, req.PreviewOptions, map[string]any{"status": "generated"})
 }
 
 func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
@@ -302,6 +333,85 @@ func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *Server) handleLint(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string          `json:"session_id"`
+		Config    json.RawMessage `json:"config"`
+	}
+
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	} else {
+		req.SessionID = r.URL.Query().Get("session_id")
+	}
+
+	session := s.GetSession(req.SessionID)
+	if session == nil {
+		respondError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	cfg := lint.DefaultConfig()
+	if len(req.Config) > 0 {
+		var err error
+		if cfg, err = lint.ParseConfig(req.Config); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid config: "+err.Error())
+			return
+		}
+	}
+
+	diags := lint.New(cfg).Run(session.API.GetCanvas())
+	if diags == nil {
+		diags = []lint.Diagnostic{}
+	}
+	respondJSON(w, map[string]any{
+		"diagnostics": diags,
+		"errors":      lint.Count(diags, lint.SeverityError),
+		"warnings":    lint.Count(diags, lint.SeverityWarning),
+	})
+}
+
+// handleRender previews the design: as JSON, or as the text of the
+// preview with format=text
+func (s *Server) handleRender(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string `json:"session_id"`
+		Format    string `json:"format"`
+		PreviewOptions
+	}
+
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		req.SessionID = q.Get("session_id")
+		req.Format = q.Get("format")
+		req.Diff = q.Get("diff") == "true"
+		req.MaxWidth, _ = strconv.Atoi(q.Get("max_width"))
+		req.MaxHeight, _ = strconv.Atoi(q.Get("max_height"))
+	}
+
+	session := s.GetSession(req.SessionID)
+	if session == nil {
+		respondError(w, http.StatusNotFound, "session not found")
+		return
+	}
+
+	p := session.preview(req.PreviewOptions)
+	if req.Format == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(p.Text()))
+		return
+	}
+	respondJSON(w, p)
+}
+
 func (s *Server) handleTemplates(w http.ResponseWriter, r *http.Request) {
 	templates := s.templateEngine.List()
 	respondJSON(w, templates)
@@ -311,6 +421,7 @@ func (s *Server) handleApplyTemplate(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		SessionID    string `json:"session_id"`
 		TemplateName string `json:"template_name"`
+		PreviewOptions
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -331,7 +442,7 @@ func (s *Server) handleApplyTemplate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	session.API = api
-	respondJSON(w, map[string]string{"status": "applied"})
+	respondWithPreview(w, session, req.PreviewOptions, map[string]any{"status": "applied"})
 }
 
 func (s *Server) handleCanvasResource(w http.ResponseWriter, r *http.Request) {
@@ -363,6 +474,28 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 // Helper functions
+
+// preview draws the session's design, with the rows changed since the
+// previous preview when asked, and keeps it to diff the next against
+func (sess *Session) preview(opts PreviewOptions) Preview {
+	p := NewPreview(sess.API.GetCanvas(), opts)
+	if opts.Diff && sess.screen != "" {
+		diff := diffScreens(sess.screen, p.Screen)
+		p.Diff = &diff
+	}
+	sess.screen = p.Screen
+	return p
+}
+
+// respondWithPreview responds with result, and a preview of the design
+// as the tool call left it when the call asks for one
+func respondWithPreview(w http.ResponseWriter, session *Session, opts PreviewOptions, result map[string]any) {
+	if opts.IncludePreview {
+		result["preview"] = session.preview(opts)
+	}
+	respondJSON(w, result)
+}
+
 func respondJSON(w http.ResponseWriter, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(data)
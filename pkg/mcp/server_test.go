@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/lint"
+)
+
+func TestCreateSessionIDStrategy(t *testing.T) {
+	s := NewServer(0)
+	for _, tc := range []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"name":"a","id_strategy":"slug"}`, http.StatusOK, `"name":"a"`},
+		{`{"name":"b","id_strategy":"seeded","id_seed":7}`, http.StatusOK, `"name":"b"`},
+		{`{"name":"c","id_strategy":"uuid"}`, http.StatusBadRequest, "valid: random, seeded, slug"},
+		{`{"name":"d","id_strategy":"slug","id_seed":7}`, http.StatusBadRequest, "id_seed needs the seeded ID strategy"},
+		{`{"name":"e","id_strategy":"seeded","id_seed":"x"}`, http.StatusBadRequest, "invalid request"},
+	} {
+		rec := httptest.NewRecorder()
+		s.handleSessions(rec, httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(tc.body)))
+		if rec.Code != tc.status || !strings.Contains(rec.Body.String(), tc.want) {
+			t.Errorf("%s: %d %s, want %d containing %q", tc.body, rec.Code, rec.Body, tc.status, tc.want)
+		}
+	}
+	if n := len(s.sessions); n != 2 {
+		t.Errorf("%d sessions created, want 2", n)
+	}
+}
+
+func TestLintPartialConfig(t *testing.T) {
+	s := NewServer(0)
+	session := s.CreateSession("demo")
+	canvas := session.API.GetCanvas()
+	canvas.Width, canvas.Height = 0, 0
+	session.API.AddBox("Card", "", 0, 0, 20, 5)
+	session.API.AddBox("Inner", "", 1, 1, 5, 2)
+	session.API.AddBox("Other", "", 10, 2, 20, 5)
+
+	body := `{"session_id":"` + session.ID + `","config":{"rules":{"overlap":{"severity":"error"}}}}`
+	rec := httptest.NewRecorder()
+	s.handleLint(rec, httptest.NewRequest(http.MethodPost, "/tools/lint", strings.NewReader(body)))
+
+	var resp struct {
+		Diagnostics []lint.Diagnostic `json:"diagnostics"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	// Inner is contained in Card and everything fits the default 80×24,
+	// so only Other overlapping Card is reported, as the config asked
+	var layout []lint.Diagnostic
+	for _, d := range resp.Diagnostics {
+		if d.Rule == lint.RuleOverlap || d.Rule == lint.RuleOutOfBounds {
+			layout = append(layout, d)
+		}
+	}
+	if len(layout) != 1 || layout[0].Rule != lint.RuleOverlap || layout[0].Severity != lint.SeverityError {
+		t.Errorf("diagnostics = %+v", layout)
+	}
+}
@@ -8,8 +8,55 @@ type MCPToolSchema struct {
 	InputSchema map[string]interface{} `json:"inputSchema"`
 }
 
+// mutatingTools change the design, so they can return a preview of it
+var mutatingTools = map[string]bool{
+	"makeatui_add_box":          true,
+	"makeatui_add_text":         true,
+	"makeatui_add_button":       true,
+	"makeatui_move_component":   true,
+	"makeatui_remove_component": true,
+	"makeatui_generate":         true,
+	"makeatui_apply_template":   true,
+}
+
+// previewProperties are the inputs of PreviewOptions
+func previewProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"include_preview": map[string]string{
+			"type":        "boolean",
+			"description": "Return the design as a plain-text screen with coordinate rulers and a component legend",
+		},
+		"diff": map[string]string{
+			"type":        "boolean",
+			"description": "Also return the screen rows changed since the previous preview",
+		},
+		"max_width": map[string]string{
+			"type":        "integer",
+			"description": "Crop the preview to this many columns (default 120)",
+		},
+		"max_height": map[string]string{
+			"type":        "integer",
+			"description": "Crop the preview to this many rows (default 50)",
+		},
+	}
+}
+
 // GetToolSchemas returns all MakeaTUI MCP tool schemas
 func GetToolSchemas() []MCPToolSchema {
+	tools := toolSchemas()
+	for _, tool := range tools {
+		if !mutatingTools[tool.Name] {
+			continue
+		}
+		props := tool.InputSchema["properties"].(map[string]interface{})
+		for name, prop := range previewProperties() {
+			props[name] = prop
+		}
+	}
+	return tools
+}
+
+func toolSchemas() []MCPToolSchema {
 	return []MCPToolSchema{
 		{
 			Name:        "makeatui_create_session",
@@ -21,6 +68,14 @@ func GetToolSchemas() []MCPToolSchema {
 						"type":        "string",
 						"description": "Name for the design project",
 					},
+					"id_strategy": map[string]string{
+						"type":        "string",
+						"description": "Component ID strategy: 'random' (default), 'slug' or 'seeded'",
+					},
+					"id_seed": map[string]string{
+						"type":        "integer",
+						"description": "Seed for the 'seeded' ID strategy",
+					},
 				},
 				"required": []string{"name"},
 			},
@@ -135,6 +190,40 @@ func GetToolSchemas() []MCPToolSchema {
 				"required": []string{"template"},
 			},
 		},
+		{
+			Name:        "makeatui_lint",
+			Description: "Check the design for overlaps, out-of-bounds components, text overflow, duplicate names, zero-size components and invalid colors",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"config": map[string]string{
+						"type":        "object",
+						"description": "Optional lint config, e.g. {\"rules\": {\"overlap\": {\"disabled\": true}}, \"allow_containment\": true}",
+					},
+				},
+			},
+		},
+		{
+			Name:        "makeatui_render",
+			Description: "Show the design as a plain-text screen with coordinate rulers and a legend of each component's ID, type, name, position and size",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"diff": map[string]string{
+						"type":        "boolean",
+						"description": "Also return the screen rows changed since the previous preview",
+					},
+					"max_width": map[string]string{
+						"type":        "integer",
+						"description": "Crop the preview to this many columns (default 120)",
+					},
+					"max_height": map[string]string{
+						"type":        "integer",
+						"description": "Crop the preview to this many rows (default 50)",
+					},
+				},
+			},
+		},
 		{
 			Name:        "makeatui_get_canvas",
 			Description: "Get the current canvas state as JSON",
@@ -0,0 +1,183 @@
+// Package palette generates complete themes from a single seed color
+package palette
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/contrast"
+)
+
+// Mode selects a dark or light theme
+type Mode string
+
+const (
+	ModeDark  Mode = "dark"
+	ModeLight Mode = "light"
+)
+
+// Options configures theme generation
+type Options struct {
+	// Name of the generated theme; derived from the seed if empty
+	Name string
+
+	// Mode is dark (the default) or light
+	Mode Mode
+
+	// Level is the WCAG level every theme pair must meet (AA by default)
+	Level contrast.Level
+}
+
+// Hues of the semantic colors in OkLch degrees
+const (
+	hueError   = 27
+	hueWarning = 80
+	hueSuccess = 145
+	hueInfo    = 235
+)
+
+// tone is a target OkLch lightness for each mode
+type tone struct{ dark, light float64 }
+
+func (t tone) in(mode Mode) float64 {
+	if mode == ModeLight {
+		return t.light
+	}
+	return t.dark
+}
+
+var (
+	toneBackground   = tone{0.18, 0.98}
+	toneSurface      = tone{0.23, 0.95}
+	toneSurfaceLight = tone{0.28, 0.91}
+	toneText         = tone{0.95, 0.22}
+	toneTextSecond   = tone{0.82, 0.38}
+	toneTextMuted    = tone{0.70, 0.50}
+	toneBorder       = tone{0.45, 0.75}
+	toneSemantic     = tone{0.75, 0.50}
+)
+
+// Generate derives every theme slot from the seed color. Colors are
+// built in OkLch so that equal lightness looks equally light across
+// hues, then nudged until each pair checked by contrast.CheckTheme
+// meets the requested level.
+func Generate(seed string, opts Options) (styles.Theme, error) {
+	if !strings.HasPrefix(seed, "#") {
+		seed = "#" + seed
+	}
+	base, err := contrast.Parse(seed)
+	if err != nil {
+		return styles.Theme{}, fmt.Errorf("invalid seed color %q", seed)
+	}
+	if opts.Mode == "" {
+		opts.Mode = ModeDark
+	}
+	if opts.Mode != ModeDark && opts.Mode != ModeLight {
+		return styles.Theme{}, fmt.Errorf("unknown mode %q (use dark or light)", opts.Mode)
+	}
+	if opts.Level == "" {
+		opts.Level = contrast.LevelAA
+	}
+	if opts.Level != contrast.LevelAA && opts.Level != contrast.LevelAAA {
+		return styles.Theme{}, fmt.Errorf("unknown level %q (use AA or AAA)", opts.Level)
+	}
+	if opts.Name == "" {
+		opts.Name = fmt.Sprintf("%s-%s", strings.ToLower(strings.TrimPrefix(base.Hex(), "#")), opts.Mode)
+	}
+
+	mode := opts.Mode
+	textRatio := opts.Level.Required(contrast.KindText)
+	nonTextRatio := opts.Level.Required(contrast.KindNonText)
+	seedL, seedC, hue := base.OkLch()
+	chroma := clamp(seedC, 0, 0.2)
+
+	// Neutrals carry a hint of the seed hue
+	neutral := math.Min(chroma, 0.02)
+	bg := oklch(toneBackground.in(mode), neutral, hue)
+	surface := oklch(toneSurface.in(mode), neutral, hue)
+	surfaceLight := oklch(toneSurfaceLight.in(mode), neutral, hue)
+	surfaces := []colorful.Color{bg, surface, surfaceLight}
+	onBg := []colorful.Color{bg}
+
+	// The seed is used as-is when it already reads well
+	primary := base
+	if minRatio(base, onBg) < textRatio {
+		primary = fit(seedL, chroma, hue, onBg, textRatio, mode)
+	}
+	accentChroma := math.Max(chroma, 0.1)
+
+	t := styles.Theme{
+		Name:          opts.Name,
+		Primary:       hex(primary),
+		Secondary:     hex(fit(seedL, accentChroma, hue+40, onBg, textRatio, mode)),
+		Accent:        hex(fit(toneSemantic.in(mode), accentChroma, hue+180, onBg, textRatio, mode)),
+		Background:    hex(bg),
+		Surface:       hex(surface),
+		SurfaceLight:  hex(surfaceLight),
+		TextPrimary:   hex(fit(toneText.in(mode), neutral, hue, surfaces, textRatio, mode)),
+		TextSecondary: hex(fit(toneTextSecond.in(mode), neutral, hue, surfaces, textRatio, mode)),
+		TextMuted:     hex(fit(toneTextMuted.in(mode), neutral, hue, surfaces, textRatio, mode)),
+		Success:       hex(fit(toneSemantic.in(mode), 0.15, hueSuccess, onBg, textRatio, mode)),
+		Warning:       hex(fit(toneSemantic.in(mode), 0.15, hueWarning, onBg, textRatio, mode)),
+		Error:         hex(fit(toneSemantic.in(mode), 0.15, hueError, onBg, textRatio, mode)),
+		Info:          hex(fit(toneSemantic.in(mode), 0.15, hueInfo, onBg, textRatio, mode)),
+		Border:        hex(fit(toneBorder.in(mode), neutral*2, hue, onBg, nonTextRatio, mode)),
+		BorderActive:  hex(primary),
+	}
+	return t, nil
+}
+
+// fit starts at lightness l and moves away from the background until
+// the color reaches the required ratio against every color in against
+func fit(l, c, h float64, against []colorful.Color, required float64, mode Mode) colorful.Color {
+	step := 0.01
+	if mode == ModeLight {
+		step = -step
+	}
+	// If moving away never gets there, try the other direction
+	for try := 0; try < 2; try++ {
+		for x := l; x >= 0 && x <= 1; x += step {
+			col := oklch(x, c, h)
+			if minRatio(col, against) >= required {
+				return col
+			}
+		}
+		step = -step
+	}
+	if mode == ModeLight {
+		return oklch(0, 0, 0)
+	}
+	return oklch(1, 0, 0)
+}
+
+func minRatio(c colorful.Color, against []colorful.Color) float64 {
+	lowest := math.Inf(1)
+	for _, a := range against {
+		lowest = math.Min(lowest, contrast.Ratio(c, a))
+	}
+	return lowest
+}
+
+// oklch converts to RGB, reducing chroma until the color is in gamut,
+// and rounds to the nearest hex color so checks see what is written
+func oklch(l, c, h float64) colorful.Color {
+	col := colorful.OkLch(l, c, h)
+	for !col.IsValid() && c > 0 {
+		c = math.Max(c-0.005, 0)
+		col = colorful.OkLch(l, c, h)
+	}
+	rounded, _ := colorful.Hex(col.Clamped().Hex())
+	return rounded
+}
+
+func hex(c colorful.Color) lipgloss.Color {
+	return lipgloss.Color(strings.ToUpper(c.Hex()))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,47 @@
+package palette
+
+import (
+	"testing"
+
+	"github.com/makeatui/makeatui/pkg/contrast"
+)
+
+func TestGenerateMeetsContrast(t *testing.T) {
+	seeds := []string{"#9D4EDD", "#00FFFF", "FFD93D", "#333333", "#FFFFFF", "#0000FF"}
+	for _, seed := range seeds {
+		for _, mode := range []Mode{ModeDark, ModeLight} {
+			for _, level := range []contrast.Level{contrast.LevelAA, contrast.LevelAAA} {
+				theme, err := Generate(seed, Options{Mode: mode, Level: level})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, p := range contrast.CheckTheme(theme, level) {
+					if !p.Pass {
+						t.Errorf("%s %s %s: %s is %.2f:1, needs %.1f", seed, mode, level, p.Role, p.Ratio, p.Required)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateOptions(t *testing.T) {
+	theme, err := Generate("#C77DFF", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Name != "c77dff-dark" {
+		t.Errorf("default name = %q", theme.Name)
+	}
+	// A seed that already reads well is kept as the primary color
+	if theme.Primary != "#C77DFF" {
+		t.Errorf("primary = %s, want the seed", theme.Primary)
+	}
+
+	if _, err := Generate("not-a-color", Options{}); err == nil {
+		t.Error("expected an error for an invalid seed")
+	}
+	if _, err := Generate("#9D4EDD", Options{Mode: "dim"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
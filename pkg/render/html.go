@@ -0,0 +1,105 @@
+// Package render - HTML snapshots
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/compositor"
+)
+
+// css returns the inline style of a span of cells styled a
+func css(a compositor.Attrs) string {
+	fg, bg := a.FG, a.BG
+	if a.Reverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "var(--bg)"
+		}
+		if bg == "" {
+			bg = "var(--fg)"
+		}
+	}
+	var rules []string
+	if fg != "" {
+		rules = append(rules, "color:"+fg)
+	}
+	if bg != "" {
+		rules = append(rules, "background:"+bg)
+	}
+	if a.Bold {
+		rules = append(rules, "font-weight:bold")
+	}
+	if a.Faint {
+		rules = append(rules, "opacity:.6")
+	}
+	if a.Italic {
+		rules = append(rules, "font-style:italic")
+	}
+	switch {
+	case a.Underline && a.Strike:
+		rules = append(rules, "text-decoration:underline line-through")
+	case a.Underline:
+		rules = append(rules, "text-decoration:underline")
+	case a.Strike:
+		rules = append(rules, "text-decoration:line-through")
+	}
+	return strings.Join(rules, ";")
+}
+
+// HTML writes buf as a page of its own, each run of cells styled alike
+// in a span, on the theme's background
+func HTML(buf *compositor.Buffer, title string, theme styles.Theme) string {
+	if title == "" {
+		title = "MakeaTUI design"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+:root { --fg: %s; --bg: %s; }
+body { margin: 0; background: var(--bg); }
+pre { margin: 0; padding: 1em; color: var(--fg); background: var(--bg);
+      font-family: "JetBrains Mono", Menlo, Consolas, monospace; line-height: 1.2; }
+</style>
+</head>
+<body>
+<pre>`, html.EscapeString(title), theme.TextPrimary, theme.Background)
+
+	for y := 0; y < buf.Height(); y++ {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		style, run := "", ""
+		flush := func() {
+			if run == "" {
+				return
+			}
+			if rules := css(compositor.ParseSGR(style)); rules != "" {
+				fmt.Fprintf(&sb, `<span style="%s">%s</span>`, rules, html.EscapeString(run))
+			} else {
+				sb.WriteString(html.EscapeString(run))
+			}
+			run = ""
+		}
+		for x := 0; x < buf.Width(); x++ {
+			c := buf.Cell(x, y)
+			if c.Width == 0 {
+				continue
+			}
+			if c.Style != style {
+				flush()
+				style = c.Style
+			}
+			run += c.Content
+		}
+		flush()
+	}
+	sb.WriteString("</pre>\n</body>\n</html>\n")
+	return sb.String()
+}
@@ -0,0 +1,183 @@
+// Package render draws a design without the designer, onto a cell grid
+// written out as ANSI text, plain text or an HTML snapshot. It shows a
+// design to tools and agents, and plain text snapshots can be diffed in
+// CI.
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/components"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/compositor"
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Format is how a rendered design is written out
+type Format string
+
+const (
+	// FormatANSI is text with the color escape sequences of the terminal
+	FormatANSI Format = "ansi"
+
+	// FormatPlain is the text alone, the same in every terminal
+	FormatPlain Format = "plain"
+
+	// FormatHTML is a page showing the text in its colors
+	FormatHTML Format = "html"
+)
+
+// Formats lists the formats, as named on the command line
+var Formats = []Format{FormatANSI, FormatPlain, FormatHTML}
+
+// ParseFormat returns the format named s
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q, want ansi, plain or html", s)
+}
+
+// Ext is the file extension for the format
+func (f Format) Ext() string {
+	switch f {
+	case FormatPlain:
+		return ".txt"
+	case FormatHTML:
+		return ".html"
+	}
+	return ".ans"
+}
+
+// Size is the size a design is rendered at: its own, or 80×24 when it
+// has none
+func Size(design *schema.Canvas) schema.Size {
+	if design.Width > 0 && design.Height > 0 {
+		return schema.Size{Width: design.Width, Height: design.Height}
+	}
+	return schema.Size{Width: 80, Height: 24}
+}
+
+// Grid draws the visible components of design, in stacking order and
+// with each container's children over it, on a grid of size cells.
+// Whatever falls outside the grid is cut off.
+func Grid(design *schema.Canvas, size schema.Size, theme styles.Theme) *compositor.Buffer {
+	buf := compositor.NewBuffer(size.Width, size.Height)
+	draw(buf, design.Components, 0, 0, theme)
+	return buf
+}
+
+func draw(buf *compositor.Buffer, comps []schema.Component, dx, dy int, theme styles.Theme) {
+	for _, comp := range schema.Stack(comps) {
+		if comp.Hidden {
+			continue
+		}
+		x, y := comp.Position.X+dx, comp.Position.Y+dy
+		buf.Draw(x, y, Component(comp, theme, State{}))
+		draw(buf, comp.Children, x, y, theme)
+	}
+}
+
+// State is how a component shows beyond its schema: picked in the
+// designer, or focused, scrolled and with an item or tab active in the
+// running app. The zero State is how it looks when its app starts.
+type State struct {
+	Selected bool // selected on the designer canvas
+	Focused  bool // has the focus in the running app
+	Cursor   int  // rune position of an input's cursor
+	Active   int  // selected list item or active tab
+	Offset   int  // first list item shown
+}
+
+// Component renders one component in state st, inside its schema size:
+// an input shows its value or placeholder, and a list the items from
+// st.Offset on that fit. A zero width or height is left to the content.
+func Component(c schema.Component, theme styles.Theme, st State) string {
+	c.Selected, c.Focused = st.Selected, st.Focused
+	return fit(render(c, theme, st), c.Size)
+}
+
+func render(c schema.Component, theme styles.Theme, st State) string {
+	switch c.Type {
+	case schema.TypeText:
+		return components.RenderText(c, theme)
+	case schema.TypeButton:
+		return components.RenderButton(c, theme)
+	case schema.TypeInput:
+		value, _ := c.Value.(string)
+		return components.RenderInput(c, theme, value, st.Cursor)
+	case schema.TypeList:
+		offset := min(max(st.Offset, 0), len(c.Items))
+		c.Items = c.Items[offset:min(offset+ListRows(c), len(c.Items))]
+		return components.RenderList(c, theme, st.Active-offset)
+	case schema.TypeTabs:
+		return components.RenderTabs(c, theme, st.Active)
+	case schema.TypeTable:
+		return components.RenderTable(c, theme)
+	case schema.TypeProgress:
+		return components.RenderProgress(c, theme)
+	case schema.TypeSpinner:
+		return components.RenderSpinner(c, theme)
+	default:
+		return components.RenderBox(c, theme)
+	}
+}
+
+// fit cuts what is drawn for a component down to its size, for content
+// that overflows it. A zero width or height is left as it is.
+func fit(s string, size schema.Size) string {
+	style := lipgloss.NewStyle()
+	if size.Width > 0 {
+		style = style.MaxWidth(size.Width)
+	}
+	if size.Height > 0 {
+		style = style.MaxHeight(size.Height)
+	}
+	return style.Render(s)
+}
+
+// ListRows is how many items a list shows at once: as many as there are
+// rows inside its border, or all of them when it has no height
+func ListRows(c schema.Component) int {
+	if c.Size.Height <= 0 {
+		return len(c.Items)
+	}
+	if c.Style.Border != nil {
+		return max(c.Size.Height-2, 1)
+	}
+	return max(c.Size.Height, 1)
+}
+
+// Render draws design at size and writes it out in format. The colors
+// of ANSI and HTML output are those lipgloss renders for the terminal,
+// so they need a color profile set when there is none.
+func Render(design *schema.Canvas, format Format, size schema.Size, theme styles.Theme) (string, error) {
+	buf := Grid(design, size, theme)
+	switch format {
+	case FormatANSI:
+		return buf.String(), nil
+	case FormatPlain:
+		return Plain(buf), nil
+	case FormatHTML:
+		return HTML(buf, design.Name, theme), nil
+	}
+	return "", fmt.Errorf("unknown format %q, want ansi, plain or html", format)
+}
+
+// Plain returns the text of buf without its styling, with the spaces
+// at the end of each line trimmed
+func Plain(buf *compositor.Buffer) string {
+	lines := make([]string, buf.Height())
+	for y := range lines {
+		var sb strings.Builder
+		for x := 0; x < buf.Width(); x++ {
+			sb.WriteString(buf.Cell(x, y).Content)
+		}
+		lines[y] = strings.TrimRight(sb.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
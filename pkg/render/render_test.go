@@ -0,0 +1,86 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/internal/ui/styles"
+	"github.com/makeatui/makeatui/pkg/schema"
+	"github.com/muesli/termenv"
+)
+
+func TestRender(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(termenv.Ascii)
+
+	design := &schema.Canvas{Name: "Demo", Components: []schema.Component{
+		{ID: "panel", Type: schema.TypeBox, Size: schema.Size{Width: 14, Height: 3},
+			Style:    schema.Style{Border: &schema.Border{Style: "normal"}},
+			Children: []schema.Component{{ID: "hi", Type: schema.TypeText, Text: "<hi>", Position: schema.Position{X: 1, Y: 1}}}},
+		{ID: "gone", Type: schema.TypeText, Text: "hidden", Hidden: true},
+		{ID: "bar", Type: schema.TypeProgress, Value: 0.5, Position: schema.Position{X: 0, Y: 3}, Size: schema.Size{Width: 6}},
+	}}
+	size := schema.Size{Width: 16, Height: 5}
+
+	plain, err := Render(design, FormatPlain, size, styles.Ultraviolet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "┌────────────┐\n│<hi>        │\n└────────────┘\n[██░░]\n"
+	if plain != want {
+		t.Errorf("plain:\n%s\nwant:\n%s", plain, want)
+	}
+
+	page, err := Render(design, FormatHTML, size, styles.Ultraviolet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"<title>Demo</title>", "&lt;hi&gt;", "color:#ffffff", "color:#9d4edd"} {
+		if !strings.Contains(page, s) {
+			t.Errorf("html is missing %q", s)
+		}
+	}
+	if strings.Contains(page, "hidden") || strings.Contains(page, "\x1b") {
+		t.Errorf("html:\n%s", page)
+	}
+
+	if _, err := ParseFormat("svg"); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+}
+
+func TestComponentStaysInSize(t *testing.T) {
+	border := &schema.Border{Style: "rounded"}
+	for _, c := range []schema.Component{
+		{Type: schema.TypeBox, Text: "hi", Size: schema.Size{Width: 10, Height: 4}, Style: schema.Style{Border: border}},
+		{Type: schema.TypeBox, Text: "a box with more text than fits in it", Size: schema.Size{Width: 10, Height: 3},
+			Style: schema.Style{Border: border, Padding: schema.Padding{Left: 1, Right: 1}, Margin: schema.Margin{Top: 1}}},
+		{Type: schema.TypeList, Items: []string{"a", "b", "c", "d", "e"}, Size: schema.Size{Width: 12, Height: 4}, Style: schema.Style{Border: border}},
+		{Type: schema.TypeInput, Placeholder: "Name", Size: schema.Size{Width: 20, Height: 3}, Style: schema.Style{Border: border}},
+		{Type: schema.TypeButton, Text: "OK", Size: schema.Size{Width: 12, Height: 3}},
+		{Type: schema.TypeTable, Items: []string{"Name", "Ada", "Alan"}, Size: schema.Size{Width: 16, Height: 5}, Style: schema.Style{Border: border}},
+	} {
+		w, h := lipgloss.Size(Component(c, styles.Ultraviolet, State{}))
+		if w != c.Size.Width || h != c.Size.Height {
+			t.Errorf("%s %q drawn %d×%d, want its size %d×%d", c.Type, c.Text, w, h, c.Size.Width, c.Size.Height)
+		}
+	}
+
+	// A bordered box stops short of the list beside it
+	design := &schema.Canvas{Components: []schema.Component{
+		{ID: "box", Type: schema.TypeBox, Size: schema.Size{Width: 20, Height: 5}, Style: schema.Style{Border: &schema.Border{Style: "normal"}}},
+		{ID: "list", Type: schema.TypeList, Items: []string{"one"}, Position: schema.Position{X: 20}, Size: schema.Size{Width: 10, Height: 1}},
+	}}
+	plain, err := Render(design, FormatPlain, schema.Size{Width: 30, Height: 7}, styles.Ultraviolet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(plain, "\n")
+	if want := "┌──────────────────┐▸  one"; lines[0] != want {
+		t.Errorf("top row %q, want %q", lines[0], want)
+	}
+	if want := "└──────────────────┘"; lines[4] != want || lines[5] != "" {
+		t.Errorf("rows 4 and 5 are %q and %q, want the bottom border at row 4", lines[4], lines[5])
+	}
+}
@@ -62,7 +62,9 @@ type Margin struct {
 	Left   int `json:"left"`
 }
 
-// Style represents styling for a component
+// Style represents styling for a component.
+// Colors are hex values ("#9D4EDD"), ANSI numbers ("212") or theme
+// tokens ("$primary", "$text-muted") resolved against Canvas.Theme.
 type Style struct {
 	Foreground string  `json:"foreground,omitempty"`
 	Background string  `json:"background,omitempty"`
@@ -93,6 +95,11 @@ type Component struct {
 	// Children for container components
 	Children []Component `json:"children,omitempty"`
 
+	// Layer - see layers.go
+	ZIndex int  `json:"z_index,omitempty"`
+	Hidden bool `json:"hidden,omitempty"`
+	Locked bool `json:"locked,omitempty"`
+
 	// State
 	Focused  bool `json:"focused,omitempty"`
 	Selected bool `json:"selected,omitempty"`
@@ -106,6 +113,11 @@ type Canvas struct {
 	Height     int         `json:"height"`
 	Components []Component `json:"components"`
 	Theme      string      `json:"theme"`
+
+	// ID generation - see ids.go
+	IDStrategy IDStrategy `json:"id_strategy,omitempty"`
+	IDSeed     int64      `json:"id_seed,omitempty"`
+	IDSeq      uint64     `json:"id_seq,omitempty"`
 }
 
 // NewComponent creates a new component with default values
@@ -128,6 +140,36 @@ func NewComponent(ctype ComponentType, name string) Component {
 	}
 }
 
+// Clone returns a deep copy of the component, so that editing the copy
+// (including its border, items and children) never changes c
+func (c Component) Clone() Component {
+	if c.Style.Border != nil {
+		border := *c.Style.Border
+		c.Style.Border = &border
+	}
+	if c.Items != nil {
+		c.Items = append([]string(nil), c.Items...)
+	}
+	if c.Children != nil {
+		children := make([]Component, len(c.Children))
+		for i, child := range c.Children {
+			children[i] = child.Clone()
+		}
+		c.Children = children
+	}
+	return c
+}
+
+// Clone returns a deep copy of the canvas and all its components
+func (c Canvas) Clone() Canvas {
+	comps := make([]Component, len(c.Components))
+	for i, comp := range c.Components {
+		comps[i] = comp.Clone()
+	}
+	c.Components = comps
+	return c
+}
+
 var idCounter uint64
 
 // generateID generates a unique ID for components
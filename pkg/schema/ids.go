@@ -0,0 +1,167 @@
+// Package schema - Component ID strategies
+package schema
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// IDStrategy selects how a canvas generates component IDs
+type IDStrategy string
+
+const (
+	// IDStrategyRandom mixes time, a counter and random bytes (the default)
+	IDStrategyRandom IDStrategy = "random"
+	// IDStrategySlug derives the ID from the component name, suffixed on collision
+	IDStrategySlug IDStrategy = "slug"
+	// IDStrategySeeded yields a deterministic sequence from Canvas.IDSeed
+	IDStrategySeeded IDStrategy = "seeded"
+)
+
+// IDFunc generates an ID for a new component on the given canvas.
+// Implementations may advance canvas state such as IDSeq.
+type IDFunc func(c *Canvas, ctype ComponentType, name string) string
+
+var (
+	idStrategies = map[IDStrategy]IDFunc{
+		IDStrategyRandom: randomID,
+		IDStrategySlug:   slugID,
+		IDStrategySeeded: seededID,
+	}
+	idStrategiesMu sync.RWMutex
+)
+
+// RegisterIDStrategy adds or replaces an ID strategy
+func RegisterIDStrategy(strategy IDStrategy, fn IDFunc) {
+	idStrategiesMu.Lock()
+	defer idStrategiesMu.Unlock()
+	idStrategies[strategy] = fn
+}
+
+// IDStrategies returns the names of all registered ID strategies
+func IDStrategies() []IDStrategy {
+	idStrategiesMu.RLock()
+	defer idStrategiesMu.RUnlock()
+	result := make([]IDStrategy, 0, len(idStrategies))
+	for s := range idStrategies {
+		result = append(result, s)
+	}
+	return result
+}
+
+// ValidateIDStrategy returns an error listing the registered strategies
+// when strategy is not one of them
+func ValidateIDStrategy(strategy IDStrategy) error {
+	idStrategiesMu.RLock()
+	_, ok := idStrategies[strategy]
+	idStrategiesMu.RUnlock()
+	if ok {
+		return nil
+	}
+	var names []string
+	for _, s := range IDStrategies() {
+		names = append(names, string(s))
+	}
+	slices.Sort(names)
+	return fmt.Errorf("unknown ID strategy %q (valid: %s)", strategy, strings.Join(names, ", "))
+}
+
+// NewID generates a component ID using the canvas's strategy.
+// Unknown or empty strategies fall back to IDStrategyRandom.
+func (c *Canvas) NewID(ctype ComponentType, name string) string {
+	idStrategiesMu.RLock()
+	fn, ok := idStrategies[c.IDStrategy]
+	idStrategiesMu.RUnlock()
+	if !ok {
+		fn = randomID
+	}
+	return fn(c, ctype, name)
+}
+
+// NewComponent creates a component with default values and an ID
+// generated by the canvas's strategy. The component is not added.
+func (c *Canvas) NewComponent(ctype ComponentType, name string) Component {
+	comp := NewComponent(ctype, name)
+	comp.ID = c.NewID(ctype, name)
+	return comp
+}
+
+// HasID reports whether any component (including children) uses id
+func (c *Canvas) HasID(id string) bool {
+	return hasID(c.Components, id)
+}
+
+func hasID(comps []Component, id string) bool {
+	for _, comp := range comps {
+		if comp.ID == id || hasID(comp.Children, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func randomID(c *Canvas, ctype ComponentType, name string) string {
+	return generateID()
+}
+
+func slugID(c *Canvas, ctype ComponentType, name string) string {
+	base := Slugify(name)
+	if base == "" {
+		base = Slugify(string(ctype))
+	}
+	if base == "" {
+		base = "comp"
+	}
+
+	id := base
+	for n := 2; c.HasID(id); n++ {
+		id = base + "-" + strconv.Itoa(n)
+	}
+	return id
+}
+
+func seededID(c *Canvas, ctype ComponentType, name string) string {
+	for {
+		c.IDSeq++
+		v := splitmix64(uint64(c.IDSeed) + c.IDSeq*0x9E3779B97F4A7C15)
+		b := make([]byte, 6)
+		for i := range b {
+			b[i] = byte(v >> (8 * i))
+		}
+		id := "comp_" + hex.EncodeToString(b)
+		if !c.HasID(id) {
+			return id
+		}
+	}
+}
+
+// splitmix64 is a small, well-distributed 64-bit mixing function
+func splitmix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// Slugify lowercases s and collapses runs of non-alphanumerics into '-'
+func Slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package schema
+
+import "testing"
+
+func TestSlugIDs(t *testing.T) {
+	c := &Canvas{IDStrategy: IDStrategySlug}
+
+	ids := []string{
+		c.NewComponent(TypeBox, "Main Header").ID,
+		c.NewComponent(TypeBox, "Main Header").ID,
+		c.NewComponent(TypeText, "").ID,
+	}
+	c.Components = append(c.Components, Component{ID: ids[0]})
+	ids = append(ids, c.NewComponent(TypeBox, "Main Header").ID)
+
+	want := []string{"main-header", "main-header", "text", "main-header-2"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("id %d: expected %q, got %q", i, want[i], ids[i])
+		}
+	}
+}
+
+func TestSeededIDsAreReproducible(t *testing.T) {
+	a := &Canvas{IDStrategy: IDStrategySeeded, IDSeed: 7}
+	b := &Canvas{IDStrategy: IDStrategySeeded, IDSeed: 7}
+
+	for i := 0; i < 5; i++ {
+		idA := a.NewID(TypeBox, "box")
+		idB := b.NewID(TypeBox, "box")
+		if idA != idB {
+			t.Fatalf("step %d: %q != %q", i, idA, idB)
+		}
+		a.Components = append(a.Components, Component{ID: idA})
+		b.Components = append(b.Components, Component{ID: idB})
+	}
+
+	other := &Canvas{IDStrategy: IDStrategySeeded, IDSeed: 8}
+	if other.NewID(TypeBox, "box") == a.Components[0].ID {
+		t.Error("different seeds should produce different IDs")
+	}
+}
+
+func TestRandomIsDefault(t *testing.T) {
+	c := &Canvas{}
+	if c.NewID(TypeBox, "x") == c.NewID(TypeBox, "x") {
+		t.Error("random IDs should differ")
+	}
+}
+
+func TestValidateIDStrategy(t *testing.T) {
+	if err := ValidateIDStrategy(IDStrategySlug); err != nil {
+		t.Errorf("slug: %v", err)
+	}
+	err := ValidateIDStrategy("sequential")
+	if err == nil || err.Error() != `unknown ID strategy "sequential" (valid: random, seeded, slug)` {
+		t.Errorf("sequential: %v", err)
+	}
+}
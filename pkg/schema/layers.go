@@ -0,0 +1,82 @@
+// Package schema - Layers and stacking order
+package schema
+
+import "sort"
+
+// Stack returns comps in stacking order, bottom first. Components are
+// stacked by ZIndex; those with the same ZIndex keep their order, so a
+// design that never sets ZIndex stacks in slice order.
+func Stack(comps []Component) []Component {
+	stacked := make([]Component, len(comps))
+	copy(stacked, comps)
+	sort.SliceStable(stacked, func(i, j int) bool { return stacked[i].ZIndex < stacked[j].ZIndex })
+	return stacked
+}
+
+// Find returns the component with the given ID, looking inside
+// containers too, or nil
+func (c *Canvas) Find(id string) *Component {
+	return find(c.Components, id)
+}
+
+func find(comps []Component, id string) *Component {
+	for i := range comps {
+		if comps[i].ID == id {
+			return &comps[i]
+		}
+		if found := find(comps[i].Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Siblings returns the list holding the component with the given ID:
+// the canvas's components or a container's children
+func (c *Canvas) Siblings(id string) *[]Component {
+	return siblings(&c.Components, id)
+}
+
+func siblings(comps *[]Component, id string) *[]Component {
+	for i := range *comps {
+		if (*comps)[i].ID == id {
+			return comps
+		}
+		if found := siblings(&(*comps)[i].Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Restack moves the component with the given ID by steps places up the
+// stack of its siblings, or down for negative steps. The siblings are
+// left in stacking order, and the component takes a ZIndex between
+// its new neighbours'. It reports false when there is no such
+// component.
+func (c *Canvas) Restack(id string, steps int) bool {
+	list := c.Siblings(id)
+	if list == nil {
+		return false
+	}
+	comps := Stack(*list)
+	from := 0
+	for i := range comps {
+		if comps[i].ID == id {
+			from = i
+		}
+	}
+	to := min(max(from+steps, 0), len(comps)-1)
+
+	comp := comps[from]
+	comps = append(comps[:from], comps[from+1:]...)
+	comps = append(comps[:to], append([]Component{comp}, comps[to:]...)...)
+	if to > 0 {
+		comps[to].ZIndex = max(comps[to].ZIndex, comps[to-1].ZIndex)
+	}
+	if to < len(comps)-1 {
+		comps[to].ZIndex = min(comps[to].ZIndex, comps[to+1].ZIndex)
+	}
+	*list = comps
+	return true
+}
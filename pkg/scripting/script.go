@@ -17,6 +17,10 @@ type Script struct {
 	Description string    `json:"description"`
 	Version     string    `json:"version"`
 	Steps       []Step    `json:"steps"`
+
+	// Optional ID generation settings for reproducible output
+	IDStrategy schema.IDStrategy `json:"id_strategy,omitempty"`
+	IDSeed     *int64            `json:"id_seed,omitempty"`
 }
 
 // Step represents a single script step
@@ -62,6 +66,11 @@ func (e *ScriptEngine) SetVerbose(verbose bool) {
 	e.verbose = verbose
 }
 
+// SetIDStrategy sets how component IDs are generated
+func (e *ScriptEngine) SetIDStrategy(strategy schema.IDStrategy, seed int64) {
+	e.api.SetIDStrategy(strategy, seed)
+}
+
 // SetVar sets a variable
 func (e *ScriptEngine) SetVar(name string, value any) {
 	e.vars[name] = value
@@ -88,6 +97,22 @@ func (e *ScriptEngine) Execute(script *Script) error {
 		fmt.Printf("🚀 Executing script: %s\n", script.Name)
 	}
 
+	if script.IDStrategy != "" {
+		if err := schema.ValidateIDStrategy(script.IDStrategy); err != nil {
+			return err
+		}
+	}
+	if script.IDSeed != nil && script.IDStrategy != schema.IDStrategySeeded {
+		return fmt.Errorf("id_seed needs the seeded ID strategy")
+	}
+	if script.IDStrategy != "" {
+		var seed int64
+		if script.IDSeed != nil {
+			seed = *script.IDSeed
+		}
+		e.SetIDStrategy(script.IDStrategy, seed)
+	}
+
 	for i, step := range script.Steps {
 		if e.verbose {
 			fmt.Printf("  Step %d: %s\n", i+1, step.Action)
@@ -122,6 +147,23 @@ func (e *ScriptEngine) executeStep(step Step) error {
 		return e.addComponent(schema.TypeList, step)
 	case "add_progress":
 		return e.addComponent(schema.TypeProgress, step)
+	case "set_id_strategy":
+		strategy := schema.IDStrategy(getString(step.Properties, "strategy", string(schema.IDStrategyRandom)))
+		if err := schema.ValidateIDStrategy(strategy); err != nil {
+			return err
+		}
+		seed, ok := step.Properties["seed"]
+		if !ok {
+			seed = 0.0
+		}
+		n, isNumber := seed.(float64)
+		if !isNumber || n != float64(int64(n)) {
+			return fmt.Errorf("set_id_strategy: seed must be an integer, got %v", seed)
+		}
+		if ok && strategy != schema.IDStrategySeeded {
+			return fmt.Errorf("set_id_strategy: seed needs the seeded strategy")
+		}
+		e.SetIDStrategy(strategy, int64(n))
 	case "set_theme":
 		if theme, ok := step.Properties["theme"].(string); ok {
 			e.api.SetTheme(theme)
@@ -0,0 +1,31 @@
+package scripting
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestScriptIDStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		script string
+		err    string
+	}{
+		{`{"id_strategy": "seeded", "id_seed": 7}`, ""},
+		{`{"id_strategy": "slug"}`, ""},
+		{`{"id_strategy": "sequential"}`, "unknown ID strategy"},
+		{`{"id_strategy": "slug", "id_seed": 7}`, "needs the seeded"},
+		{`{"id_seed": 7}`, "needs the seeded"},
+		{`{"steps": [{"action": "set_id_strategy", "properties": {"strategy": "random", "seed": 3}}]}`, "needs the seeded"},
+		{`{"steps": [{"action": "set_id_strategy", "properties": {"strategy": "seeded", "seed": 1.5}}]}`, "must be an integer"},
+	} {
+		var script Script
+		if err := json.Unmarshal([]byte(tc.script), &script); err != nil {
+			t.Fatal(err)
+		}
+		err := NewScriptEngine("test").Execute(&script)
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: error %v, want %q", tc.script, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package templates - Fitting a template into part of a design
+package templates
+
+import (
+	"math"
+
+	"github.com/makeatui/makeatui/pkg/schema"
+)
+
+// Scaled returns copies of the template's components fitted into the
+// rectangle at x, y of the given size. Positions and sizes are scaled
+// edge by edge, so components that touched still touch; text sized to
+// its content keeps its automatic size.
+func (t *Template) Scaled(x, y, width, height int) []schema.Component {
+	comps := make([]schema.Component, len(t.Canvas.Components))
+	if len(comps) == 0 {
+		return comps
+	}
+
+	x0, y0 := math.MaxInt, math.MaxInt
+	x1, y1 := math.MinInt, math.MinInt
+	for _, c := range t.Canvas.Components {
+		x0, y0 = min(x0, c.Position.X), min(y0, c.Position.Y)
+		x1, y1 = max(x1, c.Position.X+max(c.Size.Width, 1)), max(y1, c.Position.Y+max(c.Size.Height, 1))
+	}
+	sx := float64(width) / float64(max(x1-x0, 1))
+	sy := float64(height) / float64(max(y1-y0, 1))
+
+	for i, c := range t.Canvas.Components {
+		c = c.Clone()
+		c.Position.X -= x0
+		c.Position.Y -= y0
+		scale(&c, sx, sy)
+		c.Position.X += x
+		c.Position.Y += y
+		comps[i] = c
+	}
+	return comps
+}
+
+// scale scales c and its children, which are placed relative to it, by
+// sx across and sy down
+func scale(c *schema.Component, sx, sy float64) {
+	left, top := round(float64(c.Position.X)*sx), round(float64(c.Position.Y)*sy)
+	if c.Size.Width > 0 {
+		c.Size.Width = max(round(float64(c.Position.X+c.Size.Width)*sx)-left, 1)
+	}
+	if c.Size.Height > 0 {
+		c.Size.Height = max(round(float64(c.Position.Y+c.Size.Height)*sy)-top, 1)
+	}
+	c.Position.X, c.Position.Y = left, top
+	for i := range c.Children {
+		scale(&c.Children[i], sx, sy)
+	}
+}
+
+func round(v float64) int {
+	return int(math.Round(v))
+}
@@ -0,0 +1,27 @@
+package templates
+
+import "testing"
+
+func TestScaled(t *testing.T) {
+	tpl := DashboardBasic()
+	comps := tpl.Scaled(10, 5, 40, 11)
+
+	want := map[string][4]int{
+		"header":  {10, 5, 40, 2},
+		"sidebar": {10, 7, 10, 9},
+		"main":    {21, 7, 29, 9},
+	}
+	for _, c := range comps {
+		w, ok := want[c.ID]
+		if !ok {
+			continue
+		}
+		got := [4]int{c.Position.X, c.Position.Y, c.Size.Width, c.Size.Height}
+		if got != w {
+			t.Errorf("%s: got x, y, w, h %v, want %v", c.ID, got, w)
+		}
+	}
+	if tpl.Canvas.Components[0].Size.Width != 80 {
+		t.Error("scaling changed the template")
+	}
+}
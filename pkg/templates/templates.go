@@ -29,6 +29,12 @@ const (
 	CategorySettings   TemplateCategory = "settings"
 )
 
+// Categories lists the template categories in the order to show them
+var Categories = []TemplateCategory{
+	CategoryDashboard, CategoryForm, CategoryList, CategoryWizard,
+	CategoryMonitor, CategoryChat, CategoryEditor, CategorySettings,
+}
+
 // TemplateEngine manages and applies templates
 type TemplateEngine struct {
 	templates map[string]*Template
@@ -109,18 +115,27 @@ type TemplateBuilder struct {
 	api      *agent.API
 }
 
-// NewTemplateBuilder starts building a template
+// NewTemplateBuilder starts building a template.
+// Component IDs are slugs of their names so templates are reproducible.
 func NewTemplateBuilder(name, description string, category TemplateCategory) *TemplateBuilder {
+	api := agent.NewAPI(name)
+	api.SetIDStrategy(schema.IDStrategySlug, 0)
 	return &TemplateBuilder{
 		template: &Template{
 			Name:        name,
 			Description: description,
 			Category:    category,
 		},
-		api: agent.NewAPI(name),
+		api: api,
 	}
 }
 
+// WithIDStrategy changes how IDs are generated for components added afterwards
+func (b *TemplateBuilder) WithIDStrategy(strategy schema.IDStrategy, seed int64) *TemplateBuilder {
+	b.api.SetIDStrategy(strategy, seed)
+	return b
+}
+
 // AddBox adds a box to the template
 func (b *TemplateBuilder) AddBox(name, title string, x, y, w, h int) *TemplateBuilder {
 	b.api.AddBox(name, title, x, y, w, h)
@@ -39,7 +39,7 @@ type FileManager struct {
 	SelectedSet  map[string]bool
 	SortBy       FileSortBy
 	SortReverse  bool
-	Filter       string
+	Filter       string // glob matched against file names; directories are always shown
 	Focused      bool
 	Clipboard    []string
 	ClipboardOp  ClipboardOp
@@ -181,6 +181,12 @@ func (fm *FileManager) readDirectory(path string) ([]FileEntry, error) {
 			continue
 		}
 
+		if fm.Filter != "" && !entry.IsDir() {
+			if ok, _ := filepath.Match(fm.Filter, entry.Name()); !ok {
+				continue
+			}
+		}
+
 		info, err := entry.Info()
 		if err != nil {
 			continue
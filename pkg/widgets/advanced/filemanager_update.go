@@ -171,7 +171,7 @@ func (fm *FileManager) SetDirectory(path string) error {
 
 	// Add to history
 	if fm.CurrentDir != "" && fm.CurrentDir != absPath {
-		fm.History = append(fm.History[:fm.HistoryIdx+1], fm.CurrentDir)
+		fm.History = append(fm.History[:min(fm.HistoryIdx+1, len(fm.History))], fm.CurrentDir)
 		fm.HistoryIdx = len(fm.History) - 1
 	}
 
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
 )
 
 // WindowManager manages multiple windows
@@ -174,48 +175,19 @@ func (wm *WindowManager) Update(msg tea.Msg) (*WindowManager, tea.Cmd) {
 	return wm, tea.Batch(cmds...)
 }
 
-// View renders all windows
+// View renders all windows over the background, in z-order
 func (wm *WindowManager) View() string {
-	// Create background canvas
-	canvas := make([][]rune, wm.Height)
-	for y := range canvas {
-		canvas[y] = make([]rune, wm.Width)
-		for x := range canvas[y] {
-			canvas[y][x] = ' '
-		}
+	buf := compositor.NewBuffer(wm.Width, wm.Height)
+	background := wm.style.Background.Render(strings.Repeat(" ", wm.Width))
+	for y := 0; y < wm.Height; y++ {
+		buf.Draw(0, y, background)
 	}
 
-	// Render each window onto canvas (in z-order)
 	for _, w := range wm.Windows {
-		if !w.Visible {
-			continue
-		}
-
-		windowView := w.View()
-		windowLines := strings.Split(windowView, "\n")
-
-		for dy, line := range windowLines {
-			y := w.Y + dy
-			if y < 0 || y >= wm.Height {
-				continue
-			}
-
-			for dx, ch := range line {
-				x := w.X + dx
-				if x < 0 || x >= wm.Width {
-					continue
-				}
-				canvas[y][x] = ch
-			}
+		if w.Visible {
+			buf.Draw(w.X, w.Y, w.View())
 		}
 	}
 
-	// Convert canvas to string
-	var lines []string
-	for _, row := range canvas {
-		lines = append(lines, string(row))
-	}
-
-	return wm.style.Background.Render(strings.Join(lines, "\n"))
+	return buf.String()
 }
-
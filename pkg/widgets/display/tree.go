@@ -193,6 +193,30 @@ func (t *Tree) GetSelectedNode() *TreeNode {
 	return nil
 }
 
+// VisibleNodes returns the nodes shown, in order, one per line
+func (t *Tree) VisibleNodes() []*TreeNode {
+	return t.flatNodes
+}
+
+// SetCursor moves the cursor to the visible node at index
+func (t *Tree) SetCursor(index int) {
+	if index >= 0 && index < len(t.flatNodes) {
+		t.cursor = index
+	}
+}
+
+// SelectID moves the cursor to the visible node with the given ID,
+// reporting false when there is none
+func (t *Tree) SelectID(id string) bool {
+	for i, node := range t.flatNodes {
+		if node.ID == id {
+			t.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
 // ExpandAll expands all nodes
 func (t *Tree) ExpandAll() *Tree {
 	if t.Root != nil {
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
 )
 
 // TooltipPosition defines tooltip position
@@ -114,6 +115,32 @@ func (t *Tooltip) View() string {
 	return content
 }
 
+// Overlay draws the tooltip over base so that its arrow points at the
+// coordinates given to Show, shifted as needed to stay inside base
+func (t *Tooltip) Overlay(base string) string {
+	if !t.Visible {
+		return base
+	}
+	view := t.View()
+	w, h := lipgloss.Size(view)
+
+	x, y := t.X-w/2, t.Y-h
+	switch t.Position {
+	case TooltipBottom:
+		y = t.Y + 1
+	case TooltipLeft:
+		x, y = t.X-w, t.Y-h/2
+	case TooltipRight:
+		x, y = t.X+1, t.Y-h/2
+	}
+
+	buf := compositor.Parse(base)
+	x = max(0, min(x, buf.Width()-w))
+	y = max(0, min(y, buf.Height()-h))
+	buf.Draw(x, y, view)
+	return buf.String()
+}
+
 // Popover provides a rich popover/dropdown
 type Popover struct {
 	ID        string
@@ -227,6 +254,15 @@ func (p *Popover) View() string {
 	return p.style.Container.Width(p.Width).Render(inner)
 }
 
+// Overlay draws the popover over base with its top-left corner at the
+// coordinates given to Show
+func (p *Popover) Overlay(base string) string {
+	if !p.Visible {
+		return base
+	}
+	return compositor.Overlay(base, p.View(), p.X, p.Y)
+}
+
 // Badge provides a small label/badge
 type Badge struct {
 	Label string
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/makeatui/makeatui/pkg/compositor"
 )
 
 // Stack implements a z-index layered stack (like Textual's layers)
@@ -43,55 +44,20 @@ func (s *Stack) AddLayer(content string, x, y, z int) *Stack {
 	return s
 }
 
-// Render composites all layers
+// Render composites all layers in z-order, clipped to the stack size
 func (s *Stack) Render() string {
-	// Create base canvas
-	canvas := make([][]rune, s.height)
-	for i := range canvas {
-		canvas[i] = []rune(strings.Repeat(" ", s.width))
-	}
-
-	// Sort layers by Z (simple bubble sort)
-	sorted := make([]Layer, len(s.layers))
-	copy(sorted, s.layers)
-	for i := 0; i < len(sorted)-1; i++ {
-		for j := 0; j < len(sorted)-i-1; j++ {
-			if sorted[j].Z > sorted[j+1].Z {
-				sorted[j], sorted[j+1] = sorted[j+1], sorted[j]
-			}
-		}
-	}
-
-	// Render layers from bottom to top
-	for _, layer := range sorted {
-		if !layer.Visible {
-			continue
-		}
-		lines := strings.Split(layer.Content, "\n")
-		for ly, line := range lines {
-			y := layer.Y + ly
-			if y < 0 || y >= s.height {
-				continue
-			}
-			for lx, ch := range line {
-				x := layer.X + lx
-				if x < 0 || x >= s.width {
-					continue
-				}
-				canvas[y][x] = ch
-			}
-		}
-	}
-
-	// Convert back to string
-	var result strings.Builder
-	for i, row := range canvas {
-		result.WriteString(string(row))
-		if i < len(canvas)-1 {
-			result.WriteRune('\n')
+	var layers []compositor.Layer
+	for _, layer := range s.layers {
+		if layer.Visible {
+			layers = append(layers, compositor.Layer{
+				Content: layer.Content,
+				X:       layer.X,
+				Y:       layer.Y,
+				Z:       layer.Z,
+			})
 		}
 	}
-	return result.String()
+	return compositor.NewBuffer(s.width, s.height).Compose(layers...).String()
 }
 
 // VStack is a vertical stack (simpler API)
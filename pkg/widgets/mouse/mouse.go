@@ -15,6 +15,8 @@ type Zone struct {
 	OnHover func() tea.Cmd
 	OnDrag  func(dx, dy int) tea.Cmd
 	Data    interface{} // arbitrary data
+	Z       int         // stacking order; HitTest prefers the highest
+	Cursor  CursorType  // cursor suggested while hovering the zone
 }
 
 // ZoneManager tracks interactive zones
@@ -59,15 +61,18 @@ func (zm *ZoneManager) Update(id string, x, y, width, height int) {
 	}
 }
 
-// HitTest returns the zone at the given coordinates
+// HitTest returns the topmost zone at the given coordinates
 func (zm *ZoneManager) HitTest(x, y int) *Zone {
+	var hit *Zone
 	for _, zone := range zm.zones {
 		if x >= zone.X && x < zone.X+zone.Width &&
 			y >= zone.Y && y < zone.Y+zone.Height {
-			return zone
+			if hit == nil || zone.Z > hit.Z || (zone.Z == hit.Z && zone.ID < hit.ID) {
+				hit = zone
+			}
 		}
 	}
-	return nil
+	return hit
 }
 
 // HandleMouse processes a mouse event and returns appropriate commands
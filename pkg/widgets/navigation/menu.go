@@ -130,10 +130,12 @@ func (m *Menu) GetSelectedItem() *MenuItem {
 	return nil
 }
 
-// findNextSelectable finds next non-separator, non-disabled item
+// findNextSelectable finds next non-separator, non-disabled item. start
+// may be -1 when nothing is selected yet.
 func (m *Menu) findNextSelectable(start, direction int) int {
-	for i := 0; i < len(m.Items); i++ {
-		idx := (start + direction*(i+1) + len(m.Items)) % len(m.Items)
+	n := len(m.Items)
+	for i := 0; i < n; i++ {
+		idx := ((start+direction*(i+1))%n + n) % n
 		item := m.Items[idx]
 		if !item.Separator && !item.Disabled {
 			return idx
@@ -182,6 +184,23 @@ func (m *Menu) Update(msg tea.Msg) (*Menu, tea.Cmd) {
 				m.Open = false
 			}
 		}
+
+	case tea.MouseMsg:
+		// Coordinates are relative to the menu's top-left corner.
+		// Only vertical menus take the mouse.
+		if m.Horizontal {
+			break
+		}
+		idx := msg.Y - 1 // top border
+		if idx < 0 || idx >= len(m.Items) || m.Items[idx].Separator || m.Items[idx].Disabled {
+			break
+		}
+		m.Selected = idx
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			if item := m.Items[idx]; item.Action != nil {
+				return m, item.Action()
+			}
+		}
 	}
 
 	return m, nil
@@ -26,11 +26,19 @@ func (cp *CommandPalette) Update(msg tea.Msg) (*CommandPalette, tea.Cmd) {
 			if cp.Selected < 0 {
 				cp.Selected = len(cp.Filtered) - 1
 			}
+			cp.scroll()
 		case "down", "ctrl+n":
 			cp.Selected++
 			if cp.Selected >= len(cp.Filtered) {
 				cp.Selected = 0
 			}
+			cp.scroll()
+		case "pgup":
+			cp.Selected = max(cp.Selected-cp.MaxResults, 0)
+			cp.scroll()
+		case "pgdown":
+			cp.Selected = max(min(cp.Selected+cp.MaxResults, len(cp.Filtered)-1), 0)
+			cp.scroll()
 		case "backspace":
 			if len(cp.Query) > 0 {
 				cp.Query = cp.Query[:len(cp.Query)-1]
@@ -69,7 +77,12 @@ func (cp *CommandPalette) View() string {
 	if len(cp.Filtered) == 0 {
 		parts = append(parts, cp.style.Empty.Render("No commands found"))
 	} else {
-		for i, cmd := range cp.Filtered {
+		end := len(cp.Filtered)
+		if cp.MaxResults > 0 {
+			end = min(cp.offset+cp.MaxResults, end)
+		}
+		for i := cp.offset; i < end; i++ {
+			cmd := cp.Filtered[i]
 			style := cp.style.Item
 			if i == cp.Selected {
 				style = cp.style.ItemSel
@@ -101,6 +114,15 @@ func (cp *CommandPalette) View() string {
 				itemParts = append(itemParts, " "+cp.style.Shortcut.Render("["+cmd.Shortcut+"]"))
 			}
 
+			// Recently used
+			if i < cp.recent {
+				itemParts = append(itemParts, " "+cp.style.Recent.Render("· recent"))
+			}
+
+			// One line per command, cut off at the edge of the palette
+			if w := cp.style.Container.GetWidth(); w > 0 {
+				style = style.MaxWidth(w - cp.style.Container.GetHorizontalPadding())
+			}
 			itemView := style.Render(strings.Join(itemParts, ""))
 			parts = append(parts, itemView)
 		}
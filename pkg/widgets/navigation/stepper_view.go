@@ -2,6 +2,7 @@
 package navigation
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
@@ -73,7 +74,11 @@ type CommandPalette struct {
 	Visible     bool
 	MaxResults  int
 	Placeholder string
+	Recent      []string // IDs of the commands last executed, most recent first
+	MaxRecent   int
 	style       CommandPaletteStyle
+	offset      int // first result shown
+	recent      int // how many of Filtered are recent commands
 }
 
 // Command represents a palette command
@@ -98,6 +103,7 @@ type CommandPaletteStyle struct {
 	Description lipgloss.Style
 	Category    lipgloss.Style
 	Shortcut    lipgloss.Style
+	Recent      lipgloss.Style
 	Empty       lipgloss.Style
 }
 
@@ -132,6 +138,8 @@ func DefaultCommandPaletteStyle() CommandPaletteStyle {
 			Foreground(lipgloss.Color("#7B2CBF")),
 		Shortcut: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#E040FB")),
+		Recent: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#666666")),
 		Empty: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#666666")).
 			Italic(true).
@@ -146,11 +154,18 @@ func NewCommandPalette(id string) *CommandPalette {
 		Commands:    []Command{},
 		Filtered:    []Command{},
 		MaxResults:  10,
+		MaxRecent:   5,
 		Placeholder: "Type a command...",
 		style:       DefaultCommandPaletteStyle(),
 	}
 }
 
+// SetStyle sets the palette styling
+func (cp *CommandPalette) SetStyle(style CommandPaletteStyle) *CommandPalette {
+	cp.style = style
+	return cp
+}
+
 // AddCommand adds a command
 func (cp *CommandPalette) AddCommand(id, label, desc, category, shortcut, icon string, action func() tea.Cmd) *CommandPalette {
 	cp.Commands = append(cp.Commands, Command{
@@ -188,10 +203,26 @@ func (cp *CommandPalette) Toggle() *CommandPalette {
 	return cp.Show()
 }
 
-// filter filters commands based on query
+// filter filters commands based on query. Without one, the recent
+// commands come first.
 func (cp *CommandPalette) filter() {
+	cp.offset, cp.recent = 0, 0
 	if cp.Query == "" {
-		cp.Filtered = cp.Commands
+		cp.Filtered = []Command{}
+		for _, id := range cp.Recent {
+			for _, cmd := range cp.Commands {
+				if cmd.ID == id {
+					cp.Filtered = append(cp.Filtered, cmd)
+					break
+				}
+			}
+		}
+		cp.recent = len(cp.Filtered)
+		for _, cmd := range cp.Commands {
+			if !slices.Contains(cp.Recent, cmd.ID) {
+				cp.Filtered = append(cp.Filtered, cmd)
+			}
+		}
 		return
 	}
 
@@ -200,21 +231,29 @@ func (cp *CommandPalette) filter() {
 
 	for _, cmd := range cp.Commands {
 		score := fuzzyScore(strings.ToLower(cmd.Label), query)
+		if score == 0 && cmd.Category != "" {
+			// Matching the category as well ranks below the label alone
+			score = fuzzyScore(strings.ToLower(cmd.Category+" "+cmd.Label), query) / 2
+		}
 		if score > 0 {
 			cmd.Score = score
 			cp.Filtered = append(cp.Filtered, cmd)
 		}
 	}
 
-	// Sort by score
-	sort.Slice(cp.Filtered, func(i, j int) bool {
+	// Sort by score, keeping the order commands were added in for ties
+	sort.SliceStable(cp.Filtered, func(i, j int) bool {
 		return cp.Filtered[i].Score > cp.Filtered[j].Score
 	})
+}
 
-	// Limit results
-	if len(cp.Filtered) > cp.MaxResults {
-		cp.Filtered = cp.Filtered[:cp.MaxResults]
+// scroll keeps the selected command among the results shown
+func (cp *CommandPalette) scroll() {
+	if cp.MaxResults <= 0 {
+		return
 	}
+	cp.offset = min(cp.offset, cp.Selected)
+	cp.offset = max(cp.offset, cp.Selected-cp.MaxResults+1, 0)
 }
 
 // fuzzyScore calculates a simple fuzzy match score
@@ -242,6 +281,11 @@ func (cp *CommandPalette) Execute() tea.Cmd {
 	if cp.Selected >= 0 && cp.Selected < len(cp.Filtered) {
 		cmd := cp.Filtered[cp.Selected]
 		cp.Hide()
+		cp.Recent = slices.DeleteFunc(cp.Recent, func(id string) bool { return id == cmd.ID })
+		cp.Recent = append([]string{cmd.ID}, cp.Recent...)
+		if len(cp.Recent) > cp.MaxRecent {
+			cp.Recent = cp.Recent[:cp.MaxRecent]
+		}
 		if cmd.Action != nil {
 			return cmd.Action()
 		}
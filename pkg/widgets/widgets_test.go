@@ -4,6 +4,8 @@ package widgets
 import (
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/makeatui/makeatui/pkg/widgets/advanced"
 	"github.com/makeatui/makeatui/pkg/widgets/display"
 	"github.com/makeatui/makeatui/pkg/widgets/effects"
@@ -197,6 +199,43 @@ func TestMenu(t *testing.T) {
 	if len(m.Items) != 2 {
 		t.Errorf("Expected 2 items, got %d", len(m.Items))
 	}
+
+	// With nothing selected and nothing to select, moving stays put
+	for _, item := range m.Items {
+		item.Disabled = true
+	}
+	m.Selected = -1
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyUp}, {Type: tea.KeyDown}, {Type: tea.KeyRunes, Runes: []rune("k")}} {
+		m.Update(key)
+	}
+	if m.Selected != -1 {
+		t.Errorf("Expected no selection, got %d", m.Selected)
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	cp := navigation.NewCommandPalette("test-palette")
+	cp.AddCommand("save", "Save", "", "File", "ctrl+s", "", nil)
+	cp.AddCommand("left", "Align left", "", "Arrange", "", "", nil)
+	cp.AddCommand("lint", "Run lint", "", "View", "", "", nil)
+
+	// The category matches too, after the labels
+	cp.Show()
+	for _, r := range "arr" {
+		cp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if len(cp.Filtered) != 1 || cp.Filtered[0].ID != "left" {
+		t.Errorf("Expected arr to find Align left, got %v", cp.Filtered)
+	}
+
+	// The last command run comes first next time
+	cp.Show()
+	cp.Selected = 2
+	cp.Execute()
+	cp.Show()
+	if cp.Filtered[0].ID != "lint" || len(cp.Filtered) != 3 {
+		t.Errorf("Expected the recent command first, got %v", cp.Filtered)
+	}
 }
 
 func TestBreadcrumb(t *testing.T) {